@@ -4,10 +4,12 @@ go 1.22.2
 
 require (
 	github.com/hashicorp/terraform-plugin-framework v1.12.0
+	github.com/hashicorp/terraform-plugin-framework-timeouts v0.4.1
 	github.com/hashicorp/terraform-plugin-framework-validators v0.13.0
 	github.com/hashicorp/terraform-plugin-go v0.24.0
 	github.com/hashicorp/terraform-plugin-log v0.9.0
 	github.com/hashicorp/terraform-plugin-testing v1.10.0
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/sftpgo/sdk v0.1.9-0.20241011171103-64fc18a344f9
 	github.com/stretchr/testify v1.9.0
 )
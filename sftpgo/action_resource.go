@@ -16,11 +16,16 @@ package sftpgo
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -28,6 +33,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 
@@ -36,11 +42,23 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &actionResource{}
-	_ resource.ResourceWithConfigure   = &actionResource{}
-	_ resource.ResourceWithImportState = &actionResource{}
+	_ resource.Resource                   = &actionResource{}
+	_ resource.ResourceWithConfigure      = &actionResource{}
+	_ resource.ResourceWithImportState    = &actionResource{}
+	_ resource.ResourceWithValidateConfig = &actionResource{}
 )
 
+// fsActionConfigAttributes maps the filesystem action types to the
+// fs_config attribute that defines their configuration.
+var fsActionConfigAttributes = map[int64]string{
+	1: "renames",
+	2: "deletes",
+	3: "mkdirs",
+	4: "exist",
+	5: "compress",
+	6: "copy",
+}
+
 // NewActionResource is a helper function to simplify the provider implementation.
 func NewActionResource() resource.Resource {
 	return &actionResource{}
@@ -57,7 +75,7 @@ func (r *actionResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	r.client = req.ProviderData.(*client.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -66,7 +84,7 @@ func (r *actionResource) Metadata(_ context.Context, req resource.MetadataReques
 }
 
 // Schema defines the schema for the resource.
-func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *actionResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Event action",
 		Attributes: map[string]schema.Attribute{
@@ -165,7 +183,7 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 							},
 							"parts": schema.ListNestedAttribute{
 								Optional:    true,
-								Description: `Multipart requests allow to combine one or more sets of data into a single body. For each part, you can set a file path or a body as text. Placeholders are supported in file path, body, header values.`,
+								Description: `Multipart requests allow to combine one or more sets of data into a single body. For each part, you must set either a file path or a body as text. Placeholders are supported in file path, body, header values. Only supported with the POST and PUT methods.`,
 								NestedObject: schema.NestedAttributeObject{
 									Attributes: map[string]schema.Attribute{
 										"name": schema.StringAttribute{
@@ -241,6 +259,7 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 								Required:    true,
 								Validators: []validator.List{
 									listvalidator.UniqueValues(),
+									listvalidator.ValueStringsAre(emailValidator{}),
 								},
 							},
 							"bcc": schema.ListAttribute{
@@ -248,6 +267,7 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 								Optional:    true,
 								Validators: []validator.List{
 									listvalidator.UniqueValues(),
+									listvalidator.ValueStringsAre(emailValidator{}),
 								},
 							},
 							"subject": schema.StringAttribute{
@@ -284,7 +304,10 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 									Attributes: map[string]schema.Attribute{
 										"path": schema.StringAttribute{
 											Required:    true,
-											Description: "Path for which to apply the retention rules.",
+											Description: "Absolute virtual path for which to apply the retention rules.",
+											Validators: []validator.String{
+												absoluteVirtualPathValidator{},
+											},
 										},
 										"retention": schema.Int64Attribute{
 											Required:    true,
@@ -320,9 +343,15 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 									Attributes: map[string]schema.Attribute{
 										"key": schema.StringAttribute{
 											Required: true,
+											Validators: []validator.String{
+												absoluteVirtualPathValidator{allowPlaceholders: true},
+											},
 										},
 										"value": schema.StringAttribute{
 											Required: true,
+											Validators: []validator.String{
+												absoluteVirtualPathValidator{allowPlaceholders: true},
+											},
 										},
 										"update_modtime": schema.BoolAttribute{
 											Optional:    true,
@@ -337,6 +366,7 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 								Description: "Directories paths to create.",
 								Validators: []validator.List{
 									listvalidator.UniqueValues(),
+									listvalidator.ValueStringsAre(absoluteVirtualPathValidator{allowPlaceholders: true}),
 								},
 							},
 							"deletes": schema.ListAttribute{
@@ -345,6 +375,7 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 								Description: "Paths to delete.",
 								Validators: []validator.List{
 									listvalidator.UniqueValues(),
+									listvalidator.ValueStringsAre(absoluteVirtualPathValidator{allowPlaceholders: true}),
 								},
 							},
 							"exist": schema.ListAttribute{
@@ -353,6 +384,7 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 								Description: "Paths to check for existence.",
 								Validators: []validator.List{
 									listvalidator.UniqueValues(),
+									listvalidator.ValueStringsAre(absoluteVirtualPathValidator{allowPlaceholders: true}),
 								},
 							},
 							"copy": schema.ListNestedAttribute{
@@ -362,9 +394,15 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 									Attributes: map[string]schema.Attribute{
 										"key": schema.StringAttribute{
 											Required: true,
+											Validators: []validator.String{
+												absoluteVirtualPathValidator{allowPlaceholders: true},
+											},
 										},
 										"value": schema.StringAttribute{
 											Required: true,
+											Validators: []validator.String{
+												absoluteVirtualPathValidator{allowPlaceholders: true},
+											},
 										},
 									},
 								},
@@ -376,6 +414,9 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 									"name": schema.StringAttribute{
 										Required:    true,
 										Description: `Full path to the zip file.`,
+										Validators: []validator.String{
+											absoluteVirtualPathValidator{allowPlaceholders: true},
+										},
 									},
 									"paths": schema.ListAttribute{
 										ElementType: types.StringType,
@@ -383,6 +424,7 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 										Description: "Paths to include in the compressed archive.",
 										Validators: []validator.List{
 											listvalidator.UniqueValues(),
+											listvalidator.ValueStringsAre(absoluteVirtualPathValidator{allowPlaceholders: true}),
 										},
 									},
 								},
@@ -446,18 +488,162 @@ func (r *actionResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig validates the resource configuration.
+func (r *actionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validateFsActionConfig(ctx, req.Config)...)
+	resp.Diagnostics.Append(validateHTTPActionParts(ctx, req.Config)...)
+}
+
+// validateFsActionConfig checks that only the fs_config attribute matching
+// the filesystem action type is set.
+func validateFsActionConfig(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	fsConfigPath := path.Root("options").AtName("fs_config")
+	var fsType types.Int64
+	diags := config.GetAttribute(ctx, fsConfigPath.AtName("type"), &fsType)
+	if diags.HasError() {
+		return diags
+	}
+	if fsType.IsNull() || fsType.IsUnknown() {
+		return diags
+	}
+	expected, ok := fsActionConfigAttributes[fsType.ValueInt64()]
+	if !ok {
+		// already reported by the type validator
+		return diags
+	}
+
+	// sorted by action type, so the errors are reported in a stable order
+	actionTypes := make([]int64, 0, len(fsActionConfigAttributes))
+	for actionType := range fsActionConfigAttributes {
+		actionTypes = append(actionTypes, actionType)
+	}
+	slices.Sort(actionTypes)
+	for _, actionType := range actionTypes {
+		name := fsActionConfigAttributes[actionType]
+		var val attr.Value
+		attrDiags := config.GetAttribute(ctx, fsConfigPath.AtName(name), &val)
+		diags.Append(attrDiags...)
+		if attrDiags.HasError() {
+			return diags
+		}
+		if name == expected {
+			if val.IsNull() {
+				diags.AddAttributeError(
+					fsConfigPath.AtName(name),
+					"Missing Filesystem Action Configuration",
+					fmt.Sprintf("%q is required for filesystem action type %d.", name, fsType.ValueInt64()),
+				)
+			}
+			continue
+		}
+		if !val.IsNull() {
+			diags.AddAttributeError(
+				fsConfigPath.AtName(name),
+				"Invalid Filesystem Action Configuration",
+				fmt.Sprintf("%q is not supported for filesystem action type %d, only %q can be set.",
+					name, fsType.ValueInt64(), expected),
+			)
+		}
+	}
+	return diags
+}
+
+// validateHTTPActionParts checks that multipart requests use a method
+// allowing a request body and that each part has a name and either a file
+// path or a body.
+func validateHTTPActionParts(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	httpConfigPath := path.Root("options").AtName("http_config")
+	var parts types.List
+	diags := config.GetAttribute(ctx, httpConfigPath.AtName("parts"), &parts)
+	if diags.HasError() {
+		return diags
+	}
+	if parts.IsNull() || parts.IsUnknown() {
+		return diags
+	}
+	for idx, elem := range parts.Elements() {
+		part, ok := elem.(types.Object)
+		if !ok || part.IsNull() || part.IsUnknown() {
+			continue
+		}
+		diags.Append(validateHTTPActionPart(httpConfigPath.AtName("parts").AtListIndex(idx), part.Attributes())...)
+	}
+	var method types.String
+	diags.Append(config.GetAttribute(ctx, httpConfigPath.AtName("method"), &method)...)
+	if diags.HasError() {
+		return diags
+	}
+	if method.IsNull() || method.IsUnknown() {
+		return diags
+	}
+	if method.ValueString() != http.MethodPost && method.ValueString() != http.MethodPut {
+		diags.AddAttributeError(
+			httpConfigPath.AtName("parts"),
+			"Invalid HTTP Action Configuration",
+			fmt.Sprintf("Multipart requests are only supported with the %s and %s methods, got: %s.",
+				http.MethodPost, http.MethodPut, method.ValueString()),
+		)
 	}
+	return diags
+}
+
+func validateHTTPActionPart(partPath path.Path, attrs map[string]attr.Value) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if name, ok := attrs["name"].(types.String); ok && !name.IsUnknown() && strings.TrimSpace(name.ValueString()) == "" {
+		diags.AddAttributeError(
+			partPath.AtName("name"),
+			"Invalid HTTP Action Configuration",
+			"The part name cannot be empty.",
+		)
+	}
+	filePath, ok := attrs["filepath"].(types.String)
+	if !ok || filePath.IsUnknown() {
+		return diags
+	}
+	body, ok := attrs["body"].(types.String)
+	if !ok || body.IsUnknown() {
+		return diags
+	}
+	hasFilePath := filePath.ValueString() != ""
+	hasBody := body.ValueString() != ""
+	if hasFilePath == hasBody {
+		diags.AddAttributeError(
+			partPath,
+			"Invalid HTTP Action Configuration",
+			"Each part must specify exactly one of file path or body.",
+		)
+	}
+	return diags
 }
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *actionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Retrieve values from plan
-	var plan eventActionResourceModel
+	var plan eventActionResourceWithTimeoutsModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
 
 	action, diags := plan.toSFTPGo(ctx)
 	resp.Diagnostics.Append(diags...)
@@ -465,26 +651,40 @@ func (r *actionResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	action, err := r.client.CreateAction(*action)
+	action, adopted, err := createOrAdopt(ctx, r.client,
+		func() (*client.BaseEventAction, error) { return r.client.CreateAction(ctx, *action) },
+		func() (*client.BaseEventAction, error) {
+			return r.client.GetAction(ctx, plan.Name.ValueString())
+		},
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating event action",
-			"Could not create event action, unexpected error: "+err.Error(),
+			"Could not create event action: "+parseAPIError(err),
 		)
 		return
 	}
-	var state eventActionResourceModel
+	var state eventActionResourceWithTimeoutsModel
 	diags = state.fromSFTPGo(ctx, action)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	diags = r.preservePlanFields(ctx, &plan, &state)
+	diags = r.preservePlanFields(ctx, &plan.eventActionResourceModel, &state.eventActionResourceModel)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	state.Timeouts = plan.Timeouts
+
+	if adopted {
+		resp.Diagnostics.Append(checkAdoptedState(ctx, req.Plan, state, "event action", plan.Name.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
@@ -496,34 +696,47 @@ func (r *actionResource) Create(ctx context.Context, req resource.CreateRequest,
 // Read refreshes the Terraform state with the latest data.
 func (r *actionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	// Get current state
-	var state eventActionResourceModel
+	var state eventActionResourceWithTimeoutsModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
 
-	action, err := r.client.GetAction(state.Name.ValueString())
+	action, err := r.client.GetAction(ctx, state.Name.ValueString())
 	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Event Action",
-			"Could not read SFTPGo Event Action "+state.Name.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Event Action "+state.Name.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
-	var newState eventActionResourceModel
+	var newState eventActionResourceWithTimeoutsModel
 	diags = newState.fromSFTPGo(ctx, action)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	diags = r.preservePlanFields(ctx, &state, &newState)
+	diags = r.preservePlanFields(ctx, &state.eventActionResourceModel, &newState.eventActionResourceModel)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	newState.Timeouts = state.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &newState)
 	resp.Diagnostics.Append(diags...)
@@ -535,48 +748,58 @@ func (r *actionResource) Read(ctx context.Context, req resource.ReadRequest, res
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *actionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	// Retrieve values from plan
-	var plan eventActionResourceModel
+	var plan eventActionResourceWithTimeoutsModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	action, diags := plan.toSFTPGo(ctx)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err := r.client.UpdateAction(*action)
+	err := r.client.UpdateAction(ctx, *action)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating event action",
-			"Could not update event action, unexpected error: "+err.Error(),
+			"Could not update event action: "+parseAPIError(err),
 		)
 		return
 	}
 
-	action, err = r.client.GetAction(plan.Name.ValueString())
+	action, err = r.client.GetAction(ctx, plan.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Event Action",
-			"Could not read SFTPGo Event Action "+plan.Name.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Event Action "+plan.Name.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
-	var state eventActionResourceModel
+	var state eventActionResourceWithTimeoutsModel
 	diags = state.fromSFTPGo(ctx, action)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	diags = r.preservePlanFields(ctx, &plan, &state)
+	diags = r.preservePlanFields(ctx, &plan.eventActionResourceModel, &state.eventActionResourceModel)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	state.Timeouts = plan.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -587,19 +810,26 @@ func (r *actionResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *actionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state eventActionResourceModel
+	var state eventActionResourceWithTimeoutsModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
 
 	// Delete existing action
-	err := r.client.DeleteAction(state.Name.ValueString())
+	err := r.client.DeleteAction(ctx, state.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting SFTPGo Event Action",
-			"Could not delete event action, unexpected error: "+err.Error(),
+			"Could not delete event action: "+parseAPIError(err),
 		)
 		return
 	}
@@ -612,6 +842,7 @@ func (*actionResource) ImportState(ctx context.Context, req resource.ImportState
 }
 
 func (*actionResource) preservePlanFields(ctx context.Context, plan, state *eventActionResourceModel) diag.Diagnostics {
+	state.Description = preserveEmptyString(plan.Description, state.Description)
 	if plan.Options.IsNull() {
 		return nil
 	}
@@ -638,7 +869,15 @@ func (*actionResource) preservePlanFields(ctx context.Context, plan, state *even
 		return diags
 	}
 
-	optionsState.HTTPConfig.Password = optionsPlan.HTTPConfig.Password
+	if optionsPlan.HTTPConfig == nil || optionsState.HTTPConfig == nil {
+		return nil
+	}
+	// SFTPGo returns the password encrypted, keep the configured value,
+	// plain text or already encrypted, for example if read from a data
+	// source. Headers, including multipart headers, are stored as plain
+	// text and returned unchanged, so there is nothing to preserve for them.
+	optionsState.HTTPConfig.Password = preserveSecret(optionsPlan.HTTPConfig.Password,
+		optionsState.HTTPConfig.Password)
 	optionsStateObj, diags := types.ObjectValueFrom(ctx, optionsState.getTFAttributes(), optionsState)
 	if diags.HasError() {
 		return diags
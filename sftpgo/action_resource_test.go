@@ -15,9 +15,14 @@
 package sftpgo
 
 import (
+	"context"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 func TestAccActionResource(t *testing.T) {
@@ -221,9 +226,10 @@ func TestAccActionResource(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      "sftpgo_action.test",
-				ImportState:       true,
-				ImportStateVerify: false,
+				ResourceName:            "sftpgo_action.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: testAccImportStateVerifyIgnore("sftpgo_action"),
 			},
 			{
 				Config: `
@@ -731,3 +737,160 @@ func TestAccActionResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccActionResourceHTTPPassword(t *testing.T) {
+	httpConfig := `
+		resource "sftpgo_action" "test" {
+			name = "test http action"
+			type = 1
+			options = {
+				http_config = {
+					endpoint = "http://127.0.0.1:8082/upload"
+					username = "myuser"
+					password = "mypassword"
+					timeout = 10
+					method = "POST"
+					parts = [
+						{
+							name = "part1"
+							headers = [
+								{
+									key = "Authorization"
+									value = "Bearer token"
+								}
+							]
+							body = "{{.VirtualPath}}"
+						}
+					]
+				}
+			}
+		}`
+	encryptedConfig := httpConfig + `
+		data "sftpgo_actions" "test" {
+			depends_on = [sftpgo_action.test]
+		}
+
+		resource "sftpgo_action" "encrypted" {
+			name = "test http action encrypted"
+			type = 1
+			options = {
+				http_config = {
+					endpoint = "http://127.0.0.1:8082/notify"
+					password = one([for a in data.sftpgo_actions.test.actions : a.options.http_config.password if a.name == "test http action"])
+					timeout = 10
+					method = "GET"
+				}
+			}
+		}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: httpConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_action.test", "options.http_config.password", "mypassword"),
+					resource.TestCheckResourceAttr("sftpgo_action.test", "options.http_config.parts.0.headers.0.value",
+						"Bearer token"),
+				),
+			},
+			{
+				// re-applying the same configuration must not produce changes
+				Config: httpConfig,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				// the encrypted password returned by the data source is kept as is
+				Config: encryptedConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_action.test", "options.http_config.password", "mypassword"),
+					resource.TestMatchResourceAttr("sftpgo_action.encrypted", "options.http_config.password",
+						regexp.MustCompile(`^\$[a-z]+\$`)),
+				),
+			},
+			{
+				Config: encryptedConfig,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccActionResourceDeletedOutsideTerraform(t *testing.T) {
+	testAccDeletedOutsideTerraform(t, "sftpgo_action.test", `
+		resource "sftpgo_action" "test" {
+		  name = "test action deleted"
+		  type = 4
+		}`, func(c *client.Client) error {
+		return c.DeleteAction(context.Background(), "test action deleted")
+	})
+}
+
+func TestAccActionResourceRelativePaths(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "sftpgo_action" "test" {
+						name = "test action relative path"
+						type = 8
+						options = {
+							retention_config = {
+								folders = [
+									{
+										path = "dir1",
+										retention = 10
+									}
+								]
+							}
+						}
+				    }`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Value Virtual Path"),
+			},
+			{
+				Config: `
+					resource "sftpgo_action" "test" {
+						name = "test action relative path"
+						type = 9
+						options = {
+							fs_config = {
+								type = 3
+								mkdirs = ["/dir1", "dir2"]
+							}
+						}
+				    }`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Value Virtual Path"),
+			},
+			{
+				Config: `
+					resource "sftpgo_action" "test" {
+						name = "test action relative path"
+						type = 9
+						options = {
+							fs_config = {
+								type = 6
+								copy = [
+									{
+										key = "/source"
+										value = "{{VirtualPath}}.bak"
+									}
+								]
+							}
+						}
+				    }`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_action.test", "options.fs_config.copy.0.key", "/source"),
+				),
+			},
+		},
+	})
+}
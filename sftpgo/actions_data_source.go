@@ -16,6 +16,7 @@ package sftpgo
 
 import (
 	"context"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -56,7 +57,7 @@ func (d *actionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 			},
 			"actions": schema.ListNestedAttribute{
 				Computed:    true,
-				Description: "List of event actions.",
+				Description: "List of event actions, sorted by name.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"id": schema.StringAttribute{
@@ -387,22 +388,26 @@ func (d *actionsDataSource) Configure(_ context.Context, req datasource.Configur
 		return
 	}
 
-	d.client = req.ProviderData.(*client.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *actionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state actionsDataSourceModel
 
-	actions, err := d.client.GetActions()
+	actions, err := d.client.GetActions(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read SFTPGo Actions",
-			err.Error(),
+			parseAPIError(err),
 		)
 		return
 	}
 
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].Name < actions[j].Name
+	})
+
 	// Map response body to model
 	for _, action := range actions {
 		var actionState eventActionResourceModel
@@ -15,6 +15,7 @@
 package sftpgo
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -49,11 +50,17 @@ func TestAccActionsDataSource(t *testing.T) {
 			},
 		},
 	}
-	_, err = c.CreateAction(action)
+	_, err = c.CreateAction(context.Background(), action)
 	require.NoError(t, err)
 
+	otherAction := client.BaseEventAction{
+		Name: "a action",
+		Type: 4,
+	}
+
 	defer func() {
-		err = c.DeleteAction(action.Name)
+		_ = c.DeleteAction(context.Background(), otherAction.Name)
+		err = c.DeleteAction(context.Background(), action.Name)
 		require.NoError(t, err)
 	}()
 
@@ -97,6 +104,19 @@ func TestAccActionsDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.sftpgo_actions.test", "id", placeholderID),
 				),
 			},
+			// actions are sorted by name
+			{
+				PreConfig: func() {
+					_, err := c.CreateAction(context.Background(), otherAction)
+					require.NoError(t, err)
+				},
+				Config: `data "sftpgo_actions" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sftpgo_actions.test", "actions.#", "2"),
+					resource.TestCheckResourceAttr("data.sftpgo_actions.test", "actions.0.name", otherAction.Name),
+					resource.TestCheckResourceAttr("data.sftpgo_actions.test", "actions.1.name", action.Name),
+				),
+			},
 		},
 	})
 }
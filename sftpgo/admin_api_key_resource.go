@@ -0,0 +1,322 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &adminAPIKeyResource{}
+	_ resource.ResourceWithConfigure   = &adminAPIKeyResource{}
+	_ resource.ResourceWithImportState = &adminAPIKeyResource{}
+)
+
+// NewAdminAPIKeyResource is a helper function to simplify the provider implementation.
+func NewAdminAPIKeyResource() resource.Resource {
+	return &adminAPIKeyResource{}
+}
+
+// adminAPIKeyResource is the resource implementation.
+type adminAPIKeyResource struct {
+	client *client.Client
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *adminAPIKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*providerData).client
+}
+
+// Metadata returns the resource type name.
+func (r *adminAPIKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_admin_api_key"
+}
+
+// Schema defines the schema for the resource.
+func (r *adminAPIKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "API key with admin scope, it grants access to the REST API with the permissions of the " +
+			"associated admin. The admin must have API key authentication enabled, see allow_api_key_auth in " +
+			"the admin filters. The secret key is only available after creation, it is not set for imported keys. " +
+			"Keys can be imported using the generated ID or \"<admin>:<name>\".",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier generated by SFTPGo, it is the first part of the key.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Key name.",
+			},
+			"admin": schema.StringAttribute{
+				Required:    true,
+				Description: "Username of the admin the key is associated with. Changing the admin creates a new key.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "Optional description.",
+			},
+			"expires_at": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Expiration time as unix timestamp in milliseconds. Not set means no expiration.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					millisecondsTimestampValidator{},
+				},
+			},
+			"key": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The API key. SFTPGo returns it only once, on creation.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Creation time as unix timestamp in milliseconds.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Last update time as unix timestamp in milliseconds.",
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *adminAPIKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan adminAPIKeyResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// SFTPGo reports a generic validation error for missing admins
+	if _, err := r.client.GetAdmin(ctx, plan.Admin.ValueString()); err != nil {
+		if client.IsNotFound(err) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("admin"),
+				"Admin Not Found",
+				fmt.Sprintf("Could not create the API key, admin %q does not exist.", plan.Admin.ValueString()),
+			)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading SFTPGo Admin",
+			"Could not read SFTPGo Admin "+plan.Admin.ValueString()+": "+parseAPIError(err),
+		)
+		return
+	}
+
+	apiKey, diags := plan.toSFTPGo(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, key, err := r.client.CreateAPIKey(ctx, *apiKey)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating admin API key",
+			"Could not create admin API key: "+parseAPIError(err),
+		)
+		return
+	}
+
+	apiKey, err = r.client.GetAPIKey(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading SFTPGo API Key",
+			"Could not read SFTPGo API Key "+id+": "+parseAPIError(err),
+		)
+		return
+	}
+
+	var state adminAPIKeyResourceModel
+	diags = state.fromSFTPGo(ctx, apiKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Key = types.StringValue(key)
+	state.Description = preserveEmptyString(plan.Description, state.Description)
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *adminAPIKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state adminAPIKeyResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiKey, err := r.client.GetAPIKey(ctx, state.ID.ValueString())
+	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading SFTPGo API Key",
+			"Could not read SFTPGo API Key "+state.ID.ValueString()+": "+parseAPIError(err),
+		)
+		return
+	}
+	if apiKey.Scope != client.APIKeyScopeAdmin {
+		resp.Diagnostics.AddError(
+			"Invalid SFTPGo API Key Scope",
+			fmt.Sprintf("The API key %s does not have admin scope.", state.ID.ValueString()),
+		)
+		return
+	}
+
+	description := state.Description
+	diags = state.fromSFTPGo(ctx, apiKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Description = preserveEmptyString(description, state.Description)
+	if state.Key.IsUnknown() {
+		state.Key = types.StringNull()
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *adminAPIKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan adminAPIKeyResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiKey, diags := plan.toSFTPGo(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.UpdateAPIKey(ctx, *apiKey)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating admin API key",
+			"Could not update admin API key: "+parseAPIError(err),
+		)
+		return
+	}
+
+	apiKey, err = r.client.GetAPIKey(ctx, plan.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading SFTPGo API Key",
+			"Could not read SFTPGo API Key "+plan.ID.ValueString()+": "+parseAPIError(err),
+		)
+		return
+	}
+
+	var state adminAPIKeyResourceModel
+	diags = state.fromSFTPGo(ctx, apiKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Key = plan.Key
+	state.Description = preserveEmptyString(plan.Description, state.Description)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *adminAPIKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state adminAPIKeyResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteAPIKey(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting SFTPGo admin API key",
+			"Could not delete admin API key: "+parseAPIError(err),
+		)
+		return
+	}
+}
+
+// ImportState imports an existing the resource and save the Terraform state
+func (r *adminAPIKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The import ID is the key ID or "<admin>:<name>"
+	id, diags := getAdminAPIKeyImportID(ctx, r.client, req.ID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAdminAPIKeyResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: `
+					resource "sftpgo_admin" "test" {
+					  username = "test admin key"
+					  status = 1
+					  password = "pwd"
+					  permissions = ["*"]
+					  filters = {
+					    allow_api_key_auth = true
+					  }
+					}
+
+					resource "sftpgo_admin_api_key" "test" {
+					  name = "test key"
+					  admin = sftpgo_admin.test.username
+					}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_admin_api_key.test", "name", "test key"),
+					resource.TestCheckResourceAttr("sftpgo_admin_api_key.test", "admin", "test admin key"),
+					resource.TestCheckResourceAttrSet("sftpgo_admin_api_key.test", "id"),
+					resource.TestCheckResourceAttrSet("sftpgo_admin_api_key.test", "key"),
+					resource.TestCheckNoResourceAttr("sftpgo_admin_api_key.test", "description"),
+					resource.TestCheckNoResourceAttr("sftpgo_admin_api_key.test", "expires_at"),
+					resource.TestCheckResourceAttrSet("sftpgo_admin_api_key.test", "created_at"),
+					resource.TestCheckResourceAttrSet("sftpgo_admin_api_key.test", "updated_at"),
+				),
+			},
+			// ImportState testing, the secret key cannot be read back
+			{
+				ResourceName:            "sftpgo_admin_api_key.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: testAccImportStateVerifyIgnore("sftpgo_admin_api_key"),
+			},
+			{
+				ResourceName:            "sftpgo_admin_api_key.test",
+				ImportState:             true,
+				ImportStateId:           "test admin key:test key",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: testAccImportStateVerifyIgnore("sftpgo_admin_api_key"),
+			},
+			{
+				ResourceName:  "sftpgo_admin_api_key.test",
+				ImportState:   true,
+				ImportStateId: "test admin key:missing key",
+				ExpectError:   regexp.MustCompile("Cannot Import Non-Existent API Key"),
+			},
+			// Update and Read testing
+			{
+				Config: `
+					resource "sftpgo_admin" "test" {
+					  username = "test admin key"
+					  status = 1
+					  password = "pwd"
+					  permissions = ["*"]
+					  filters = {
+					    allow_api_key_auth = true
+					  }
+					}
+
+					resource "sftpgo_admin_api_key" "test" {
+					  name = "test key"
+					  admin = sftpgo_admin.test.username
+					  description = "desc"
+					}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_admin_api_key.test", "description", "desc"),
+					resource.TestCheckResourceAttrSet("sftpgo_admin_api_key.test", "key"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccAdminAPIKeyResourceMissingAdmin(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "sftpgo_admin_api_key" "test" {
+					  name = "test key"
+					  admin = "missing admin"
+					}`,
+				ExpectError: regexp.MustCompile("Admin Not Found"),
+			},
+		},
+	})
+}
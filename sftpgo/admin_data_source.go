@@ -0,0 +1,146 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &adminDataSource{}
+	_ datasource.DataSourceWithConfigure = &adminDataSource{}
+)
+
+// NewAdminDataSource is a helper function to simplify the provider implementation.
+func NewAdminDataSource() datasource.DataSource {
+	return &adminDataSource{}
+}
+
+// adminDataSource is the data source implementation.
+type adminDataSource struct {
+	client *client.Client
+}
+
+// Metadata returns the data source type name.
+func (d *adminDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_admin"
+}
+
+// Schema defines the schema for the data source.
+func (d *adminDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := getComputedAdminAttributes()
+	// the password hash is never returned
+	delete(attributes, "password")
+	attributes["id"] = schema.StringAttribute{
+		Computed:    true,
+		Description: "Required to use the test framework. Matches the username.",
+	}
+	attributes["username"] = schema.StringAttribute{
+		Required:    true,
+		Description: "Username of the admin to fetch.",
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Fetches an admin by username. The password is not returned.",
+		Attributes:  attributes,
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *adminDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*providerData).client
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *adminDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config adminDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	admin, err := d.client.GetAdmin(ctx, config.Username.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read SFTPGo Admin",
+			"Could not read SFTPGo Admin "+config.Username.ValueString()+": "+parseAPIError(err),
+		)
+		return
+	}
+
+	var adminState adminResourceModel
+	diags = adminState.fromSFTPGo(ctx, admin)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var state adminDataSourceModel
+	state.fromResourceModel(&adminState)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// adminDataSourceModel maps the data source schema data, it matches
+// adminResourceModel without the password.
+type adminDataSourceModel struct {
+	ID             types.String        `tfsdk:"id"`
+	Username       types.String        `tfsdk:"username"`
+	Status         types.Int64         `tfsdk:"status"`
+	Email          types.String        `tfsdk:"email"`
+	Permissions    types.List          `tfsdk:"permissions"`
+	Filters        types.Object        `tfsdk:"filters"`
+	Preferences    types.Object        `tfsdk:"preferences"`
+	Description    types.String        `tfsdk:"description"`
+	AdditionalInfo types.String        `tfsdk:"additional_info"`
+	Groups         []adminGroupMapping `tfsdk:"groups"`
+	CreatedAt      types.Int64         `tfsdk:"created_at"`
+	UpdatedAt      types.Int64         `tfsdk:"updated_at"`
+	LastLogin      types.Int64         `tfsdk:"last_login"`
+	Role           types.String        `tfsdk:"role"`
+}
+
+func (a *adminDataSourceModel) fromResourceModel(admin *adminResourceModel) {
+	a.ID = admin.ID
+	a.Username = admin.Username
+	a.Status = admin.Status
+	a.Email = admin.Email
+	a.Permissions = admin.Permissions
+	a.Filters = admin.Filters
+	a.Preferences = admin.Preferences
+	a.Description = admin.Description
+	a.AdditionalInfo = admin.AdditionalInfo
+	a.Groups = admin.Groups
+	a.CreatedAt = admin.CreatedAt
+	a.UpdatedAt = admin.UpdatedAt
+	a.LastLogin = admin.LastLogin
+	a.Role = admin.Role
+}
@@ -55,7 +55,7 @@ func (r *adminResource) Configure(_ context.Context, req resource.ConfigureReque
 		return
 	}
 
-	r.client = req.ProviderData.(*client.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -215,11 +215,14 @@ func (r *adminResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	admin, err := r.client.CreateAdmin(*admin)
+	admin, adopted, err := createOrAdopt(ctx, r.client,
+		func() (*client.Admin, error) { return r.client.CreateAdmin(ctx, *admin) },
+		func() (*client.Admin, error) { return r.client.GetAdmin(ctx, plan.Username.ValueString()) },
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating admin",
-			"Could not create admin, unexpected error: "+err.Error(),
+			"Could not create admin: "+parseAPIError(err),
 		)
 		return
 	}
@@ -229,7 +232,15 @@ func (r *adminResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	state.Password = plan.Password
+	state.Password = preserveSecret(plan.Password, state.Password)
+	state.Description = preserveEmptyString(plan.Description, state.Description)
+
+	if adopted {
+		resp.Diagnostics.Append(checkAdoptedState(ctx, req.Plan, state, "admin", plan.Username.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
@@ -249,11 +260,15 @@ func (r *adminResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	admin, err := r.client.GetAdmin(state.Username.ValueString())
+	admin, err := r.client.GetAdmin(ctx, state.Username.ValueString())
 	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Admin",
-			"Could not read SFTPGo Admin "+state.Username.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Admin "+state.Username.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
@@ -265,7 +280,8 @@ func (r *adminResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	newState.Password = state.Password
+	newState.Password = preserveSecret(state.Password, newState.Password)
+	newState.Description = preserveEmptyString(state.Description, newState.Description)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &newState)
@@ -290,20 +306,20 @@ func (r *adminResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	err := r.client.UpdateAdmin(*admin)
+	err := r.client.UpdateAdmin(ctx, *admin)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating admin",
-			"Could not update admin, unexpected error: "+err.Error(),
+			"Could not update admin: "+parseAPIError(err),
 		)
 		return
 	}
 
-	admin, err = r.client.GetAdmin(plan.Username.ValueString())
+	admin, err = r.client.GetAdmin(ctx, plan.Username.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Admin",
-			"Could not read SFTPGo Admin "+plan.Username.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Admin "+plan.Username.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
@@ -314,7 +330,8 @@ func (r *adminResource) Update(ctx context.Context, req resource.UpdateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	state.Password = plan.Password
+	state.Password = preserveSecret(plan.Password, state.Password)
+	state.Description = preserveEmptyString(plan.Description, state.Description)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -334,11 +351,11 @@ func (r *adminResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 
 	// Delete existing admin
-	err := r.client.DeleteAdmin(state.Username.ValueString())
+	err := r.client.DeleteAdmin(ctx, state.Username.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting SFTPGo admin",
-			"Could not delete admin, unexpected error: "+err.Error(),
+			"Could not delete admin: "+parseAPIError(err),
 		)
 		return
 	}
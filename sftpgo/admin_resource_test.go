@@ -15,11 +15,14 @@
 package sftpgo
 
 import (
+	"context"
 	"os"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 func TestAccAdminResource(t *testing.T) {
@@ -28,19 +31,19 @@ func TestAccAdminResource(t *testing.T) {
 	}
 	c, err := getClient()
 	require.NoError(t, err)
-	_, err = c.CreateFolder(testFolder)
+	_, err = c.CreateFolder(context.Background(), testFolder)
 	require.NoError(t, err)
-	_, err = c.CreateGroup(testGroup)
+	_, err = c.CreateGroup(context.Background(), testGroup)
 	require.NoError(t, err)
-	_, err = c.CreateRole(testRole)
+	_, err = c.CreateRole(context.Background(), testRole)
 	require.NoError(t, err)
 
 	defer func() {
-		err = c.DeleteGroup(testGroup.Name)
+		err = c.DeleteGroup(context.Background(), testGroup.Name)
 		require.NoError(t, err)
-		err = c.DeleteFolder(testFolder.Name)
+		err = c.DeleteFolder(context.Background(), testFolder.Name)
 		require.NoError(t, err)
-		err = c.DeleteRole(testRole.Name)
+		err = c.DeleteRole(context.Background(), testRole.Name)
 		require.NoError(t, err)
 	}()
 
@@ -95,7 +98,9 @@ func TestAccAdminResource(t *testing.T) {
 			{
 				ResourceName:      "sftpgo_admin.test",
 				ImportState:       true,
-				ImportStateVerify: false, // SFTPGo will not return plain text password/secrets
+				ImportStateVerify: true,
+				// SFTPGo will not return plain text password/secrets
+				ImportStateVerifyIgnore: testAccImportStateVerifyIgnore("sftpgo_admin"),
 			},
 			// Update and Read testing
 			{
@@ -150,3 +155,15 @@ func TestAccAdminResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccAdminResourceDeletedOutsideTerraform(t *testing.T) {
+	testAccDeletedOutsideTerraform(t, "sftpgo_admin.test", `
+		resource "sftpgo_admin" "test" {
+		  username = "test admin deleted"
+		  status = 1
+		  password = "secretpwd"
+		  permissions = ["*"]
+		}`, func(c *client.Client) error {
+		return c.DeleteAdmin(context.Background(), "test admin deleted")
+	})
+}
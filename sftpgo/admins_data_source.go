@@ -58,111 +58,117 @@ func (d *adminsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Computed:    true,
 				Description: "List of admins.",
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"id": schema.StringAttribute{
-							Computed: true,
-						},
-						"username": schema.StringAttribute{
-							Computed:    true,
-							Description: "Unique username.",
-						},
-						"status": schema.Int64Attribute{
-							Computed:    true,
-							Description: "1 enabled, 0 disabled (login is not allowed).",
-						},
-						"password": schema.StringAttribute{
-							Computed:    true,
-							Description: "Password hash saved in the SFTPGo data provider.",
-						},
-						"email": schema.StringAttribute{
-							Computed: true,
-						},
-						"permissions": schema.ListAttribute{
-							ElementType: types.StringType,
-							Computed:    true,
-							Description: "Granted permissions.",
-						},
-						"description": schema.StringAttribute{
-							Computed:    true,
-							Description: "Optional description.",
-						},
-						"additional_info": schema.StringAttribute{
-							Computed:    true,
-							Description: "Free form text field.",
-						},
-						"created_at": schema.Int64Attribute{
-							Computed:    true,
-							Description: "Creation time as unix timestamp in milliseconds.",
-						},
-						"updated_at": schema.Int64Attribute{
-							Computed:    true,
-							Description: "Last update time as unix timestamp in milliseconds.",
-						},
-						"last_login": schema.Int64Attribute{
-							Computed:    true,
-							Description: "Last login as unix timestamp in milliseconds.",
-						},
-						"role": schema.StringAttribute{
-							Computed:    true,
-							Description: "Role name. If set the admin can only administer users with the same role.",
-						},
-						"filters": schema.SingleNestedAttribute{
-							Computed:    true,
-							Description: "Additional restrictions.",
-							Attributes: map[string]schema.Attribute{
-								"allow_list": schema.ListAttribute{
-									ElementType: types.StringType,
-									Computed:    true,
-									Description: `Only connections from these IP/Mask are allowed. IP/Mask must be in CIDR notation as defined in RFC 4632 and RFC 4291 for example "192.0.2.0/24" or "2001:db8::/32"`,
-								},
-								"allow_api_key_auth": schema.BoolAttribute{
-									Computed:    true,
-									Description: "If set, API Key authentication is allowed.",
-								},
-								"require_password_change": schema.BoolAttribute{
-									Computed:    true,
-									Description: "If set, two factor authentication is required.",
-								},
-								"require_two_factor": schema.BoolAttribute{
-									Computed:    true,
-									Description: "If set, API Key authentication is allowed.",
-								},
-							},
-						},
-						"preferences": schema.SingleNestedAttribute{
-							Computed:    true,
-							Description: "Admin preferences.",
-							Attributes: map[string]schema.Attribute{
-								"hide_user_page_sections": schema.Int64Attribute{
-									Computed:    true,
-									Description: "If set allow to hide some sections from the user page in the WebAdmin. 1 = groups, 2 = filesystem, 4 = virtual folders, 8 = profile, 16 = ACL, 32 = Disk and bandwidth quota limits, 64 = Advanced. Settings can be combined.",
-								},
-								"default_users_expiration": schema.Int64Attribute{
-									Computed:    true,
-									Description: "If set defines the default expiration for newly created users as number of days.",
-								},
-							},
-						},
-						"groups": schema.ListNestedAttribute{
-							Computed:    true,
-							Description: "Groups automatically selected for new users created by this admin.",
-							NestedObject: schema.NestedAttributeObject{
-								Attributes: map[string]schema.Attribute{
-									"name": schema.StringAttribute{
-										Computed:    true,
-										Description: "Group name.",
-									},
-									"options": schema.SingleNestedAttribute{
-										Computed:    true,
-										Description: "Options for admin/group mapping",
-										Attributes: map[string]schema.Attribute{
-											"add_to_users_as": schema.Int64Attribute{
-												Computed:    true,
-												Description: "Add to users as the specified group type. 1 = Primary, 2 = Secondary, 3 = Membership only.",
-											},
-										},
-									},
-								},
+					Attributes: getComputedAdminAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// getComputedAdminAttributes returns the computed attributes for an admin,
+// they are shared by the sftpgo_admins and sftpgo_admin data sources.
+func getComputedAdminAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Computed: true,
+		},
+		"username": schema.StringAttribute{
+			Computed:    true,
+			Description: "Unique username.",
+		},
+		"status": schema.Int64Attribute{
+			Computed:    true,
+			Description: "1 enabled, 0 disabled (login is not allowed).",
+		},
+		"password": schema.StringAttribute{
+			Computed:    true,
+			Description: "Password hash saved in the SFTPGo data provider.",
+		},
+		"email": schema.StringAttribute{
+			Computed: true,
+		},
+		"permissions": schema.ListAttribute{
+			ElementType: types.StringType,
+			Computed:    true,
+			Description: "Granted permissions.",
+		},
+		"description": schema.StringAttribute{
+			Computed:    true,
+			Description: "Optional description.",
+		},
+		"additional_info": schema.StringAttribute{
+			Computed:    true,
+			Description: "Free form text field.",
+		},
+		"created_at": schema.Int64Attribute{
+			Computed:    true,
+			Description: "Creation time as unix timestamp in milliseconds.",
+		},
+		"updated_at": schema.Int64Attribute{
+			Computed:    true,
+			Description: "Last update time as unix timestamp in milliseconds.",
+		},
+		"last_login": schema.Int64Attribute{
+			Computed:    true,
+			Description: "Last login as unix timestamp in milliseconds.",
+		},
+		"role": schema.StringAttribute{
+			Computed:    true,
+			Description: "Role name. If set the admin can only administer users with the same role.",
+		},
+		"filters": schema.SingleNestedAttribute{
+			Computed:    true,
+			Description: "Additional restrictions.",
+			Attributes: map[string]schema.Attribute{
+				"allow_list": schema.ListAttribute{
+					ElementType: types.StringType,
+					Computed:    true,
+					Description: `Only connections from these IP/Mask are allowed. IP/Mask must be in CIDR notation as defined in RFC 4632 and RFC 4291 for example "192.0.2.0/24" or "2001:db8::/32"`,
+				},
+				"allow_api_key_auth": schema.BoolAttribute{
+					Computed:    true,
+					Description: "If set, API Key authentication is allowed.",
+				},
+				"require_password_change": schema.BoolAttribute{
+					Computed:    true,
+					Description: "If set, two factor authentication is required.",
+				},
+				"require_two_factor": schema.BoolAttribute{
+					Computed:    true,
+					Description: "If set, API Key authentication is allowed.",
+				},
+			},
+		},
+		"preferences": schema.SingleNestedAttribute{
+			Computed:    true,
+			Description: "Admin preferences.",
+			Attributes: map[string]schema.Attribute{
+				"hide_user_page_sections": schema.Int64Attribute{
+					Computed:    true,
+					Description: "If set allow to hide some sections from the user page in the WebAdmin. 1 = groups, 2 = filesystem, 4 = virtual folders, 8 = profile, 16 = ACL, 32 = Disk and bandwidth quota limits, 64 = Advanced. Settings can be combined.",
+				},
+				"default_users_expiration": schema.Int64Attribute{
+					Computed:    true,
+					Description: "If set defines the default expiration for newly created users as number of days.",
+				},
+			},
+		},
+		"groups": schema.ListNestedAttribute{
+			Computed:    true,
+			Description: "Groups automatically selected for new users created by this admin.",
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Computed:    true,
+						Description: "Group name.",
+					},
+					"options": schema.SingleNestedAttribute{
+						Computed:    true,
+						Description: "Options for admin/group mapping",
+						Attributes: map[string]schema.Attribute{
+							"add_to_users_as": schema.Int64Attribute{
+								Computed:    true,
+								Description: "Add to users as the specified group type. 1 = Primary, 2 = Secondary, 3 = Membership only.",
 							},
 						},
 					},
@@ -178,18 +184,18 @@ func (d *adminsDataSource) Configure(_ context.Context, req datasource.Configure
 		return
 	}
 
-	d.client = req.ProviderData.(*client.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *adminsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state adminsDataSourceModel
 
-	admins, err := d.client.GetAdmins()
+	admins, err := d.client.GetAdmins(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read SFTPGo Admins",
-			err.Error(),
+			parseAPIError(err),
 		)
 		return
 	}
@@ -15,6 +15,7 @@
 package sftpgo
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -58,23 +59,23 @@ func TestAccAdminsDataSource(t *testing.T) {
 		},
 		Role: testRole.Name,
 	}
-	_, err = c.CreateRole(testRole)
+	_, err = c.CreateRole(context.Background(), testRole)
 	require.NoError(t, err)
-	_, err = c.CreateFolder(testFolder)
+	_, err = c.CreateFolder(context.Background(), testFolder)
 	require.NoError(t, err)
-	_, err = c.CreateGroup(testGroup)
+	_, err = c.CreateGroup(context.Background(), testGroup)
 	require.NoError(t, err)
-	_, err = c.CreateAdmin(admin)
+	_, err = c.CreateAdmin(context.Background(), admin)
 	require.NoError(t, err)
 
 	defer func() {
-		err = c.DeleteAdmin(admin.Username)
+		err = c.DeleteAdmin(context.Background(), admin.Username)
 		require.NoError(t, err)
-		err = c.DeleteGroup(testGroup.Name)
+		err = c.DeleteGroup(context.Background(), testGroup.Name)
 		require.NoError(t, err)
-		err = c.DeleteFolder(testFolder.Name)
+		err = c.DeleteFolder(context.Background(), testFolder.Name)
 		require.NoError(t, err)
-		err = c.DeleteRole(testRole.Name)
+		err = c.DeleteRole(context.Background(), testRole.Name)
 		require.NoError(t, err)
 	}()
 
@@ -145,6 +146,35 @@ func TestAccAdminsDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.sftpgo_admins.test", "id", placeholderID),
 				),
 			},
+			{
+				Config: `
+					data "sftpgo_admin" "test" {
+					  username = "test_admin"
+					}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "username", admin.Username),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "id", admin.Username),
+					resource.TestCheckNoResourceAttr("data.sftpgo_admin.test", "password"),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "email", admin.Email),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "status", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "permissions.#",
+						fmt.Sprintf("%d", len(admin.Permissions))),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "description", admin.Description),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "additional_info", admin.AdditionalInfo),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "filters.allow_list.#", "2"),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "filters.allow_api_key_auth", "true"),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "filters.require_password_change", "true"),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "filters.require_two_factor", "true"),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "preferences.default_users_expiration",
+						fmt.Sprintf("%d", admin.Filters.Preferences.DefaultUsersExpiration)),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "role", testRole.Name),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "groups.#", "1"),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "groups.0.name", testGroup.Name),
+					resource.TestCheckResourceAttr("data.sftpgo_admin.test", "groups.0.options.add_to_users_as", "2"),
+					resource.TestCheckResourceAttrSet("data.sftpgo_admin.test", "created_at"),
+					resource.TestCheckResourceAttrSet("data.sftpgo_admin.test", "updated_at"),
+				),
+			},
 		},
 	})
 }
@@ -95,18 +95,18 @@ func (d *allowListEntriesDataSource) Configure(_ context.Context, req datasource
 		return
 	}
 
-	d.client = req.ProviderData.(*client.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *allowListEntriesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state allowListEntriesDataSourceModel
 
-	entries, err := d.client.GetIPListEntries(1)
+	entries, err := d.client.GetIPListEntries(ctx, 1)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read SFTPGo allow list entries",
-			err.Error(),
+			parseAPIError(err),
 		)
 		return
 	}
@@ -15,6 +15,7 @@
 package sftpgo
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -38,11 +39,11 @@ func TestAccAllowListDataSource(t *testing.T) {
 		Mode:        1,
 		Protocols:   3,
 	}
-	_, err = c.CreateIPListEntry(entry)
+	_, err = c.CreateIPListEntry(context.Background(), entry)
 	require.NoError(t, err)
 
 	defer func() {
-		err = c.DeleteIPListEntry(entry.Type, entry.IPOrNet)
+		err = c.DeleteIPListEntry(context.Background(), entry.Type, entry.IPOrNet)
 		require.NoError(t, err)
 	}()
 
@@ -50,7 +50,7 @@ func (r *allowListEntryResource) Configure(_ context.Context, req resource.Confi
 		return
 	}
 
-	r.client = req.ProviderData.(*client.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -61,7 +61,7 @@ func (r *allowListEntryResource) Metadata(_ context.Context, req resource.Metada
 // Schema defines the schema for the resource.
 func (r *allowListEntryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Allow list entry",
+		Description: "Allow list entry. It can be imported using the IP or network, optionally prefixed by the list type (1), for example \"1/192.168.1.0/24\".",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:    true,
@@ -113,11 +113,16 @@ func (r *allowListEntryResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	entry, err := r.client.CreateIPListEntry(*entry)
+	entry, adopted, err := createOrAdopt(ctx, r.client,
+		func() (*client.IPListEntry, error) { return r.client.CreateIPListEntry(ctx, *entry) },
+		func() (*client.IPListEntry, error) {
+			return r.client.GetIPListEntry(ctx, entry.Type, plan.IPOrNet.ValueString())
+		},
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating allow list entry",
-			"Could not create allow list entry, unexpected error: "+err.Error(),
+			"Could not create allow list entry: "+parseAPIError(err),
 		)
 		return
 	}
@@ -127,6 +132,14 @@ func (r *allowListEntryResource) Create(ctx context.Context, req resource.Create
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(plan.Description, state.Description)
+
+	if adopted {
+		resp.Diagnostics.Append(checkAdoptedState(ctx, req.Plan, state, "allow list entry", plan.IPOrNet.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
@@ -146,20 +159,26 @@ func (r *allowListEntryResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	entry, err := r.client.GetIPListEntry(1, state.IPOrNet.ValueString())
+	entry, err := r.client.GetIPListEntry(ctx, 1, state.IPOrNet.ValueString())
 	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo allow list entry",
-			"Could not read SFTPGo allow list entry "+state.IPOrNet.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo allow list entry "+state.IPOrNet.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
+	description := state.Description
 	diags = state.fromSFTPGo(ctx, entry)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(description, state.Description)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -184,20 +203,20 @@ func (r *allowListEntryResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	err := r.client.UpdateIPListEntry(*entry)
+	err := r.client.UpdateIPListEntry(ctx, *entry)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating allow list entry",
-			"Could not update allow list entry, unexpected error: "+err.Error(),
+			"Could not update allow list entry: "+parseAPIError(err),
 		)
 		return
 	}
 
-	entry, err = r.client.GetIPListEntry(1, plan.IPOrNet.ValueString())
+	entry, err = r.client.GetIPListEntry(ctx, 1, plan.IPOrNet.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo allow list entry",
-			"Could not read SFTPGo allow list entry "+plan.IPOrNet.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo allow list entry "+plan.IPOrNet.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
@@ -208,6 +227,7 @@ func (r *allowListEntryResource) Update(ctx context.Context, req resource.Update
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(plan.Description, state.Description)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -227,18 +247,23 @@ func (r *allowListEntryResource) Delete(ctx context.Context, req resource.Delete
 	}
 
 	// Delete existing entry
-	err := r.client.DeleteIPListEntry(1, state.IPOrNet.ValueString())
+	err := r.client.DeleteIPListEntry(ctx, 1, state.IPOrNet.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting SFTPGo allow list entry",
-			"Could not delete allow list entry, unexpected error: "+err.Error(),
+			"Could not delete allow list entry: "+parseAPIError(err),
 		)
 		return
 	}
 }
 
 // ImportState imports an existing the resource and save the Terraform state
-func (*allowListEntryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ipornet and save to ipornet attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("ipornet"), req, resp)
+func (r *allowListEntryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The import ID is the ipornet, optionally prefixed by the list type
+	ipOrNet, diags := getIPListEntryImportID(ctx, r.client, req.ID, 1, "allow list")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ipornet"), ipOrNet)...)
 }
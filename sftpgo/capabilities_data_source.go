@@ -0,0 +1,187 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sftpgo/sdk"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &capabilitiesDataSource{}
+	_ datasource.DataSourceWithConfigure = &capabilitiesDataSource{}
+)
+
+// optionalFsProviders maps the build features to the filesystem providers
+// they enable. The other providers are always available.
+var optionalFsProviders = map[string]sdk.FilesystemProvider{
+	"s3":     sdk.S3FilesystemProvider,
+	"gcs":    sdk.GCSFilesystemProvider,
+	"azblob": sdk.AzureBlobFilesystemProvider,
+}
+
+// NewCapabilitiesDataSource is a helper function to simplify the provider implementation.
+func NewCapabilitiesDataSource() datasource.DataSource {
+	return &capabilitiesDataSource{}
+}
+
+// capabilitiesDataSource is the data source implementation.
+type capabilitiesDataSource struct {
+	client *client.Client
+}
+
+// Metadata returns the data source type name.
+func (d *capabilitiesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_capabilities"
+}
+
+// Schema defines the schema for the data source.
+func (d *capabilitiesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the SFTPGo version and the features available in the running build.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Required to use the test framework. Just a placeholder.",
+			},
+			"version": schema.StringAttribute{
+				Computed:    true,
+				Description: "SFTPGo version.",
+			},
+			"build_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "Build date.",
+			},
+			"commit_hash": schema.StringAttribute{
+				Computed:    true,
+				Description: "Git commit hash.",
+			},
+			"enabled_features": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: `Features enabled at build time, for example "s3", "gcs", "azblob", "metrics".`,
+			},
+			"disabled_features": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Features disabled at build time.",
+			},
+			"filesystem_providers": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Computed:    true,
+				Description: "Filesystem providers supported by the running build. 0 = local filesystem, 1 = S3 Compatible, 2 = Google Cloud, 3 = Azure Blob, 4 = Local encrypted, 5 = SFTP, 6 = HTTP",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *capabilitiesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*providerData).client
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *capabilitiesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	info, err := d.client.GetVersion(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read SFTPGo Capabilities",
+			parseAPIError(err),
+		)
+		return
+	}
+
+	var state capabilitiesDataSourceModel
+	diags := state.fromSFTPGo(ctx, info)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// capabilitiesDataSourceModel maps the data source schema data.
+type capabilitiesDataSourceModel struct {
+	ID                  types.String `tfsdk:"id"`
+	Version             types.String `tfsdk:"version"`
+	BuildDate           types.String `tfsdk:"build_date"`
+	CommitHash          types.String `tfsdk:"commit_hash"`
+	EnabledFeatures     types.List   `tfsdk:"enabled_features"`
+	DisabledFeatures    types.List   `tfsdk:"disabled_features"`
+	FilesystemProviders types.List   `tfsdk:"filesystem_providers"`
+}
+
+func (m *capabilitiesDataSourceModel) fromSFTPGo(ctx context.Context, info *client.VersionInfo) diag.Diagnostics {
+	enabled := []string{}
+	disabled := []string{}
+	for _, feature := range info.Features {
+		switch {
+		case strings.HasPrefix(feature, "+"):
+			enabled = append(enabled, strings.TrimPrefix(feature, "+"))
+		case strings.HasPrefix(feature, "-"):
+			disabled = append(disabled, strings.TrimPrefix(feature, "-"))
+		}
+	}
+	providers := []int64{}
+	for _, provider := range []sdk.FilesystemProvider{sdk.LocalFilesystemProvider, sdk.S3FilesystemProvider,
+		sdk.GCSFilesystemProvider, sdk.AzureBlobFilesystemProvider, sdk.CryptedFilesystemProvider,
+		sdk.SFTPFilesystemProvider, sdk.HTTPFilesystemProvider} {
+		supported := true
+		for feature, p := range optionalFsProviders {
+			if p == provider && !contains(enabled, feature) {
+				supported = false
+			}
+		}
+		if supported {
+			providers = append(providers, int64(provider))
+		}
+	}
+
+	m.ID = types.StringValue(placeholderID)
+	m.Version = types.StringValue(info.Version)
+	m.BuildDate = types.StringValue(info.BuildDate)
+	m.CommitHash = types.StringValue(info.CommitHash)
+
+	var diags diag.Diagnostics
+	m.EnabledFeatures, diags = types.ListValueFrom(ctx, types.StringType, enabled)
+	if diags.HasError() {
+		return diags
+	}
+	m.DisabledFeatures, diags = types.ListValueFrom(ctx, types.StringType, disabled)
+	if diags.HasError() {
+		return diags
+	}
+	m.FilesystemProviders, diags = types.ListValueFrom(ctx, types.Int64Type, providers)
+	return diags
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+func TestAccCapabilitiesDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+	info, err := c.GetVersion(context.Background())
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: `data "sftpgo_capabilities" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sftpgo_capabilities.test", "version", info.Version),
+					resource.TestCheckResourceAttr("data.sftpgo_capabilities.test", "commit_hash", info.CommitHash),
+					resource.TestCheckResourceAttrSet("data.sftpgo_capabilities.test", "enabled_features.#"),
+					// local, crypt, SFTP and HTTP filesystems are always supported
+					resource.TestCheckTypeSetElemAttr("data.sftpgo_capabilities.test", "filesystem_providers.*", "0"),
+					resource.TestCheckTypeSetElemAttr("data.sftpgo_capabilities.test", "filesystem_providers.*", "4"),
+					resource.TestCheckTypeSetElemAttr("data.sftpgo_capabilities.test", "filesystem_providers.*", "5"),
+					resource.TestCheckTypeSetElemAttr("data.sftpgo_capabilities.test", "filesystem_providers.*", "6"),
+					// Verify placeholder id attribute
+					resource.TestCheckResourceAttr("data.sftpgo_capabilities.test", "id", placeholderID),
+				),
+			},
+		},
+	})
+}
+
+func TestCapabilitiesFromVersion(t *testing.T) {
+	var model capabilitiesDataSourceModel
+	diags := model.fromSFTPGo(context.Background(), &client.VersionInfo{
+		Version:    "2.6.2",
+		BuildDate:  "2024-07-20T08:16:19Z",
+		CommitHash: "abcdef0",
+		Features:   []string{"+metrics", "+s3", "-gcs", "+azblob", "+sqlite", "-unixcrypt"},
+	})
+	require.False(t, diags.HasError())
+	require.Equal(t, "2.6.2", model.Version.ValueString())
+
+	var enabled, disabled []string
+	var providers []int64
+	require.False(t, model.EnabledFeatures.ElementsAs(context.Background(), &enabled, false).HasError())
+	require.False(t, model.DisabledFeatures.ElementsAs(context.Background(), &disabled, false).HasError())
+	require.False(t, model.FilesystemProviders.ElementsAs(context.Background(), &providers, false).HasError())
+	require.Equal(t, []string{"metrics", "s3", "azblob", "sqlite"}, enabled)
+	require.Equal(t, []string{"gcs", "unixcrypt"}, disabled)
+	require.Equal(t, []int64{0, 1, 3, 4, 5, 6}, providers)
+}
@@ -16,6 +16,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -200,8 +201,8 @@ type BaseEventAction struct {
 }
 
 // GetActions - Returns list of actions
-func (c *Client) GetActions() ([]BaseEventAction, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/dumpdata?output-data=1&scopes=actions", c.HostURL), nil)
+func (c *Client) GetActions(ctx context.Context) ([]BaseEventAction, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/dumpdata?output-data=1&scopes=actions", c.HostURL), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -221,12 +222,12 @@ func (c *Client) GetActions() ([]BaseEventAction, error) {
 }
 
 // CreateAction - creates a new action
-func (c *Client) CreateAction(action BaseEventAction) (*BaseEventAction, error) {
+func (c *Client) CreateAction(ctx context.Context, action BaseEventAction) (*BaseEventAction, error) {
 	rb, err := json.Marshal(action)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v2/eventactions?confidential_data=1", c.HostURL),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v2/eventactions?confidential_data=1", c.HostURL),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return nil, err
@@ -243,8 +244,8 @@ func (c *Client) CreateAction(action BaseEventAction) (*BaseEventAction, error)
 }
 
 // GetAction - Returns a specifc action
-func (c *Client) GetAction(name string) (*BaseEventAction, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/eventactions/%s?confidential_data=1", c.HostURL,
+func (c *Client) GetAction(ctx context.Context, name string) (*BaseEventAction, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/eventactions/%s?confidential_data=1", c.HostURL,
 		url.PathEscape(name)), nil)
 	if err != nil {
 		return nil, err
@@ -260,12 +261,12 @@ func (c *Client) GetAction(name string) (*BaseEventAction, error) {
 }
 
 // UpdateAction - Updates an existing action
-func (c *Client) UpdateAction(action BaseEventAction) error {
+func (c *Client) UpdateAction(ctx context.Context, action BaseEventAction) error {
 	rb, err := json.Marshal(action)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v2/eventactions/%s", c.HostURL, url.PathEscape(action.Name)),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/eventactions/%s", c.HostURL, url.PathEscape(action.Name)),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return err
@@ -276,8 +277,8 @@ func (c *Client) UpdateAction(action BaseEventAction) error {
 }
 
 // DeleteAction - Deletes a action
-func (c *Client) DeleteAction(name string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v2/eventactions/%s", c.HostURL, url.PathEscape(name)), nil)
+func (c *Client) DeleteAction(ctx context.Context, name string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v2/eventactions/%s", c.HostURL, url.PathEscape(name)), nil)
 	if err != nil {
 		return err
 	}
@@ -16,6 +16,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -101,8 +102,8 @@ type Admin struct {
 }
 
 // GetAdmins - Returns list of admin
-func (c *Client) GetAdmins() ([]Admin, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/dumpdata?output-data=1&scopes=admins", c.HostURL), nil)
+func (c *Client) GetAdmins(ctx context.Context) ([]Admin, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/dumpdata?output-data=1&scopes=admins", c.HostURL), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -121,12 +122,12 @@ func (c *Client) GetAdmins() ([]Admin, error) {
 }
 
 // CreateAdmin - creates a new admin
-func (c *Client) CreateAdmin(admin Admin) (*Admin, error) {
+func (c *Client) CreateAdmin(ctx context.Context, admin Admin) (*Admin, error) {
 	rb, err := json.Marshal(admin)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v2/admins?confidential_data=1", c.HostURL),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v2/admins?confidential_data=1", c.HostURL),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return nil, err
@@ -143,8 +144,8 @@ func (c *Client) CreateAdmin(admin Admin) (*Admin, error) {
 }
 
 // GetAdmin - Returns a specifc admin
-func (c *Client) GetAdmin(username string) (*Admin, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/admins/%s?confidential_data=1", c.HostURL,
+func (c *Client) GetAdmin(ctx context.Context, username string) (*Admin, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/admins/%s?confidential_data=1", c.HostURL,
 		url.PathEscape(username)), nil)
 	if err != nil {
 		return nil, err
@@ -160,12 +161,13 @@ func (c *Client) GetAdmin(username string) (*Admin, error) {
 }
 
 // UpdateAdmin - Updates an existing admin
-func (c *Client) UpdateAdmin(admin Admin) error {
-	rb, err := json.Marshal(admin)
+func (c *Client) UpdateAdmin(ctx context.Context, admin Admin) error {
+	rb, err := c.marshalForUpdate(ctx, fmt.Sprintf("%s/api/v2/admins/%s?confidential_data=1", c.HostURL, url.PathEscape(admin.Username)),
+		admin)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v2/admins/%s", c.HostURL, url.PathEscape(admin.Username)),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/admins/%s", c.HostURL, url.PathEscape(admin.Username)),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return err
@@ -176,8 +178,8 @@ func (c *Client) UpdateAdmin(admin Admin) error {
 }
 
 // DeleteAdmin - Deletes an admin
-func (c *Client) DeleteAdmin(username string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v2/admins/%s", c.HostURL, url.PathEscape(username)), nil)
+func (c *Client) DeleteAdmin(ctx context.Context, username string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v2/admins/%s", c.HostURL, url.PathEscape(username)), nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,158 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// API key scopes
+const (
+	APIKeyScopeAdmin = 1
+	APIKeyScopeUser  = 2
+)
+
+// APIKey defines an SFTPGo API key.
+type APIKey struct {
+	// Unique identifier, it is the first part of the displayed key
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	// Scope is 1 for admin keys and 2 for user keys
+	Scope int `json:"scope"`
+	// Creation time as unix timestamp in milliseconds
+	CreatedAt int64 `json:"created_at,omitempty"`
+	// last update time as unix timestamp in milliseconds
+	UpdatedAt int64 `json:"updated_at,omitempty"`
+	// last use time as unix timestamp in milliseconds
+	LastUseAt int64 `json:"last_use_at,omitempty"`
+	// expiration time as unix timestamp in milliseconds, 0 means no expiration
+	ExpiresAt   int64  `json:"expires_at,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Admin and User are the key owner, only one of them is set based on
+	// the scope
+	Admin string `json:"admin,omitempty"`
+	User  string `json:"user,omitempty"`
+}
+
+type apiKeyResponse struct {
+	Message string `json:"message"`
+	Key     string `json:"key"`
+}
+
+// CreateAPIKey - Creates a new API key and returns its ID and the secret key.
+// The key is returned only once and cannot be read back
+func (c *Client) CreateAPIKey(ctx context.Context, apiKey APIKey) (string, string, error) {
+	rb, err := json.Marshal(apiKey)
+	if err != nil {
+		return "", "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v2/apikeys", c.HostURL), bytes.NewBuffer(rb))
+	if err != nil {
+		return "", "", err
+	}
+
+	body, err := c.doRequestWithAuth(req, http.StatusCreated)
+	if err != nil {
+		return "", "", err
+	}
+
+	var resp apiKeyResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", "", err
+	}
+	// the displayed key has the format "<id>.<secret>"
+	id, _, ok := strings.Cut(resp.Key, ".")
+	if !ok || id == "" {
+		return "", "", errors.New("unexpected API key format in response")
+	}
+	return id, resp.Key, nil
+}
+
+// GetAPIKey - Returns a specifc API key, the secret key is not included
+func (c *Client) GetAPIKey(ctx context.Context, id string) (*APIKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/apikeys/%s", c.HostURL, url.PathEscape(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.doRequestWithAuth(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiKey APIKey
+	err = json.Unmarshal(body, &apiKey)
+	return &apiKey, err
+}
+
+// apiKeysPageSize is the maximum number of API keys returned by a single request
+const apiKeysPageSize = 500
+
+// GetAPIKeys - Returns the list of API keys, the secret keys are not included
+func (c *Client) GetAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var apiKeys []APIKey
+	for offset := 0; ; offset += apiKeysPageSize {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/apikeys?offset=%d&limit=%d", c.HostURL,
+			offset, apiKeysPageSize), nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := c.doRequestWithAuth(req, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []APIKey
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		apiKeys = append(apiKeys, page...)
+		if len(page) < apiKeysPageSize {
+			return apiKeys, nil
+		}
+	}
+}
+
+// UpdateAPIKey - Updates an existing API key
+func (c *Client) UpdateAPIKey(ctx context.Context, apiKey APIKey) error {
+	rb, err := json.Marshal(apiKey)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/apikeys/%s", c.HostURL, url.PathEscape(apiKey.ID)),
+		bytes.NewBuffer(rb))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequestWithAuth(req, http.StatusOK)
+	return err
+}
+
+// DeleteAPIKey - Deletes an API key
+func (c *Client) DeleteAPIKey(ctx context.Context, id string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v2/apikeys/%s", c.HostURL, url.PathEscape(id)), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequestWithAuth(req, http.StatusOK)
+	return err
+}
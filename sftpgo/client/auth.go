@@ -15,6 +15,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -25,12 +26,12 @@ const (
 )
 
 // signInAdmin returns a new access token for the admin with the specified credentials.
-func (c *Client) signInAdmin() (*AuthResponse, error) {
+func (c *Client) signInAdmin(ctx context.Context) (*AuthResponse, error) {
 	if c.Auth.Username == "" || c.Auth.Password == "" {
 		return nil, fmt.Errorf("define username and password")
 	}
 
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s", c.HostURL, authEndpoint), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s", c.HostURL, authEndpoint), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -15,9 +15,15 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -27,37 +33,65 @@ import (
 // HostURL - Default SFTPGo URL
 const HostURL string = "http://localhost:8080"
 
+// Default settings for the HTTP client
+const (
+	DefaultTimeout   = 20 * time.Second
+	DefaultRetryWait = 1 * time.Second
+	DefaultUserAgent = "terraform-provider-sftpgo"
+)
+
 // Client defines the SFTPGo API client
 type Client struct {
-	HostURL      string
-	HTTPClient   *http.Client
-	APIKey       string
-	Auth         AuthStruct
-	Headers      []KeyValue
+	HostURL    string
+	HTTPClient *http.Client
+	APIKey     string
+	Auth       AuthStruct
+	Headers    []KeyValue
+	// UserAgent is sent with each request, Headers can override it
+	UserAgent string
+	// RetryMax is the maximum number of retries for failed requests.
+	// 0 means no retry
+	RetryMax int
+	// RetryWait is the wait time before the first retry, it is doubled
+	// after each attempt
+	RetryWait time.Duration
+	// fallbackHosts are tried in order if HostURL is unreachable
+	fallbackHosts []string
+	basePath      string
+	session       *authSession
+	server        *serverInfo
+}
+
+// authSession holds the access token
+type authSession struct {
 	mu           sync.RWMutex
 	authResponse *AuthResponse
+	// refreshMu serializes sign-ins, so concurrent requests refresh
+	// an expired token only once
+	refreshMu sync.Mutex
 }
 
 func (c *Client) setAuthResponse(ar *AuthResponse) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
 
-	c.authResponse = ar
+	c.session.authResponse = ar
 }
 
 func (c *Client) getAccessToken() string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.session.mu.RLock()
+	defer c.session.mu.RUnlock()
 
-	if c.authResponse == nil {
+	if c.session.authResponse == nil {
 		return ""
 	}
 
-	if c.authResponse.ExpiresAt.Before(time.Now().Add(-2 * time.Minute)) {
+	// refresh the token before it expires
+	if c.session.authResponse.ExpiresAt.Before(time.Now().Add(2 * time.Minute)) {
 		return ""
 	}
 
-	return c.authResponse.AccessToken
+	return c.session.authResponse.AccessToken
 }
 
 // AuthStruct defines th SFTPGo API auth
@@ -84,14 +118,18 @@ type backupData struct {
 // NewClient return an SFTPGo API client
 func NewClient(host, username, password, apiKey *string, headers []KeyValue) (*Client, error) {
 	c := Client{
-		HTTPClient: &http.Client{Timeout: 20 * time.Second},
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
 		// Default SFTPGo URL
-		HostURL: HostURL,
-		Headers: headers,
+		HostURL:   HostURL,
+		Headers:   headers,
+		UserAgent: DefaultUserAgent,
+		RetryWait: DefaultRetryWait,
+		session:   &authSession{},
+		server:    &serverInfo{},
 	}
 
 	if host != nil {
-		c.HostURL = *host
+		c.HostURL = strings.TrimRight(*host, "/")
 	}
 
 	if getStringFromPointer(apiKey) != "" {
@@ -112,36 +150,98 @@ func NewClient(host, username, password, apiKey *string, headers []KeyValue) (*C
 	return &c, nil
 }
 
-func (c *Client) setAuthHeader(req *http.Request) error {
+// refreshAccessToken signs in and returns a new access token. If another
+// request already replaced the invalid token, the cached token is returned.
+func (c *Client) refreshAccessToken(ctx context.Context, invalidToken string) (string, error) {
+	c.session.refreshMu.Lock()
+	defer c.session.refreshMu.Unlock()
+
+	if accessToken := c.getAccessToken(); accessToken != "" && accessToken != invalidToken {
+		return accessToken, nil
+	}
+
+	ar, err := c.signInAdmin(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.setAuthResponse(ar)
+
+	return ar.AccessToken, nil
+}
+
+// SetBasePath sets the path prefix for the SFTPGo API, it is required if
+// SFTPGo is served under a sub-path, for example behind a reverse proxy
+func (c *Client) SetBasePath(basePath string) {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		return
+	}
+	c.basePath = "/" + basePath
+	c.HostURL = strings.TrimRight(c.HostURL, "/") + c.basePath
+	for idx, host := range c.fallbackHosts {
+		c.fallbackHosts[idx] = host + c.basePath
+	}
+}
+
+// SetFallbackHosts sets the SFTPGo URLs to try, in order, if the host is
+// unreachable. The base path, if any, is added to each URL
+func (c *Client) SetFallbackHosts(hosts []string) {
+	c.fallbackHosts = nil
+	for _, host := range hosts {
+		host = strings.TrimRight(host, "/")
+		if host == "" {
+			continue
+		}
+		c.fallbackHosts = append(c.fallbackHosts, host+c.basePath)
+	}
+}
+
+// setAuthHeader sets the authentication header and returns the access token
+// used, if any.
+func (c *Client) setAuthHeader(req *http.Request, invalidToken string) (string, error) {
 	if c.APIKey != "" {
 		req.Header.Set("X-SFTPGO-API-KEY", c.APIKey)
-		return nil
+		return "", nil
 	}
 
 	accessToken := c.getAccessToken()
-	if accessToken == "" {
-		ar, err := c.signInAdmin()
+	if accessToken == "" || accessToken == invalidToken {
+		var err error
+		accessToken, err = c.refreshAccessToken(req.Context(), invalidToken)
 		if err != nil {
-			return err
+			return "", err
 		}
-		c.setAuthResponse(ar)
-
-		accessToken = ar.AccessToken
 	}
 
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
 
-	return nil
+	return accessToken, nil
 }
 
 func (c *Client) doRequestWithAuth(req *http.Request, expectedStatusCode int) ([]byte, error) {
-	if err := c.setAuthHeader(req); err != nil {
+	accessToken, err := c.setAuthHeader(req, "")
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.doRequest(req, expectedStatusCode)
+	if accessToken == "" || !isStatusCodeError(err, http.StatusUnauthorized) {
+		return body, err
+	}
+	// the token was revoked or SFTPGo was restarted with a different
+	// signing key, sign in again and retry once
+	if err := rewindRequestBody(req); err != nil {
+		return nil, err
+	}
+	if _, err := c.setAuthHeader(req, accessToken); err != nil {
 		return nil, err
 	}
 	return c.doRequest(req, expectedStatusCode)
 }
 
 func (c *Client) doRequest(req *http.Request, expectedStatusCode int) ([]byte, error) {
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 	for _, h := range c.Headers {
 		req.Header.Set(h.Key, h.Value)
 	}
@@ -150,24 +250,169 @@ func (c *Client) doRequest(req *http.Request, expectedStatusCode int) ([]byte, e
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	res, err := c.HTTPClient.Do(req)
+	start := time.Now()
+	attempts := 1
+	res, err := c.do(req)
+	for attempt := 0; attempt < c.RetryMax && shouldRetry(req, res, err); attempt++ {
+		wait := c.getRetryWait(attempt, res)
+		if res != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		if err := rewindRequestBody(req); err != nil {
+			return nil, err
+		}
+		if err := sleepWithContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		res, err = c.do(req)
+		attempts++
+	}
 	if err != nil {
+		logRequest(req, nil, nil, start, attempts, err)
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
+		logRequest(req, nil, nil, start, attempts, err)
 		return nil, err
 	}
+	logRequest(req, res, body, start, attempts, nil)
 
 	if res.StatusCode != expectedStatusCode {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+		return nil, &statusCodeError{
+			statusCode: res.StatusCode,
+			body:       body,
+		}
 	}
 
 	return body, err
 }
 
+// do sends the request and, if the host is unreachable, tries the fallback
+// hosts in order. Only connection errors trigger a failover, so the request
+// was never received by SFTPGo and it is safe to send it again.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	res, err := c.HTTPClient.Do(req)
+	if err == nil || len(c.fallbackHosts) == 0 || !isConnectionError(err) {
+		return res, err
+	}
+	reqURL := req.URL.String()
+	if !strings.HasPrefix(reqURL, c.HostURL) {
+		return res, err
+	}
+	for _, host := range c.fallbackHosts {
+		fallbackURL, parseErr := url.Parse(host + strings.TrimPrefix(reqURL, c.HostURL))
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		if err := rewindRequestBody(req); err != nil {
+			return nil, err
+		}
+		fallbackReq := req.Clone(req.Context())
+		fallbackReq.URL = fallbackURL
+		fallbackReq.Host = ""
+		res, err = c.HTTPClient.Do(fallbackReq)
+		if err == nil || !isConnectionError(err) {
+			return res, err
+		}
+	}
+	return res, err
+}
+
+// sleepWithContext waits for the given duration or until ctx is done.
+func sleepWithContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isConnectionError reports whether err is a failure to connect to the host.
+func isConnectionError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Op == "dial"
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// statusCodeError is returned if SFTPGo responds with an unexpected status code
+type statusCodeError struct {
+	statusCode int
+	body       []byte
+}
+
+func (e *statusCodeError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.statusCode, e.body)
+}
+
+// IsNotFound reports whether err is returned because the requested object
+// does not exist in SFTPGo.
+func IsNotFound(err error) bool {
+	return isStatusCodeError(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is returned because the object to create
+// already exists in SFTPGo.
+func IsConflict(err error) bool {
+	return isStatusCodeError(err, http.StatusConflict)
+}
+
+// IsTransportError reports whether err is a failure to send the request or
+// to read the response. SFTPGo may or may not have processed the request.
+func IsTransportError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// APIError is the error response body returned by SFTPGo
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Error      string `json:"error"`
+	Message    string `json:"message"`
+}
+
+// GetAPIError returns the error details returned by SFTPGo, if err is
+// caused by an unexpected status code and the response body is a JSON
+// encoded error. It returns nil otherwise
+func GetAPIError(err error) *APIError {
+	var statusErr *statusCodeError
+	if !errors.As(err, &statusErr) {
+		return nil
+	}
+	var apiErr APIError
+	if err := json.Unmarshal(statusErr.body, &apiErr); err != nil {
+		return nil
+	}
+	if apiErr.Error == "" && apiErr.Message == "" {
+		return nil
+	}
+	apiErr.StatusCode = statusErr.statusCode
+	return &apiErr
+}
+
+func isStatusCodeError(err error, statusCode int) bool {
+	var statusErr *statusCodeError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode == statusCode
+	}
+	return false
+}
+
 func getStringFromPointer(val *string) string {
 	if val == nil {
 		return ""
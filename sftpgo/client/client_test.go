@@ -0,0 +1,782 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/sftpgo/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestClient(serverURL string, retryMax int) *Client {
+	return &Client{
+		HostURL:    serverURL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		APIKey:     "apikey",
+		RetryMax:   retryMax,
+		RetryWait:  time.Millisecond,
+		session:    &authSession{},
+		server:     &serverInfo{},
+	}
+}
+
+func TestRetryOnServiceUnavailable(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name":"role","description":"desc"}`))
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 2)
+	role, err := c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+	require.Equal(t, "role", role.Name)
+	require.Equal(t, int32(2), requests.Load())
+	// retries are disabled by default
+	requests.Store(0)
+	c = getTestClient(ts.URL, 0)
+	_, err = c.GetRole(context.Background(), "role")
+	require.ErrorContains(t, err, "status: 503")
+	require.Equal(t, int32(1), requests.Load())
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 3)
+	_, err := c.GetRole(context.Background(), "role")
+	require.ErrorContains(t, err, "status: 429")
+	require.Equal(t, int32(4), requests.Load())
+}
+
+func TestRetryRequestBody(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil || len(body) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 1)
+	err := c.UpdateRole(context.Background(), Role{Name: "role"})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), requests.Load())
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 2)
+	_, err := c.CreateRole(context.Background(), Role{Name: "role"})
+	require.ErrorContains(t, err, "status: 502")
+	require.Equal(t, int32(1), requests.Load())
+	// idempotent requests are retried
+	requests.Store(0)
+	err = c.DeleteRole(context.Background(), "role")
+	require.ErrorContains(t, err, "status: 502")
+	require.Equal(t, int32(3), requests.Load())
+}
+
+func TestGetRetryWait(t *testing.T) {
+	c := &Client{RetryWait: time.Second}
+	require.Equal(t, time.Second, c.getRetryWait(0, nil))
+	require.Equal(t, 2*time.Second, c.getRetryWait(1, nil))
+	require.Equal(t, 8*time.Second, c.getRetryWait(3, nil))
+	require.Equal(t, maxRetryWait, c.getRetryWait(20, nil))
+
+	res := &http.Response{Header: http.Header{}}
+	res.Header.Set("Retry-After", "5")
+	require.Equal(t, 5*time.Second, c.getRetryWait(3, res))
+	res.Header.Set("Retry-After", "3600")
+	require.Equal(t, maxRetryWait, c.getRetryWait(0, res))
+	res.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	require.Equal(t, time.Duration(0), c.getRetryWait(0, res))
+	res.Header.Set("Retry-After", "invalid")
+	require.Equal(t, 2*time.Second, c.getRetryWait(1, res))
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	var tokenRequests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == authEndpoint {
+			tokenRequests.Add(1)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if r.Header.Get("X-SFTPGO-API-KEY") != "apikey" || r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name":"role"}`))
+	}))
+	defer ts.Close()
+
+	host := ts.URL
+	apiKey := "apikey"
+	c, err := NewClient(&host, nil, nil, &apiKey, nil)
+	require.NoError(t, err)
+	role, err := c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+	require.Equal(t, "role", role.Name)
+	require.Equal(t, int32(0), tokenRequests.Load())
+
+	_, err = NewClient(&host, nil, nil, nil, nil)
+	require.Error(t, err)
+}
+
+// newTokenServer returns a test server issuing a new token for each sign-in.
+// Only the last issued token is accepted.
+func newTokenServer(tokenRequests *atomic.Int32) *httptest.Server {
+	var mu sync.Mutex
+	validToken := ""
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path == authEndpoint {
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "admin" || password != "password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			validToken = fmt.Sprintf("token%d", tokenRequests.Add(1))
+			resp, _ := json.Marshal(AuthResponse{
+				AccessToken: validToken,
+				ExpiresAt:   time.Now().Add(20 * time.Minute),
+			})
+			_, _ = w.Write(resp)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+validToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"role"}`))
+	}))
+}
+
+func getTestClientWithCredentials(t *testing.T, serverURL string) *Client {
+	username := "admin"
+	password := "password"
+	c, err := NewClient(&serverURL, &username, &password, nil, nil)
+	require.NoError(t, err)
+	return c
+}
+
+func getRolesConcurrently(t *testing.T, c *Client) {
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.GetRole(context.Background(), "role")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestTokenCaching(t *testing.T) {
+	var tokenRequests atomic.Int32
+	ts := newTokenServer(&tokenRequests)
+	defer ts.Close()
+
+	c := getTestClientWithCredentials(t, ts.URL)
+	getRolesConcurrently(t, c)
+	require.Equal(t, int32(1), tokenRequests.Load())
+	getRolesConcurrently(t, c)
+	require.Equal(t, int32(1), tokenRequests.Load())
+}
+
+func TestTokenRefreshOnExpiration(t *testing.T) {
+	var tokenRequests atomic.Int32
+	ts := newTokenServer(&tokenRequests)
+	defer ts.Close()
+
+	c := getTestClientWithCredentials(t, ts.URL)
+	_, err := c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), tokenRequests.Load())
+	// the token is about to expire
+	c.setAuthResponse(&AuthResponse{
+		AccessToken: c.getAccessToken(),
+		ExpiresAt:   time.Now().Add(time.Minute),
+	})
+	getRolesConcurrently(t, c)
+	require.Equal(t, int32(2), tokenRequests.Load())
+}
+
+func TestTokenRefreshOnUnauthorized(t *testing.T) {
+	var tokenRequests atomic.Int32
+	ts := newTokenServer(&tokenRequests)
+	defer ts.Close()
+
+	c := getTestClientWithCredentials(t, ts.URL)
+	// not expired but no longer accepted by the server
+	c.setAuthResponse(&AuthResponse{
+		AccessToken: "revoked",
+		ExpiresAt:   time.Now().Add(20 * time.Minute),
+	})
+	getRolesConcurrently(t, c)
+	require.Equal(t, int32(1), tokenRequests.Load())
+	require.Equal(t, "token1", c.getAccessToken())
+}
+
+func TestSignInError(t *testing.T) {
+	var tokenRequests atomic.Int32
+	ts := newTokenServer(&tokenRequests)
+	defer ts.Close()
+
+	serverURL := ts.URL
+	username := "admin"
+	password := "wrong"
+	c, err := NewClient(&serverURL, &username, &password, nil, nil)
+	require.NoError(t, err)
+	_, err = c.GetRole(context.Background(), "role")
+	require.ErrorContains(t, err, "status: 401")
+	require.Equal(t, int32(0), tokenRequests.Load())
+}
+
+func TestBasePath(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sftpgo" + authEndpoint:
+			resp, _ := json.Marshal(AuthResponse{
+				AccessToken: "token",
+				ExpiresAt:   time.Now().Add(20 * time.Minute),
+			})
+			_, _ = w.Write(resp)
+		case "/sftpgo/api/v2/roles/role":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"name":"role"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	type testCase struct {
+		host     string
+		basePath string
+	}
+	tests := map[string]testCase{
+		"prefix in host":                     {host: ts.URL + "/sftpgo"},
+		"prefix in host with trailing slash": {host: ts.URL + "/sftpgo/"},
+		"base path":                          {host: ts.URL, basePath: "sftpgo"},
+		"base path with slashes":             {host: ts.URL + "/", basePath: "/sftpgo/"},
+	}
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			c := getTestClientWithCredentials(t, test.host)
+			c.SetBasePath(test.basePath)
+			require.Equal(t, ts.URL+"/sftpgo", c.HostURL)
+			role, err := c.GetRole(context.Background(), "role")
+			require.NoError(t, err)
+			require.Equal(t, "role", role.Name)
+		})
+	}
+
+	c := getTestClientWithCredentials(t, ts.URL)
+	_, err := c.GetRole(context.Background(), "role")
+	require.ErrorContains(t, err, "status: 404")
+}
+
+func TestUserAgent(t *testing.T) {
+	var userAgents []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		switch r.URL.Path {
+		case authEndpoint:
+			resp, _ := json.Marshal(AuthResponse{
+				AccessToken: "token",
+				ExpiresAt:   time.Now().Add(20 * time.Minute),
+			})
+			_, _ = w.Write(resp)
+		default:
+			_, _ = w.Write([]byte(`{"name":"role"}`))
+		}
+	}))
+	defer ts.Close()
+
+	c := getTestClientWithCredentials(t, ts.URL)
+	require.Equal(t, DefaultUserAgent, c.UserAgent)
+	c.UserAgent = "Terraform/1.9.0 terraform-provider-sftpgo/1.0.0"
+	_, err := c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+	// the sign in and the API request
+	require.Equal(t, []string{c.UserAgent, c.UserAgent}, userAgents)
+	// a configured header takes precedence
+	c.Headers = []KeyValue{{Key: "User-Agent", Value: "custom"}}
+	_, err = c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+	require.Equal(t, "custom", userAgents[len(userAgents)-1])
+}
+
+func getUnreachableURL(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return "http://" + addr
+}
+
+func TestFallbackHosts(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/sftpgo/api/v2/roles/role" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name":"role"}`))
+	}))
+	defer ts.Close()
+
+	c := getTestClient(getUnreachableURL(t), 0)
+	c.SetFallbackHosts([]string{getUnreachableURL(t), ts.URL + "/"})
+	c.SetBasePath("sftpgo")
+	role, err := c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+	require.Equal(t, "role", role.Name)
+	require.Equal(t, int32(1), requests.Load())
+	// the configured host is tried first for each request
+	role, err = c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+	require.Equal(t, "role", role.Name)
+	require.Equal(t, int32(2), requests.Load())
+
+	c.SetFallbackHosts(nil)
+	_, err = c.GetRole(context.Background(), "role")
+	require.Error(t, err)
+	require.Equal(t, int32(2), requests.Load())
+}
+
+func TestFallbackHostsRequestBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil || len(body) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	}))
+	defer ts.Close()
+
+	c := getTestClient(getUnreachableURL(t), 0)
+	c.SetFallbackHosts([]string{ts.URL})
+	role, err := c.CreateRole(context.Background(), Role{Name: "role"})
+	require.NoError(t, err)
+	require.Equal(t, "role", role.Name)
+}
+
+func TestFallbackHostsHTTPError(t *testing.T) {
+	var fallbackRequests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fallbackRequests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fallback.Close()
+
+	// the host is reachable, HTTP errors must not trigger a failover
+	c := getTestClient(ts.URL, 0)
+	c.SetFallbackHosts([]string{fallback.URL})
+	_, err := c.GetRole(context.Background(), "role")
+	require.ErrorContains(t, err, "status: 500")
+	require.Equal(t, int32(0), fallbackRequests.Load())
+}
+
+func TestRequestContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetRole(ctx, "role")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	// the retry wait is interrupted if the context is done
+	err = sleepWithContext(ctx, time.Minute)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGetAPIError(t *testing.T) {
+	type testCase struct {
+		statusCode int
+		body       string
+		expected   *APIError
+	}
+	tests := map[string]testCase{
+		"validation error": {
+			statusCode: http.StatusBadRequest,
+			body:       `{"error":"Validation error: home_dir must be an absolute path, actual: home","message":""}`,
+			expected: &APIError{
+				StatusCode: http.StatusBadRequest,
+				Error:      "Validation error: home_dir must be an absolute path, actual: home",
+			},
+		},
+		"error and message": {
+			statusCode: http.StatusConflict,
+			body:       `{"error":"conflict","message":"the role is referenced"}`,
+			expected: &APIError{
+				StatusCode: http.StatusConflict,
+				Error:      "conflict",
+				Message:    "the role is referenced",
+			},
+		},
+		"only message": {
+			statusCode: http.StatusNotFound,
+			body:       `{"message":"Not Found"}`,
+			expected: &APIError{
+				StatusCode: http.StatusNotFound,
+				Message:    "Not Found",
+			},
+		},
+		"plain text body": {
+			statusCode: http.StatusBadGateway,
+			body:       "Bad Gateway",
+		},
+		"empty body": {
+			statusCode: http.StatusInternalServerError,
+		},
+		"unrelated JSON": {
+			statusCode: http.StatusBadRequest,
+			body:       `{"name":"role"}`,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(test.statusCode)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer ts.Close()
+
+			c := getTestClient(ts.URL, 0)
+			_, err := c.GetRole(context.Background(), "role")
+			require.Error(t, err)
+			require.Equal(t, test.expected, GetAPIError(err))
+		})
+	}
+	require.Nil(t, GetAPIError(fmt.Errorf("network error")))
+}
+
+func TestUpdateKeepsUnknownFields(t *testing.T) {
+	var updateBody map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v2/version":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"version":"2.7.0"}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"username":"user","description":"old","status":1,"last_login":1700000000000,
+				"used_quota_size":1024,"future_field":"value","filters":{"start_directory":"/old","future_filter":true}}`))
+		case r.Method == http.MethodPut:
+			err := json.NewDecoder(r.Body).Decode(&updateBody)
+			require.NoError(t, err)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 0)
+	user := User{}
+	user.Username = "user"
+	user.Description = "new"
+	user.Status = 1
+	err := c.UpdateUser(context.Background(), user)
+	require.NoError(t, err)
+	// the fields unknown to the provider are sent unchanged
+	require.Equal(t, "value", updateBody["future_field"])
+	filters, ok := updateBody["filters"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, true, filters["future_filter"])
+	// the known fields are not preserved
+	require.Equal(t, "new", updateBody["description"])
+	require.NotContains(t, filters, "start_directory")
+	// read only fields are not sent
+	require.NotContains(t, updateBody, "last_login")
+	require.NotContains(t, updateBody, "used_quota_size")
+}
+
+func TestUpdateRuleKeepsUnknownFields(t *testing.T) {
+	var updateBody map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v2/version":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"version":"2.7.0-dev"}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"name":"rule","status":1,"trigger":1,"created_at":1700000000000,
+				"conditions":{"fs_events":["upload"],"future_condition":"value","options":{"future_option":1}},
+				"actions":[{"name":"action","order":1}]}`))
+		case r.Method == http.MethodPut:
+			err := json.NewDecoder(r.Body).Decode(&updateBody)
+			require.NoError(t, err)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 0)
+	err := c.UpdateRule(context.Background(), EventRule{
+		Name:    "rule",
+		Status:  0,
+		Trigger: 1,
+		Conditions: EventRuleConditions{
+			FsEvents: []string{"upload"},
+		},
+		Actions: []EventAction{{Name: "action", Order: 1}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, float64(0), updateBody["status"])
+	conditions, ok := updateBody["conditions"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, "value", conditions["future_condition"])
+	options, ok := conditions["options"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, float64(1), options["future_option"])
+	require.NotContains(t, updateBody, "created_at")
+}
+
+func TestUpdateSupportedServer(t *testing.T) {
+	var requests []string
+	var updateBody map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"version":"2.6.2"}`))
+		case http.MethodPut:
+			err := json.NewDecoder(r.Body).Decode(&updateBody)
+			require.NoError(t, err)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 0)
+	user := User{}
+	user.Username = "user"
+	user.Status = 1
+	user.LastLogin = 1700000000000
+	for i := 0; i < 2; i++ {
+		err := c.UpdateUser(context.Background(), user)
+		require.NoError(t, err)
+	}
+	// the SDK defines all the fields, the current user is not read and the
+	// server version is checked only once
+	require.Equal(t, []string{"GET /api/v2/version", "PUT /api/v2/users/user", "PUT /api/v2/users/user"}, requests)
+	require.Equal(t, "user", updateBody["username"])
+	require.NotContains(t, updateBody, "last_login")
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	testCases := []struct {
+		version  string
+		expected bool
+	}{
+		{"2.6.0", false},
+		{"2.6.2", false},
+		{"v2.6.4", false},
+		{"2.5.6", false},
+		{"1.2.2", false},
+		{"2.6.99-dev", false},
+		{"2.7.0", true},
+		{"2.7.0-dev", true},
+		{"2.10.1", true},
+		{"3.0.0", true},
+		{"", true},
+		{"unknown", true},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, isNewerVersion(tc.version, SupportedServerVersion), tc.version)
+	}
+}
+
+func TestFolderQuotaScans(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/quotas/folders/scans":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`[{"name":"folder 1","start_time":1700000000000}]`))
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/api/v2/quotas/folders/folder%201/scan":
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusConflict)
+		}
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 0)
+	scans, err := c.GetFolderQuotaScans(context.Background())
+	require.NoError(t, err)
+	require.Len(t, scans, 1)
+	require.Equal(t, "folder 1", scans[0].Name)
+	require.Equal(t, int64(1700000000000), scans[0].StartTime)
+	err = c.StartFolderQuotaScan(context.Background(), "folder 1")
+	require.NoError(t, err)
+	err = c.StartFolderQuotaScan(context.Background(), "folder 2")
+	require.Error(t, err)
+}
+
+func TestGetAPIKeysPagination(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		require.NoError(t, err)
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		require.NoError(t, err)
+		var keys []APIKey
+		for i := offset; i < offset+limit && i < apiKeysPageSize+1; i++ {
+			keys = append(keys, APIKey{ID: strconv.Itoa(i), Scope: APIKeyScopeAdmin})
+		}
+		w.WriteHeader(http.StatusOK)
+		require.NoError(t, json.NewEncoder(w).Encode(keys))
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 0)
+	keys, err := c.GetAPIKeys(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keys, apiKeysPageSize+1)
+	require.Equal(t, strconv.Itoa(apiKeysPageSize), keys[apiKeysPageSize].ID)
+}
+
+func TestRequestLogRedaction(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == authEndpoint {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"access_token":"secrettoken","expires_at":"2100-01-01T00:00:00Z"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"username":"user","password":"$2a$10$hash","filesystem":{"provider":1,
+			"s3config":{"bucket":"b","access_key":"ak","access_secret":{"status":"Plain","payload":"secretpayload"}}}}`))
+	}))
+	defer ts.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	username := "admin"
+	password := "adminpassword"
+	c, err := NewClient(&ts.URL, &username, &password, nil, nil)
+	require.NoError(t, err)
+	_, err = c.CreateUser(ctx, User{
+		User: sdk.User{
+			BaseUser: sdk.BaseUser{
+				Username: "user",
+			},
+		},
+		Password: "userpassword",
+	})
+	require.NoError(t, err)
+
+	logs := output.String()
+	for _, secret := range []string{"adminpassword", "userpassword", "secrettoken", "secretpayload", "$2a$10$hash"} {
+		require.NotContains(t, logs, secret)
+	}
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	require.NoError(t, err)
+	var found, foundBodies bool
+	for _, entry := range entries {
+		if entry["@message"] == "SFTPGo API request" && entry["path"] == "/api/v2/users" {
+			found = true
+			require.Equal(t, http.MethodPost, entry["method"])
+			require.Equal(t, float64(http.StatusCreated), entry["status_code"])
+			require.Contains(t, entry, "duration_ms")
+		}
+		if entry["@message"] == "SFTPGo API request and response bodies" && entry["path"] == "/api/v2/users" {
+			foundBodies = true
+			require.Contains(t, entry["request_body"], `"password":"***"`)
+			require.Contains(t, entry["response_body"], `"payload":"***"`)
+			require.Contains(t, entry["response_body"], `"access_key":"ak"`)
+		}
+	}
+	require.True(t, found)
+	require.True(t, foundBodies)
+}
+
+func TestRedactBody(t *testing.T) {
+	require.Equal(t, "", redactBody(nil))
+	require.Equal(t, "<5 bytes omitted, not JSON>", redactBody([]byte("hello")))
+	require.Equal(t, `[{"key":"***","value":"***"}]`, redactBody([]byte(`[{"key":"Authorization","value":"Bearer x"}]`)))
+	require.Equal(t, `{"password":"","username":"u"}`, redactBody([]byte(`{"username":"u","password":""}`)))
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// Valid values for the filesystem options with a fixed set of values.
+// Empty values, not included here, mean the backend default.
+var (
+	// S3StorageClasses are the storage classes supported by AWS S3
+	S3StorageClasses = []string{"STANDARD", "REDUCED_REDUNDANCY", "STANDARD_IA", "ONEZONE_IA",
+		"INTELLIGENT_TIERING", "GLACIER", "GLACIER_IR", "DEEP_ARCHIVE", "OUTPOSTS", "SNOW", "EXPRESS_ONEZONE"}
+	// S3CannedACLs are the canned ACLs supported by AWS S3
+	S3CannedACLs = []string{"private", "public-read", "public-read-write", "authenticated-read", "aws-exec-read",
+		"bucket-owner-read", "bucket-owner-full-control"}
+	// GCSStorageClasses are the storage classes supported by Google Cloud Storage
+	GCSStorageClasses = []string{"STANDARD", "NEARLINE", "COLDLINE", "ARCHIVE", "MULTI_REGIONAL", "REGIONAL",
+		"DURABLE_REDUCED_AVAILABILITY"}
+	// GCSPredefinedACLs are the predefined object ACLs supported by Google Cloud Storage
+	GCSPredefinedACLs = []string{"authenticatedRead", "bucketOwnerFullControl", "bucketOwnerRead", "private",
+		"projectPrivate", "publicRead"}
+	// AzBlobAccessTiers are the Azure Blob access tiers supported by SFTPGo
+	AzBlobAccessTiers = []string{"Hot", "Cool", "Archive"}
+)
@@ -16,6 +16,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -25,8 +26,8 @@ import (
 )
 
 // GetFolders - Returns list of folders
-func (c *Client) GetFolders() ([]sdk.BaseVirtualFolder, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/dumpdata?output-data=1&scopes=folders", c.HostURL), nil)
+func (c *Client) GetFolders(ctx context.Context) ([]sdk.BaseVirtualFolder, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/dumpdata?output-data=1&scopes=folders", c.HostURL), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -45,12 +46,12 @@ func (c *Client) GetFolders() ([]sdk.BaseVirtualFolder, error) {
 }
 
 // CreateFolder - creates a new folder
-func (c *Client) CreateFolder(folder sdk.BaseVirtualFolder) (*sdk.BaseVirtualFolder, error) {
+func (c *Client) CreateFolder(ctx context.Context, folder sdk.BaseVirtualFolder) (*sdk.BaseVirtualFolder, error) {
 	rb, err := json.Marshal(folder)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v2/folders?confidential_data=1", c.HostURL),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v2/folders?confidential_data=1", c.HostURL),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return nil, err
@@ -67,8 +68,8 @@ func (c *Client) CreateFolder(folder sdk.BaseVirtualFolder) (*sdk.BaseVirtualFol
 }
 
 // GetFolder - Returns a specifc folder
-func (c *Client) GetFolder(name string) (*sdk.BaseVirtualFolder, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/folders/%s?confidential_data=1", c.HostURL,
+func (c *Client) GetFolder(ctx context.Context, name string) (*sdk.BaseVirtualFolder, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/folders/%s?confidential_data=1", c.HostURL,
 		url.PathEscape(name)), nil)
 	if err != nil {
 		return nil, err
@@ -84,12 +85,13 @@ func (c *Client) GetFolder(name string) (*sdk.BaseVirtualFolder, error) {
 }
 
 // UpdateFolder - Updates an existing folder
-func (c *Client) UpdateFolder(folder sdk.BaseVirtualFolder) error {
-	rb, err := json.Marshal(folder)
+func (c *Client) UpdateFolder(ctx context.Context, folder sdk.BaseVirtualFolder) error {
+	rb, err := c.marshalForUpdate(ctx, fmt.Sprintf("%s/api/v2/folders/%s?confidential_data=1", c.HostURL, url.PathEscape(folder.Name)),
+		folder)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v2/folders/%s", c.HostURL, url.PathEscape(folder.Name)),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/folders/%s", c.HostURL, url.PathEscape(folder.Name)),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return err
@@ -100,11 +102,85 @@ func (c *Client) UpdateFolder(folder sdk.BaseVirtualFolder) error {
 }
 
 // DeleteFolder - Deletes a folder
-func (c *Client) DeleteFolder(name string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v2/folders/%s", c.HostURL, url.PathEscape(name)), nil)
+func (c *Client) DeleteFolder(ctx context.Context, name string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v2/folders/%s", c.HostURL, url.PathEscape(name)), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequestWithAuth(req, http.StatusOK)
+	return err
+}
+
+// QuotaUsage defines the quota used by a user or a folder
+type QuotaUsage struct {
+	UsedQuotaSize  int64 `json:"used_quota_size"`
+	UsedQuotaFiles int   `json:"used_quota_files"`
+}
+
+// UpdateFolderQuotaUsage - Sets the quota used by the specified folder
+func (c *Client) UpdateFolderQuotaUsage(ctx context.Context, name string, usage QuotaUsage) error {
+	rb, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/quotas/folders/%s/usage", c.HostURL,
+		url.PathEscape(name)), bytes.NewBuffer(rb))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequestWithAuth(req, http.StatusOK)
+	return err
+}
+
+// UpdateUserQuotaUsage - Sets the quota used by the specified user
+func (c *Client) UpdateUserQuotaUsage(ctx context.Context, username string, usage QuotaUsage) error {
+	rb, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/quotas/users/%s/usage", c.HostURL,
+		url.PathEscape(username)), bytes.NewBuffer(rb))
 	if err != nil {
 		return err
 	}
+
 	_, err = c.doRequestWithAuth(req, http.StatusOK)
 	return err
 }
+
+// FolderQuotaScan defines an active quota scan for a virtual folder
+type FolderQuotaScan struct {
+	Name      string `json:"name"`
+	StartTime int64  `json:"start_time"`
+}
+
+// GetFolderQuotaScans - Returns the active quota scans for virtual folders
+func (c *Client) GetFolderQuotaScans(ctx context.Context) ([]FolderQuotaScan, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/quotas/folders/scans", c.HostURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequestWithAuth(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var scans []FolderQuotaScan
+	err = json.Unmarshal(body, &scans)
+	return scans, err
+}
+
+// StartFolderQuotaScan - Starts a quota scan for the specified folder.
+// The scan runs in the background
+func (c *Client) StartFolderQuotaScan(ctx context.Context, name string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v2/quotas/folders/%s/scan", c.HostURL,
+		url.PathEscape(name)), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequestWithAuth(req, http.StatusAccepted)
+	return err
+}
@@ -16,6 +16,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -25,8 +26,8 @@ import (
 )
 
 // GetGroups - Returns list of groups
-func (c *Client) GetGroups() ([]sdk.Group, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/dumpdata?output-data=1&scopes=groups", c.HostURL), nil)
+func (c *Client) GetGroups(ctx context.Context) ([]sdk.Group, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/dumpdata?output-data=1&scopes=groups", c.HostURL), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -45,12 +46,12 @@ func (c *Client) GetGroups() ([]sdk.Group, error) {
 }
 
 // CreateGroup - creates a new group
-func (c *Client) CreateGroup(group sdk.Group) (*sdk.Group, error) {
+func (c *Client) CreateGroup(ctx context.Context, group sdk.Group) (*sdk.Group, error) {
 	rb, err := json.Marshal(group)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v2/groups?confidential_data=1", c.HostURL),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v2/groups?confidential_data=1", c.HostURL),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return nil, err
@@ -67,8 +68,8 @@ func (c *Client) CreateGroup(group sdk.Group) (*sdk.Group, error) {
 }
 
 // GetGroup - Returns a specifc group
-func (c *Client) GetGroup(name string) (*sdk.Group, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/groups/%s?confidential_data=1", c.HostURL,
+func (c *Client) GetGroup(ctx context.Context, name string) (*sdk.Group, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/groups/%s?confidential_data=1", c.HostURL,
 		url.PathEscape(name)), nil)
 	if err != nil {
 		return nil, err
@@ -84,12 +85,13 @@ func (c *Client) GetGroup(name string) (*sdk.Group, error) {
 }
 
 // UpdateGroup - Updates an existing group
-func (c *Client) UpdateGroup(group sdk.Group) error {
-	rb, err := json.Marshal(group)
+func (c *Client) UpdateGroup(ctx context.Context, group sdk.Group) error {
+	rb, err := c.marshalForUpdate(ctx, fmt.Sprintf("%s/api/v2/groups/%s?confidential_data=1", c.HostURL, url.PathEscape(group.Name)),
+		group)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v2/groups/%s", c.HostURL, url.PathEscape(group.Name)),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/groups/%s", c.HostURL, url.PathEscape(group.Name)),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return err
@@ -100,8 +102,8 @@ func (c *Client) UpdateGroup(group sdk.Group) error {
 }
 
 // DeleteGroup - Deletes a group
-func (c *Client) DeleteGroup(name string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v2/groups/%s", c.HostURL, url.PathEscape(name)), nil)
+func (c *Client) DeleteGroup(ctx context.Context, name string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v2/groups/%s", c.HostURL, url.PathEscape(name)), nil)
 	if err != nil {
 		return err
 	}
@@ -16,6 +16,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -43,13 +44,13 @@ type IPListEntry struct {
 }
 
 // GetIPListEntries - Returns entries for the specified IP list type
-func (c *Client) GetIPListEntries(listType int) ([]IPListEntry, error) {
+func (c *Client) GetIPListEntries(ctx context.Context, listType int) ([]IPListEntry, error) {
 	var result []IPListEntry
 	limit := 100
 	from := ""
 
 	for {
-		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/iplists/%d?limit=%d&from=%s",
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/iplists/%d?limit=%d&from=%s",
 			c.HostURL, listType, limit, url.QueryEscape(from)), nil)
 		if err != nil {
 			return nil, err
@@ -76,12 +77,12 @@ func (c *Client) GetIPListEntries(listType int) ([]IPListEntry, error) {
 }
 
 // CreateIPListEntry - Creates a new IP list entry
-func (c *Client) CreateIPListEntry(entry IPListEntry) (*IPListEntry, error) {
+func (c *Client) CreateIPListEntry(ctx context.Context, entry IPListEntry) (*IPListEntry, error) {
 	rb, err := json.Marshal(entry)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v2/iplists/%d", c.HostURL, entry.Type), bytes.NewBuffer(rb))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v2/iplists/%d", c.HostURL, entry.Type), bytes.NewBuffer(rb))
 	if err != nil {
 		return nil, err
 	}
@@ -91,12 +92,12 @@ func (c *Client) CreateIPListEntry(entry IPListEntry) (*IPListEntry, error) {
 		return nil, err
 	}
 
-	return c.GetIPListEntry(entry.Type, entry.IPOrNet)
+	return c.GetIPListEntry(ctx, entry.Type, entry.IPOrNet)
 }
 
 // GetIPListEntry - Returns a specifc IP list entry
-func (c *Client) GetIPListEntry(listType int, ipOrNet string) (*IPListEntry, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/iplists/%d/%s", c.HostURL, listType, url.PathEscape(ipOrNet)), nil)
+func (c *Client) GetIPListEntry(ctx context.Context, listType int, ipOrNet string) (*IPListEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/iplists/%d/%s", c.HostURL, listType, url.PathEscape(ipOrNet)), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -111,12 +112,12 @@ func (c *Client) GetIPListEntry(listType int, ipOrNet string) (*IPListEntry, err
 }
 
 // UpdateIPListEntry - Updates an existing IP list entru
-func (c *Client) UpdateIPListEntry(entry IPListEntry) error {
+func (c *Client) UpdateIPListEntry(ctx context.Context, entry IPListEntry) error {
 	rb, err := json.Marshal(entry)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v2/iplists/%d/%s",
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/iplists/%d/%s",
 		c.HostURL, entry.Type, url.PathEscape(entry.IPOrNet)), bytes.NewBuffer(rb))
 	if err != nil {
 		return err
@@ -127,8 +128,8 @@ func (c *Client) UpdateIPListEntry(entry IPListEntry) error {
 }
 
 // DeleteIPListEntry - Deletes an IP list entry
-func (c *Client) DeleteIPListEntry(listType int, ipOrNet string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v2/iplists/%d/%s",
+func (c *Client) DeleteIPListEntry(ctx context.Context, listType int, ipOrNet string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v2/iplists/%d/%s",
 		c.HostURL, listType, url.PathEscape(ipOrNet)), nil)
 	if err != nil {
 		return err
@@ -0,0 +1,72 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetIPListEntriesPagination(t *testing.T) {
+	var entries []IPListEntry
+	for i := 0; i < 250; i++ {
+		entries = append(entries, IPListEntry{
+			IPOrNet:     fmt.Sprintf("10.%d.%d.0/24", i/256, i%256),
+			Description: fmt.Sprintf("entry %d", i),
+			Type:        3,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].IPOrNet < entries[j].IPOrNet
+	})
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/api/v2/iplists/3" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		from := r.URL.Query().Get("from")
+		page := []IPListEntry{}
+		for _, entry := range entries {
+			if entry.IPOrNet > from && len(page) < limit {
+				page = append(page, entry)
+			}
+		}
+		resp, _ := json.Marshal(page)
+		_, _ = w.Write(resp)
+	}))
+	defer ts.Close()
+
+	c := getTestClient(ts.URL, 0)
+	result, err := c.GetIPListEntries(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, entries, result)
+	require.Equal(t, 3, requests)
+}
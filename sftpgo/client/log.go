@@ -0,0 +1,131 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const redactedValue = "***"
+
+// sensitiveLogFields lists the JSON fields never included in the logs. The
+// secrets are sent as objects with a "payload" field, the API keys as "key"
+var sensitiveLogFields = []string{"password", "payload", "access_token", "key", "passphrase", "client_secret",
+	"secret"}
+
+// logRequest logs the method, path, status code and duration of a request.
+// The request and response bodies are only logged at trace level and the
+// sensitive fields are redacted. The authentication headers are never
+// logged. Logs are emitted only if the request context has a logger, for
+// example the context passed by Terraform to the resource methods.
+func logRequest(req *http.Request, res *http.Response, body []byte, start time.Time, attempts int, err error) {
+	ctx := req.Context()
+	fields := map[string]any{
+		"method":      req.Method,
+		"path":        req.URL.Path,
+		"duration_ms": time.Since(start).Milliseconds(),
+		"attempts":    attempts,
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "SFTPGo API request failed", fields)
+		return
+	}
+	fields["status_code"] = res.StatusCode
+	tflog.Debug(ctx, "SFTPGo API request", fields)
+
+	tflog.Trace(ctx, "SFTPGo API request and response bodies", map[string]any{
+		"method":        req.Method,
+		"path":          req.URL.Path,
+		"request_body":  redactBody(getRequestBody(req)),
+		"response_body": redactBody(body),
+	})
+}
+
+// getRequestBody returns a copy of the request body, if any.
+func getRequestBody(req *http.Request) []byte {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// redactBody returns the JSON encoded body with the sensitive fields
+// redacted. Bodies that are not JSON encoded are omitted.
+func redactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Sprintf("<%d bytes omitted, not JSON>", len(body))
+	}
+	redactValue(data)
+	redacted, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Sprintf("<%d bytes omitted>", len(body))
+	}
+	return string(redacted)
+}
+
+func redactValue(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		// key/value pairs, for example HTTP headers, may contain
+		// credentials in the value
+		_, isKeyValue := v["key"]
+		for name, fieldValue := range v {
+			if isSensitiveLogField(name) || (isKeyValue && name == "value") {
+				if fieldValue != nil && fieldValue != "" {
+					v[name] = redactedValue
+				}
+				continue
+			}
+			redactValue(fieldValue)
+		}
+	case []any:
+		for _, item := range v {
+			redactValue(item)
+		}
+	}
+}
+
+func isSensitiveLogField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range sensitiveLogFields {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SupportedServerVersion is the most recent SFTPGo version, as major.minor,
+// whose objects are fully defined in the SDK used by the provider
+const SupportedServerVersion = "2.6"
+
+// userReadOnlyFields are managed by SFTPGo and never sent on updates
+var userReadOnlyFields = []string{"used_quota_size", "used_quota_files", "used_upload_data_transfer",
+	"used_download_data_transfer", "last_quota_update", "last_login", "first_download", "first_upload",
+	"last_password_change", "created_at", "updated_at"}
+
+// serverInfo caches the SFTPGo server properties used for updates
+type serverInfo struct {
+	mu sync.Mutex
+	// isNewer is nil until the server version is known
+	isNewer *bool
+}
+
+// marshalForUpdate returns the JSON encoding of obj to use for updates.
+// If the SFTPGo server is newer than SupportedServerVersion, the fields of
+// the object returned by getURL that obj does not define are sent unchanged,
+// so they are not reset. Nested objects are merged the same way, lists are
+// not. The read only fields are removed
+func (c *Client) marshalForUpdate(ctx context.Context, getURL string, obj any, readOnlyFields ...string) ([]byte, error) {
+	rb, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var update map[string]any
+	if err := json.Unmarshal(rb, &update); err != nil {
+		return nil, err
+	}
+	for _, name := range readOnlyFields {
+		delete(update, name)
+	}
+	isNewer, err := c.isNewerServer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isNewer {
+		return json.Marshal(update)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, getURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.doRequestWithAuth(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var current map[string]any
+	if err := json.Unmarshal(body, &current); err != nil {
+		return nil, err
+	}
+	for _, name := range readOnlyFields {
+		delete(current, name)
+	}
+	mergeUnknownFields(update, current, reflect.TypeOf(obj))
+	return json.Marshal(update)
+}
+
+// isNewerServer reports whether the SFTPGo server is newer than
+// SupportedServerVersion. The result is cached
+func (c *Client) isNewerServer(ctx context.Context) (bool, error) {
+	if c.server != nil {
+		c.server.mu.Lock()
+		defer c.server.mu.Unlock()
+
+		if c.server.isNewer != nil {
+			return *c.server.isNewer, nil
+		}
+	}
+	info, err := c.GetVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	isNewer := isNewerVersion(info.Version, SupportedServerVersion)
+	if c.server != nil {
+		c.server.isNewer = &isNewer
+	}
+	return isNewer, nil
+}
+
+// isNewerVersion reports whether the major.minor part of version is greater
+// than supported. Versions that cannot be parsed are considered newer
+func isNewerVersion(version, supported string) bool {
+	major, minor, ok := parseMajorMinor(version)
+	if !ok {
+		return true
+	}
+	supportedMajor, supportedMinor, _ := parseMajorMinor(supported)
+	if major != supportedMajor {
+		return major > supportedMajor
+	}
+	return minor > supportedMinor
+}
+
+// parseMajorMinor returns the major and minor numbers of a version such as
+// "2.6.2", "v2.6.0" or "2.7.0-dev"
+func parseMajorMinor(version string) (int, int, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, _, _ := strings.Cut(parts[1], "-")
+	minorNum, err := strconv.Atoi(minor)
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minorNum, true
+}
+
+// mergeUnknownFields adds to update the fields of current not defined in t
+func mergeUnknownFields(update, current map[string]any, t reflect.Type) {
+	known := getJSONFields(t)
+	for name, value := range current {
+		fieldType, ok := known[name]
+		if !ok {
+			if _, ok := update[name]; !ok {
+				update[name] = value
+			}
+			continue
+		}
+		if fieldType.Kind() != reflect.Struct {
+			continue
+		}
+		updateValue, ok := update[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		if currentValue, ok := value.(map[string]any); ok {
+			mergeUnknownFields(updateValue, currentValue, fieldType)
+		}
+	}
+}
+
+// getJSONFields returns the JSON encoded field names of t and their types,
+// the fields of embedded structs are included
+func getJSONFields(t reflect.Type) map[string]reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fields := make(map[string]reflect.Type)
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			for k, v := range getJSONFields(fieldType) {
+				if _, ok := fields[k]; !ok {
+					fields[k] = v
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = fieldType
+	}
+	return fields
+}
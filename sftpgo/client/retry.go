@@ -0,0 +1,115 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const maxRetryWait = 60 * time.Second
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether a failed request can be safely retried.
+// 429 and 503 responses mean that SFTPGo did not process the request, so
+// they are retried for any method. Network errors and other transient
+// gateway errors are retried for idempotent requests only.
+func shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	if err != nil {
+		return isIdempotentMethod(req.Method)
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotentMethod(req.Method)
+	default:
+		return false
+	}
+}
+
+// RetryOnTransportError calls fn and, if it fails with a transport error,
+// calls it again up to RetryMax times, waiting as for the other retries.
+// Non idempotent requests are never retried after transport errors by the
+// client itself, so the caller must make sure that fn can be safely repeated,
+// for example by handling the conflict returned if the object was already
+// created. retried reports whether fn was called more than once.
+func (c *Client) RetryOnTransportError(ctx context.Context, fn func() error) (retried bool, err error) {
+	err = fn()
+	for attempt := 0; attempt < c.RetryMax && IsTransportError(err); attempt++ {
+		if err := sleepWithContext(ctx, c.getRetryWait(attempt, nil)); err != nil {
+			return retried, err
+		}
+		retried = true
+		err = fn()
+	}
+	return retried, err
+}
+
+// getRetryWait returns the time to wait before the next attempt.
+// The Retry-After header, if any, takes precedence over the exponential backoff.
+func (c *Client) getRetryWait(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if wait, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return min(wait, maxRetryWait)
+		}
+	}
+	wait := c.RetryWait
+	for i := 0; i < attempt && wait < maxRetryWait; i++ {
+		wait *= 2
+	}
+	return min(wait, maxRetryWait)
+}
+
+// parseRetryAfter parses a Retry-After header value expressed as
+// delay in seconds or as HTTP date.
+func parseRetryAfter(val string) (time.Duration, bool) {
+	if val == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(val); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(val); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// rewindRequestBody restores the request body so it can be sent again.
+func rewindRequestBody(req *http.Request) error {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
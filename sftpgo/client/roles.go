@@ -16,6 +16,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -32,15 +33,18 @@ type Role struct {
 	CreatedAt int64 `json:"created_at"`
 	// last update time as unix timestamp in milliseconds
 	UpdatedAt int64 `json:"updated_at"`
+	// Admins and users with this role, they are maintained by SFTPGo
+	Admins []string `json:"admins,omitempty"`
+	Users  []string `json:"users,omitempty"`
 }
 
 // GetRoles - Returns list of roles
-func (c *Client) GetRoles() ([]Role, error) {
+func (c *Client) GetRoles(ctx context.Context) ([]Role, error) {
 	var result []Role
 	limit := 100
 
 	for {
-		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/roles?limit=%d&offset=%d", c.HostURL, limit, len(result)), nil)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/roles?limit=%d&offset=%d", c.HostURL, limit, len(result)), nil)
 		if err != nil {
 			return nil, err
 		}
@@ -65,12 +69,12 @@ func (c *Client) GetRoles() ([]Role, error) {
 }
 
 // CreateRole - Creates a new role
-func (c *Client) CreateRole(role Role) (*Role, error) {
+func (c *Client) CreateRole(ctx context.Context, role Role) (*Role, error) {
 	rb, err := json.Marshal(role)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v2/roles", c.HostURL), bytes.NewBuffer(rb))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v2/roles", c.HostURL), bytes.NewBuffer(rb))
 	if err != nil {
 		return nil, err
 	}
@@ -86,8 +90,8 @@ func (c *Client) CreateRole(role Role) (*Role, error) {
 }
 
 // GetRole - Returns a specifc role
-func (c *Client) GetRole(name string) (*Role, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/roles/%s", c.HostURL, url.PathEscape(name)), nil)
+func (c *Client) GetRole(ctx context.Context, name string) (*Role, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/roles/%s", c.HostURL, url.PathEscape(name)), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -102,12 +106,12 @@ func (c *Client) GetRole(name string) (*Role, error) {
 }
 
 // UpdateRole - Updates an existing role
-func (c *Client) UpdateRole(role Role) error {
+func (c *Client) UpdateRole(ctx context.Context, role Role) error {
 	rb, err := json.Marshal(role)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v2/roles/%s", c.HostURL, url.PathEscape(role.Name)),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/roles/%s", c.HostURL, url.PathEscape(role.Name)),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return err
@@ -118,8 +122,8 @@ func (c *Client) UpdateRole(role Role) error {
 }
 
 // DeleteRole - Deletes a role
-func (c *Client) DeleteRole(name string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v2/roles/%s", c.HostURL, url.PathEscape(name)), nil)
+func (c *Client) DeleteRole(ctx context.Context, name string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v2/roles/%s", c.HostURL, url.PathEscape(name)), nil)
 	if err != nil {
 		return err
 	}
@@ -16,6 +16,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -81,6 +82,9 @@ type EventAction struct {
 	Options EventActionRelationOptions `json:"relation_options"`
 }
 
+// ruleReadOnlyFields are managed by SFTPGo and never sent on updates
+var ruleReadOnlyFields = []string{"created_at", "updated_at"}
+
 // EventRule defines the trigger, conditions and actions for an event
 type EventRule struct {
 	// Rule name
@@ -102,12 +106,12 @@ type EventRule struct {
 }
 
 // GetActions - Returns list of actions
-func (c *Client) GetRules() ([]EventRule, error) {
+func (c *Client) GetRules(ctx context.Context) ([]EventRule, error) {
 	var result []EventRule
 	limit := 100
 
 	for {
-		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/eventrules?limit=%d&offset=%d",
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/eventrules?limit=%d&offset=%d",
 			c.HostURL, limit, len(result)), nil)
 		if err != nil {
 			return nil, err
@@ -133,12 +137,12 @@ func (c *Client) GetRules() ([]EventRule, error) {
 }
 
 // CreateRule - Creates a new rule
-func (c *Client) CreateRule(rule EventRule) (*EventRule, error) {
+func (c *Client) CreateRule(ctx context.Context, rule EventRule) (*EventRule, error) {
 	rb, err := json.Marshal(rule)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v2/eventrules", c.HostURL), bytes.NewBuffer(rb))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v2/eventrules", c.HostURL), bytes.NewBuffer(rb))
 	if err != nil {
 		return nil, err
 	}
@@ -154,8 +158,8 @@ func (c *Client) CreateRule(rule EventRule) (*EventRule, error) {
 }
 
 // GetRule - Returns a specifc role
-func (c *Client) GetRule(name string) (*EventRule, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/eventrules/%s", c.HostURL, url.PathEscape(name)), nil)
+func (c *Client) GetRule(ctx context.Context, name string) (*EventRule, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/eventrules/%s", c.HostURL, url.PathEscape(name)), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -170,12 +174,13 @@ func (c *Client) GetRule(name string) (*EventRule, error) {
 }
 
 // UpdateRule - Updates an existing rule
-func (c *Client) UpdateRule(rule EventRule) error {
-	rb, err := json.Marshal(rule)
+func (c *Client) UpdateRule(ctx context.Context, rule EventRule) error {
+	rb, err := c.marshalForUpdate(ctx, fmt.Sprintf("%s/api/v2/eventrules/%s", c.HostURL, url.PathEscape(rule.Name)),
+		rule, ruleReadOnlyFields...)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v2/eventrules/%s", c.HostURL, url.PathEscape(rule.Name)),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/eventrules/%s", c.HostURL, url.PathEscape(rule.Name)),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return err
@@ -186,8 +191,8 @@ func (c *Client) UpdateRule(rule EventRule) error {
 }
 
 // DeleteRule - Deletes a rule
-func (c *Client) DeleteRule(name string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v2/eventrules/%s", c.HostURL, url.PathEscape(name)), nil)
+func (c *Client) DeleteRule(ctx context.Context, name string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v2/eventrules/%s", c.HostURL, url.PathEscape(name)), nil)
 	if err != nil {
 		return err
 	}
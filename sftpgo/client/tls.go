@@ -0,0 +1,112 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// TLSConfig defines the TLS settings for the SFTPGo API client.
+// Certificates and keys can be provided as PEM encoded data or
+// as paths to PEM encoded files.
+type TLSConfig struct {
+	// CACert is the CA certificate used to verify the SFTPGo server
+	// certificate, the system CAs are used if empty
+	CACert string
+	// ClientCert and ClientKey define the client certificate to use for
+	// mutual TLS authentication
+	ClientCert string
+	ClientKey  string
+	// SkipVerify disables the verification of the server certificate
+	SkipVerify bool
+}
+
+// IsEmpty returns true if no custom TLS setting is defined
+func (c *TLSConfig) IsEmpty() bool {
+	return c.CACert == "" && c.ClientCert == "" && c.ClientKey == "" && !c.SkipVerify
+}
+
+func (c *TLSConfig) getTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.SkipVerify,
+	}
+
+	if c.CACert != "" {
+		caCert, err := readPEM(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the CA certificate: %w", err)
+		}
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("unable to parse the CA certificate, no valid PEM certificate found")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, errors.New("client certificate and client key must be provided together")
+		}
+		clientCert, err := readPEM(c.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the client certificate: %w", err)
+		}
+		clientKey, err := readPEM(c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the client key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the client key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// SetTLSConfig configures the HTTP client to use the specified TLS settings
+func (c *Client) SetTLSConfig(config TLSConfig) error {
+	if config.IsEmpty() {
+		return nil
+	}
+	tlsConfig, err := config.getTLSConfig()
+	if err != nil {
+		return err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	c.HTTPClient.Transport = transport
+	return nil
+}
+
+// readPEM returns the specified value if it contains PEM encoded data,
+// otherwise the value is considered a file path and its content is returned
+func readPEM(val string) ([]byte, error) {
+	if strings.Contains(val, "-----BEGIN ") {
+		return []byte(val), nil
+	}
+	return os.ReadFile(val)
+}
@@ -0,0 +1,199 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+func (c *testCertificate) getTLSCertificate(t *testing.T) tls.Certificate {
+	cert, err := tls.X509KeyPair([]byte(c.certPEM), []byte(c.keyPEM))
+	require.NoError(t, err)
+	return cert
+}
+
+// newTestCertificate generates a certificate signed by the specified parent,
+// a self signed CA certificate is generated if parent is nil
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate, extKeyUsage x509.ExtKeyUsage) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signerCert := template
+	signerKey := key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{extKeyUsage}
+		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+		signerCert = parent.cert
+		signerKey = parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+func newTestTLSServer(t *testing.T, serverCert *testCertificate, clientCA *testCertificate) *httptest.Server {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name":"role"}`))
+	}))
+	ts.TLS = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{serverCert.getTLSCertificate(t)},
+	}
+	if clientCA != nil {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCA.cert)
+		ts.TLS.ClientCAs = pool
+		ts.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestTLSCustomCA(t *testing.T) {
+	ca := newTestCertificate(t, "Test CA", nil, 0)
+	serverCert := newTestCertificate(t, "127.0.0.1", ca, x509.ExtKeyUsageServerAuth)
+	ts := newTestTLSServer(t, serverCert, nil)
+
+	// the server certificate is not trusted by the system CAs
+	c := getTestClient(ts.URL, 0)
+	_, err := c.GetRole(context.Background(), "role")
+	require.Error(t, err)
+	// inline PEM
+	c = getTestClient(ts.URL, 0)
+	err = c.SetTLSConfig(TLSConfig{CACert: ca.certPEM})
+	require.NoError(t, err)
+	role, err := c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+	require.Equal(t, "role", role.Name)
+	// file path
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	err = os.WriteFile(caPath, []byte(ca.certPEM), 0600)
+	require.NoError(t, err)
+	c = getTestClient(ts.URL, 0)
+	err = c.SetTLSConfig(TLSConfig{CACert: caPath})
+	require.NoError(t, err)
+	_, err = c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+	// a different CA must not be trusted
+	otherCA := newTestCertificate(t, "Other CA", nil, 0)
+	c = getTestClient(ts.URL, 0)
+	err = c.SetTLSConfig(TLSConfig{CACert: otherCA.certPEM})
+	require.NoError(t, err)
+	_, err = c.GetRole(context.Background(), "role")
+	require.Error(t, err)
+	// skip verify is a separate option
+	c = getTestClient(ts.URL, 0)
+	err = c.SetTLSConfig(TLSConfig{SkipVerify: true})
+	require.NoError(t, err)
+	_, err = c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	ca := newTestCertificate(t, "Test CA", nil, 0)
+	serverCert := newTestCertificate(t, "127.0.0.1", ca, x509.ExtKeyUsageServerAuth)
+	clientCert := newTestCertificate(t, "client", ca, x509.ExtKeyUsageClientAuth)
+	ts := newTestTLSServer(t, serverCert, ca)
+
+	c := getTestClient(ts.URL, 0)
+	err := c.SetTLSConfig(TLSConfig{CACert: ca.certPEM})
+	require.NoError(t, err)
+	_, err = c.GetRole(context.Background(), "role")
+	require.Error(t, err)
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certPath, []byte(clientCert.certPEM), 0600))
+	require.NoError(t, os.WriteFile(keyPath, []byte(clientCert.keyPEM), 0600))
+	c = getTestClient(ts.URL, 0)
+	err = c.SetTLSConfig(TLSConfig{
+		CACert:     ca.certPEM,
+		ClientCert: certPath,
+		ClientKey:  keyPath,
+	})
+	require.NoError(t, err)
+	role, err := c.GetRole(context.Background(), "role")
+	require.NoError(t, err)
+	require.Equal(t, "role", role.Name)
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	ca := newTestCertificate(t, "Test CA", nil, 0)
+	clientCert := newTestCertificate(t, "client", ca, x509.ExtKeyUsageClientAuth)
+
+	c := getTestClient("https://127.0.0.1", 0)
+	err := c.SetTLSConfig(TLSConfig{})
+	require.NoError(t, err)
+	require.Nil(t, c.HTTPClient.Transport)
+	err = c.SetTLSConfig(TLSConfig{CACert: filepath.Join(t.TempDir(), "missing.pem")})
+	require.ErrorContains(t, err, "unable to read the CA certificate")
+	err = c.SetTLSConfig(TLSConfig{CACert: "-----BEGIN CERTIFICATE-----\ninvalid\n-----END CERTIFICATE-----"})
+	require.ErrorContains(t, err, "unable to parse the CA certificate")
+	err = c.SetTLSConfig(TLSConfig{ClientCert: clientCert.certPEM})
+	require.ErrorContains(t, err, "must be provided together")
+	err = c.SetTLSConfig(TLSConfig{ClientCert: clientCert.certPEM, ClientKey: ca.keyPEM})
+	require.ErrorContains(t, err, "unable to load the client key pair")
+	require.Nil(t, c.HTTPClient.Transport)
+}
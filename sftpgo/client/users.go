@@ -16,6 +16,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -34,8 +35,8 @@ type User struct {
 }
 
 // GetUsers - Returns list of users
-func (c *Client) GetUsers() ([]User, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/dumpdata?output-data=1&scopes=users", c.HostURL), nil)
+func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/dumpdata?output-data=1&scopes=users", c.HostURL), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -55,12 +56,12 @@ func (c *Client) GetUsers() ([]User, error) {
 }
 
 // CreateUser - creates a new user
-func (c *Client) CreateUser(user User) (*User, error) {
+func (c *Client) CreateUser(ctx context.Context, user User) (*User, error) {
 	rb, err := json.Marshal(user)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v2/users?confidential_data=1", c.HostURL),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v2/users?confidential_data=1", c.HostURL),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return nil, err
@@ -77,8 +78,8 @@ func (c *Client) CreateUser(user User) (*User, error) {
 }
 
 // GetUser - Returns a specifc user
-func (c *Client) GetUser(username string) (*User, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v2/users/%s?confidential_data=1", c.HostURL,
+func (c *Client) GetUser(ctx context.Context, username string) (*User, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/users/%s?confidential_data=1", c.HostURL,
 		url.PathEscape(username)), nil)
 	if err != nil {
 		return nil, err
@@ -94,12 +95,13 @@ func (c *Client) GetUser(username string) (*User, error) {
 }
 
 // UpdateUser - Updates an existing user
-func (c *Client) UpdateUser(user User) error {
-	rb, err := json.Marshal(user)
+func (c *Client) UpdateUser(ctx context.Context, user User) error {
+	rb, err := c.marshalForUpdate(ctx, fmt.Sprintf("%s/api/v2/users/%s?confidential_data=1", c.HostURL, url.PathEscape(user.Username)),
+		user, userReadOnlyFields...)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v2/users/%s", c.HostURL, url.PathEscape(user.Username)),
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/api/v2/users/%s", c.HostURL, url.PathEscape(user.Username)),
 		bytes.NewBuffer(rb))
 	if err != nil {
 		return err
@@ -110,8 +112,8 @@ func (c *Client) UpdateUser(user User) error {
 }
 
 // DeleteUser - Deletes a user
-func (c *Client) DeleteUser(username string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v2/users/%s", c.HostURL, url.PathEscape(username)), nil)
+func (c *Client) DeleteUser(ctx context.Context, username string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v2/users/%s", c.HostURL, url.PathEscape(username)), nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,53 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// VersionInfo defines the SFTPGo version and build information
+type VersionInfo struct {
+	Version    string `json:"version"`
+	BuildDate  string `json:"build_date"`
+	CommitHash string `json:"commit_hash"`
+	// Features lists the features enabled or disabled at build time, for
+	// example "+s3" or "-gcs"
+	Features []string `json:"features"`
+}
+
+// GetVersion - Returns the SFTPGo version and build information
+func (c *Client) GetVersion(ctx context.Context) (*VersionInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v2/version", c.HostURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequestWithAuth(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	info := VersionInfo{}
+	err = json.Unmarshal(body, &info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
@@ -99,18 +99,18 @@ func (d *defenderEntriesDataSource) Configure(_ context.Context, req datasource.
 		return
 	}
 
-	d.client = req.ProviderData.(*client.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *defenderEntriesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state defenderEntriesDataSourceModel
 
-	entries, err := d.client.GetIPListEntries(2)
+	entries, err := d.client.GetIPListEntries(ctx, 2)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read SFTPGo Defender entries",
-			err.Error(),
+			parseAPIError(err),
 		)
 		return
 	}
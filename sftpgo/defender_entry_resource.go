@@ -52,7 +52,7 @@ func (r *defenderEntryResource) Configure(_ context.Context, req resource.Config
 		return
 	}
 
-	r.client = req.ProviderData.(*client.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -63,7 +63,7 @@ func (r *defenderEntryResource) Metadata(_ context.Context, req resource.Metadat
 // Schema defines the schema for the resource.
 func (r *defenderEntryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Defender entry",
+		Description: "Defender entry. It can be imported using the IP or network, optionally prefixed by the list type (2), for example \"2/192.168.1.0/24\".",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:    true,
@@ -122,11 +122,16 @@ func (r *defenderEntryResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	entry, err := r.client.CreateIPListEntry(*entry)
+	entry, adopted, err := createOrAdopt(ctx, r.client,
+		func() (*client.IPListEntry, error) { return r.client.CreateIPListEntry(ctx, *entry) },
+		func() (*client.IPListEntry, error) {
+			return r.client.GetIPListEntry(ctx, entry.Type, plan.IPOrNet.ValueString())
+		},
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating defender entry",
-			"Could not create defender entry, unexpected error: "+err.Error(),
+			"Could not create defender entry: "+parseAPIError(err),
 		)
 		return
 	}
@@ -136,6 +141,14 @@ func (r *defenderEntryResource) Create(ctx context.Context, req resource.CreateR
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(plan.Description, state.Description)
+
+	if adopted {
+		resp.Diagnostics.Append(checkAdoptedState(ctx, req.Plan, state, "defender entry", plan.IPOrNet.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
@@ -155,20 +168,26 @@ func (r *defenderEntryResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
-	entry, err := r.client.GetIPListEntry(2, state.IPOrNet.ValueString())
+	entry, err := r.client.GetIPListEntry(ctx, 2, state.IPOrNet.ValueString())
 	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo defender entry",
-			"Could not read SFTPGo defender entry "+state.IPOrNet.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo defender entry "+state.IPOrNet.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
+	description := state.Description
 	diags = state.fromSFTPGo(ctx, entry)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(description, state.Description)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -193,20 +212,20 @@ func (r *defenderEntryResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	err := r.client.UpdateIPListEntry(*entry)
+	err := r.client.UpdateIPListEntry(ctx, *entry)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating defender entry",
-			"Could not update defender entry, unexpected error: "+err.Error(),
+			"Could not update defender entry: "+parseAPIError(err),
 		)
 		return
 	}
 
-	entry, err = r.client.GetIPListEntry(2, plan.IPOrNet.ValueString())
+	entry, err = r.client.GetIPListEntry(ctx, 2, plan.IPOrNet.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo defender entry",
-			"Could not read SFTPGo defender entry "+plan.IPOrNet.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo defender entry "+plan.IPOrNet.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
@@ -217,6 +236,7 @@ func (r *defenderEntryResource) Update(ctx context.Context, req resource.UpdateR
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(plan.Description, state.Description)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -236,18 +256,23 @@ func (r *defenderEntryResource) Delete(ctx context.Context, req resource.DeleteR
 	}
 
 	// Delete existing entry
-	err := r.client.DeleteIPListEntry(2, state.IPOrNet.ValueString())
+	err := r.client.DeleteIPListEntry(ctx, 2, state.IPOrNet.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting SFTPGo defender entry",
-			"Could not delete defender entry, unexpected error: "+err.Error(),
+			"Could not delete defender entry: "+parseAPIError(err),
 		)
 		return
 	}
 }
 
 // ImportState imports an existing the resource and save the Terraform state
-func (*defenderEntryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ipornet and save to ipornet attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("ipornet"), req, resp)
+func (r *defenderEntryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The import ID is the ipornet, optionally prefixed by the list type
+	ipOrNet, diags := getIPListEntryImportID(ctx, r.client, req.ID, 2, "defender list")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ipornet"), ipOrNet)...)
 }
@@ -15,9 +15,13 @@
 package sftpgo
 
 import (
+	"context"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 func TestAccDefenderListResource(t *testing.T) {
@@ -48,6 +52,18 @@ func TestAccDefenderListResource(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      "sftpgo_defender_entry.test",
+				ImportState:       true,
+				ImportStateId:     "2/172.16.3.0/24",
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  "sftpgo_defender_entry.test",
+				ImportState:   true,
+				ImportStateId: "3/172.16.3.0/24",
+				ExpectError:   regexp.MustCompile("Invalid Import Identifier"),
+			},
 			// Update and Read testing
 			{
 				Config: `
@@ -71,3 +87,14 @@ func TestAccDefenderListResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccDefenderListResourceDeletedOutsideTerraform(t *testing.T) {
+	testAccDeletedOutsideTerraform(t, "sftpgo_defender_entry.test", `
+		resource "sftpgo_defender_entry" "test" {
+		  ipornet = "172.16.5.0/24"
+		  protocols = 0
+		  mode = 2
+		}`, func(c *client.Client) error {
+		return c.DeleteIPListEntry(context.Background(), 2, "172.16.5.0/24")
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                   = &filesystemTemplateDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &filesystemTemplateDataSource{}
+)
+
+// NewFilesystemTemplateDataSource is a helper function to simplify the provider implementation.
+func NewFilesystemTemplateDataSource() datasource.DataSource {
+	return &filesystemTemplateDataSource{}
+}
+
+// filesystemTemplateDataSource is the data source implementation. It does
+// not call the SFTPGo API, it validates a filesystem configuration that can
+// be shared by users, groups and folders.
+type filesystemTemplateDataSource struct{}
+
+// Metadata returns the data source type name.
+func (d *filesystemTemplateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_filesystem_template"
+}
+
+// Schema defines the schema for the data source.
+func (d *filesystemTemplateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Validates a filesystem configuration once, so it can be assigned to the filesystem attribute of " +
+			"multiple users, groups and folders. The SFTPGo API is not called.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Required to use the test framework. Just a placeholder.",
+			},
+			"filesystem": getSchemaForFilesystem(),
+		},
+	}
+}
+
+// ValidateConfig validates the data source configuration.
+func (d *filesystemTemplateDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validateFilesystemConfig(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateGCSCredentials(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateS3Credentials(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateS3Endpoint(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateSkipTLSVerify(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateAzBlobEmulator(ctx, req.Config, path.Root("filesystem"))...)
+}
+
+// Read sets the Terraform state from the configuration.
+func (d *filesystemTemplateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state filesystemTemplateDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ID = types.StringValue(placeholderID)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// filesystemTemplateDataSourceModel maps the data source schema data.
+type filesystemTemplateDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Filesystem types.Object `tfsdk:"filesystem"`
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesystemTemplateSchema(t *testing.T) {
+	ctx := context.Background()
+	d := &filesystemTemplateDataSource{}
+	schemaResp := datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+	fsType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object).AttributeTypes["filesystem"]
+
+	for _, r := range []resource.Resource{&userResource{}, &folderResource{}} {
+		resourceResp := resource.SchemaResponse{}
+		r.Schema(ctx, resource.SchemaRequest{}, &resourceResp)
+		require.False(t, resourceResp.Diagnostics.HasError())
+		resourceType := resourceResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+		require.True(t, fsType.Equal(resourceType.AttributeTypes["filesystem"]))
+	}
+	resourceResp := resource.SchemaResponse{}
+	(&groupResource{}).Schema(ctx, resource.SchemaRequest{}, &resourceResp)
+	settingsType := resourceResp.Schema.Type().TerraformType(ctx).(tftypes.Object).AttributeTypes["user_settings"]
+	require.True(t, fsType.Equal(settingsType.(tftypes.Object).AttributeTypes["filesystem"]))
+}
+
+func TestFilesystemTemplateValidation(t *testing.T) {
+	ctx := context.Background()
+	d := &filesystemTemplateDataSource{}
+	schemaResp := datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	fsType := objType.AttributeTypes["filesystem"].(tftypes.Object)
+
+	validate := func(fs tftypes.Value) diag.Diagnostics {
+		req := datasource.ValidateConfigRequest{
+			Config: tfsdk.Config{
+				Schema: schemaResp.Schema,
+				Raw:    getTestObject(objType, map[string]tftypes.Value{"filesystem": fs}),
+			},
+		}
+		resp := datasource.ValidateConfigResponse{}
+		d.ValidateConfig(ctx, req, &resp)
+		return resp.Diagnostics
+	}
+
+	checkConfigErrorPath(t, validate(getFilesystemTestObject(fsType, 1, "s3config")), path.Root("filesystem"), false)
+	checkConfigErrorPath(t, validate(getFilesystemTestObject(fsType, 1)), path.Root("filesystem"), true)
+	checkConfigErrorPath(t, validate(getFilesystemTestObject(fsType, 1, "s3config", "gcsconfig")),
+		path.Root("filesystem"), true)
+}
+
+func TestAccFilesystemTemplateDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	tfresource.Test(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: `
+					data "sftpgo_filesystem_template" "test" {
+					  filesystem = {
+					    provider = 1
+					    s3config = {
+					      bucket = "bucket"
+					      region = "us-east-1"
+					      access_key = "key"
+					      access_secret = "secret"
+					      key_prefix = "prefix/"
+					    }
+					  }
+					}
+
+					resource "sftpgo_folder" "test" {
+					  name = "test folder template"
+					  filesystem = data.sftpgo_filesystem_template.test.filesystem
+					}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.sftpgo_filesystem_template.test", "id", placeholderID),
+					tfresource.TestCheckResourceAttr("sftpgo_folder.test", "filesystem.provider", "1"),
+					tfresource.TestCheckResourceAttr("sftpgo_folder.test", "filesystem.s3config.bucket", "bucket"),
+					tfresource.TestCheckResourceAttr("sftpgo_folder.test", "filesystem.s3config.access_secret", "secret"),
+					tfresource.TestCheckResourceAttr("sftpgo_folder.test", "filesystem.s3config.key_prefix", "prefix/"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+// NewFolderQuotaResetResource is a helper function to simplify the provider implementation.
+// The resource resets the quota usage of a virtual folder when created.
+func NewFolderQuotaResetResource() resource.Resource {
+	return &folderTriggerResource{
+		typeName: "_folder_quota_reset",
+		description: "Resets the quota usage of a virtual folder. The reset is done when the resource is created, " +
+			"change the triggers to replace the resource and reset the quota again. Destroying the resource " +
+			"has no effect on SFTPGo.",
+		triggersDescription:  "Arbitrary values, any change replaces the resource and resets the quota again.",
+		timestampAttribute:   "reset_at",
+		timestampDescription: "Reset time as unix timestamp in milliseconds.",
+		errorSummary:         "Error resetting folder quota",
+		errorDetail:          "Could not reset quota for folder ",
+		action: func(ctx context.Context, c *client.Client, folder string) error {
+			return c.UpdateFolderQuotaUsage(ctx, folder, client.QuotaUsage{})
+		},
+	}
+}
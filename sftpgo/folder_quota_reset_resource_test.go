@@ -0,0 +1,98 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+func TestAccFolderQuotaResetResource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	folderName := "test folder quota reset"
+	getConfig := func(trigger string) string {
+		return fmt.Sprintf(`
+			resource "sftpgo_folder" "test" {
+			  name = %q
+			  mapped_path = "/tmp/test_folder_quota_reset"
+			}
+
+			resource "sftpgo_folder_quota_reset" "test" {
+			  folder = sftpgo_folder.test.name
+			  triggers = {
+			    run = %q
+			  }
+			}`, folderName, trigger)
+	}
+	setQuotaUsage := func() {
+		err := c.UpdateFolderQuotaUsage(context.Background(), folderName, client.QuotaUsage{
+			UsedQuotaSize:  1024,
+			UsedQuotaFiles: 2,
+		})
+		require.NoError(t, err)
+	}
+	checkQuotaReset := func(_ *terraform.State) error {
+		folder, err := c.GetFolder(context.Background(), folderName)
+		if err != nil {
+			return err
+		}
+		if folder.UsedQuotaSize != 0 || folder.UsedQuotaFiles != 0 {
+			return fmt.Errorf("unexpected quota usage, size: %d, files: %d", folder.UsedQuotaSize,
+				folder.UsedQuotaFiles)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig("1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_folder_quota_reset.test", "id", folderName),
+					resource.TestCheckResourceAttr("sftpgo_folder_quota_reset.test", "folder", folderName),
+					resource.TestCheckResourceAttrSet("sftpgo_folder_quota_reset.test", "reset_at"),
+					checkQuotaReset,
+				),
+			},
+			// the quota is reset again only if the triggers change
+			{
+				PreConfig: setQuotaUsage,
+				Config:    getConfig("1"),
+				PlanOnly:  true,
+			},
+			{
+				Config: getConfig("2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_folder_quota_reset.test", "triggers.run", "2"),
+					checkQuotaReset,
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+// NewFolderQuotaScanResource is a helper function to simplify the provider implementation.
+// The resource starts a quota scan for a virtual folder when created.
+func NewFolderQuotaScanResource() resource.Resource {
+	return &folderTriggerResource{
+		typeName: "_folder_quota_scan",
+		description: "Starts a quota scan for a virtual folder. The scan is started when the resource is created " +
+			"and runs in the background, the sftpgo_folder resource and the sftpgo_folder_quota_usage data source " +
+			"expose its status. Change the triggers to replace the resource and start a new scan. Destroying " +
+			"the resource has no effect on SFTPGo.",
+		triggersDescription:  "Arbitrary values, any change replaces the resource and starts a new scan.",
+		timestampAttribute:   "started_at",
+		timestampDescription: "Scan start time as unix timestamp in milliseconds.",
+		errorSummary:         "Error starting folder quota scan",
+		errorDetail:          "Could not start quota scan for folder ",
+		action: func(ctx context.Context, c *client.Client, folder string) error {
+			return c.StartFolderQuotaScan(ctx, folder)
+		},
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccFolderQuotaScanResource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	folderName := "test folder quota scan"
+	mappedPath := t.TempDir()
+	err = os.WriteFile(mappedPath+"/file.txt", []byte("content"), 0600)
+	require.NoError(t, err)
+
+	getConfig := func(trigger string) string {
+		return fmt.Sprintf(`
+			resource "sftpgo_folder" "test" {
+			  name = %q
+			  mapped_path = %q
+			}
+
+			resource "sftpgo_folder_quota_scan" "test" {
+			  folder = sftpgo_folder.test.name
+			  triggers = {
+			    run = %q
+			  }
+			}`, folderName, mappedPath, trigger)
+	}
+	waitForScan := func() {
+		require.Eventually(t, func() bool {
+			scans, err := c.GetFolderQuotaScans(context.Background())
+			if err != nil {
+				return false
+			}
+			for _, scan := range scans {
+				if scan.Name == folderName {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 100*time.Millisecond)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig("1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_folder_quota_scan.test", "id", folderName),
+					resource.TestCheckResourceAttr("sftpgo_folder_quota_scan.test", "folder", folderName),
+					resource.TestCheckResourceAttrSet("sftpgo_folder_quota_scan.test", "started_at"),
+				),
+			},
+			// the completed scan updates the folder usage without causing a diff
+			{
+				PreConfig: waitForScan,
+				Config:    getConfig("1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_folder.test", "used_quota_files", "1"),
+					resource.TestCheckResourceAttr("sftpgo_folder.test", "used_quota_size", "7"),
+					resource.TestCheckResourceAttr("sftpgo_folder.test", "scan_in_progress", "false"),
+					resource.TestCheckResourceAttr("sftpgo_folder.test", "scan_start_time", "0"),
+					resource.TestCheckResourceAttrWith("sftpgo_folder.test", "last_quota_update", func(value string) error {
+						if value == "0" {
+							return fmt.Errorf("last_quota_update not updated")
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config:   getConfig("1"),
+				PlanOnly: true,
+			},
+			{
+				Config: getConfig("2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_folder_quota_scan.test", "triggers.run", "2"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &folderQuotaUsageDataSource{}
+	_ datasource.DataSourceWithConfigure = &folderQuotaUsageDataSource{}
+)
+
+// NewFolderQuotaUsageDataSource is a helper function to simplify the provider implementation.
+func NewFolderQuotaUsageDataSource() datasource.DataSource {
+	return &folderQuotaUsageDataSource{}
+}
+
+// folderQuotaUsageDataSource is the data source implementation.
+type folderQuotaUsageDataSource struct {
+	client *client.Client
+}
+
+// Metadata returns the data source type name.
+func (d *folderQuotaUsageDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_folder_quota_usage"
+}
+
+// Schema defines the schema for the data source.
+func (d *folderQuotaUsageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the quota usage and the quota scan status of a virtual folder.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Required to use the test framework. Matches the folder name.",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the virtual folder to fetch the usage for.",
+			},
+			"used_quota_size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Used quota as bytes.",
+			},
+			"used_quota_files": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Used quota as number of files.",
+			},
+			"last_quota_update": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Last quota update as unix timestamp in milliseconds. 0 means never updated. It is updated when a quota scan completes.",
+			},
+			"scan_in_progress": schema.BoolAttribute{
+				Computed:    true,
+				Description: "True if a quota scan is running for the folder.",
+			},
+			"scan_start_time": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Start time of the running quota scan as unix timestamp in milliseconds. 0 if no scan is running.",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *folderQuotaUsageDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*providerData).client
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *folderQuotaUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config folderQuotaUsageDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folder, err := d.client.GetFolder(ctx, config.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read SFTPGo Folder Quota Usage",
+			"Could not read SFTPGo Folder "+config.Name.ValueString()+": "+parseAPIError(err),
+		)
+		return
+	}
+	scans, err := d.client.GetFolderQuotaScans(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read SFTPGo Folder Quota Scans",
+			"Could not read SFTPGo Folder quota scans: "+parseAPIError(err),
+		)
+		return
+	}
+
+	// usage values are always set, 0 is a meaningful value here
+	state := folderQuotaUsageDataSourceModel{
+		ID:              types.StringValue(folder.Name),
+		Name:            types.StringValue(folder.Name),
+		UsedQuotaSize:   types.Int64Value(folder.UsedQuotaSize),
+		UsedQuotaFiles:  types.Int64Value(int64(folder.UsedQuotaFiles)),
+		LastQuotaUpdate: types.Int64Value(folder.LastQuotaUpdate),
+	}
+	state.ScanInProgress, state.ScanStartTime = getFolderQuotaScan(scans, folder.Name)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// folderQuotaUsageDataSourceModel maps the data source schema data.
+type folderQuotaUsageDataSourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	UsedQuotaSize   types.Int64  `tfsdk:"used_quota_size"`
+	UsedQuotaFiles  types.Int64  `tfsdk:"used_quota_files"`
+	LastQuotaUpdate types.Int64  `tfsdk:"last_quota_update"`
+	ScanInProgress  types.Bool   `tfsdk:"scan_in_progress"`
+	ScanStartTime   types.Int64  `tfsdk:"scan_start_time"`
+}
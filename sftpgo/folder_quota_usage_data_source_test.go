@@ -0,0 +1,83 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+func TestAccFolderQuotaUsageDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	config := `
+		resource "sftpgo_folder" "test" {
+		  name = "test folder usage"
+		  mapped_path = "/tmp/testfolderusage"
+		}
+
+		data "sftpgo_folder_quota_usage" "test" {
+		  name = sftpgo_folder.test.name
+		}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sftpgo_folder_quota_usage.test", "id", "test folder usage"),
+					resource.TestCheckResourceAttr("data.sftpgo_folder_quota_usage.test", "name", "test folder usage"),
+					resource.TestCheckResourceAttr("data.sftpgo_folder_quota_usage.test", "used_quota_size", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_folder_quota_usage.test", "used_quota_files", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_folder_quota_usage.test", "last_quota_update", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_folder_quota_usage.test", "scan_in_progress", "false"),
+					resource.TestCheckResourceAttr("data.sftpgo_folder_quota_usage.test", "scan_start_time", "0"),
+				),
+			},
+			{
+				PreConfig: func() {
+					err := c.UpdateFolderQuotaUsage(context.Background(), "test folder usage", client.QuotaUsage{
+						UsedQuotaSize:  2048,
+						UsedQuotaFiles: 3,
+					})
+					require.NoError(t, err)
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sftpgo_folder_quota_usage.test", "used_quota_size", "2048"),
+					resource.TestCheckResourceAttr("data.sftpgo_folder_quota_usage.test", "used_quota_files", "3"),
+					resource.TestCheckResourceAttrSet("data.sftpgo_folder_quota_usage.test", "last_quota_update"),
+					resource.TestCheckResourceAttr("sftpgo_folder.test", "used_quota_size", "2048"),
+				),
+			},
+			// the refreshed quota usage must not cause a diff
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
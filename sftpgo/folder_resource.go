@@ -21,18 +21,22 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/sftpgo/sdk"
 
 	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &folderResource{}
-	_ resource.ResourceWithConfigure   = &folderResource{}
-	_ resource.ResourceWithImportState = &folderResource{}
+	_ resource.Resource                   = &folderResource{}
+	_ resource.ResourceWithConfigure      = &folderResource{}
+	_ resource.ResourceWithImportState    = &folderResource{}
+	_ resource.ResourceWithValidateConfig = &folderResource{}
 )
 
 // NewFolderResource is a helper function to simplify the provider implementation.
@@ -43,15 +47,19 @@ func NewFolderResource() resource.Resource {
 // folderResource is the resource implementation.
 type folderResource struct {
 	client *client.Client
+	// normalizeSFTPEndpoint is the provider level setting
+	normalizeSFTPEndpoint bool
 }
 
-// Configure adds the provider configured client to the resource.
+// Configure adds the provider configured client and settings to the resource.
 func (r *folderResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	r.client = req.ProviderData.(*client.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.normalizeSFTPEndpoint = data.normalizeSFTPEndpoint
 }
 
 // Metadata returns the resource type name.
@@ -86,20 +94,53 @@ func (r *folderResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 			"used_quota_size": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Used quota as bytes.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"used_quota_files": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Used quota as number of files.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"last_quota_update": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Last quota update as unix timestamp in milliseconds",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"scan_in_progress": schema.BoolAttribute{
+				Computed:    true,
+				Description: "True if a quota scan is running for the folder.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"scan_start_time": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Start time of the running quota scan as unix timestamp in milliseconds. 0 if no scan is running.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"filesystem": getSchemaForFilesystem(),
 		},
 	}
 }
 
+// ValidateConfig validates the resource configuration.
+func (r *folderResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validateFilesystemConfig(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateGCSCredentials(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateS3Credentials(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateS3Endpoint(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateSkipTLSVerify(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateAzBlobEmulator(ctx, req.Config, path.Root("filesystem"))...)
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *folderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Retrieve values from plan
@@ -116,11 +157,16 @@ func (r *folderResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	folder, err := r.client.CreateFolder(*folder)
+	folder, adopted, err := createOrAdopt(ctx, r.client,
+		func() (*sdk.BaseVirtualFolder, error) { return r.client.CreateFolder(ctx, *folder) },
+		func() (*sdk.BaseVirtualFolder, error) {
+			return r.client.GetFolder(ctx, plan.Name.ValueString())
+		},
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating folder",
-			"Could not create folder, unexpected error: "+err.Error(),
+			"Could not create folder: "+parseAPIError(err),
 		)
 		return
 	}
@@ -135,6 +181,18 @@ func (r *folderResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	diags = r.setQuotaScan(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if adopted {
+		resp.Diagnostics.Append(checkAdoptedState(ctx, req.Plan, state, "folder", plan.Name.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
@@ -154,11 +212,15 @@ func (r *folderResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	folder, err := r.client.GetFolder(state.Name.ValueString())
+	folder, err := r.client.GetFolder(ctx, state.Name.ValueString())
 	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Folder",
-			"Could not read SFTPGo Folder "+state.Name.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Folder "+state.Name.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
@@ -174,6 +236,11 @@ func (r *folderResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	diags = r.setQuotaScan(ctx, &newState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &newState)
@@ -198,20 +265,20 @@ func (r *folderResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	err := r.client.UpdateFolder(*folder)
+	err := r.client.UpdateFolder(ctx, *folder)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating folder",
-			"Could not update folder, unexpected error: "+err.Error(),
+			"Could not update folder: "+parseAPIError(err),
 		)
 		return
 	}
 
-	folder, err = r.client.GetFolder(plan.Name.ValueString())
+	folder, err = r.client.GetFolder(ctx, plan.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Folder",
-			"Could not read SFTPGo Folder "+plan.Name.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Folder "+plan.Name.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
@@ -227,6 +294,18 @@ func (r *folderResource) Update(ctx context.Context, req resource.UpdateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	diags = r.setQuotaScan(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// the quota usage and scans are updated by SFTPGo in the background, keep
+	// the planned values, the current ones are read on the next refresh
+	state.UsedQuotaSize = getPlannedInt64(plan.UsedQuotaSize, state.UsedQuotaSize)
+	state.UsedQuotaFiles = getPlannedInt64(plan.UsedQuotaFiles, state.UsedQuotaFiles)
+	state.LastQuotaUpdate = getPlannedInt64(plan.LastQuotaUpdate, state.LastQuotaUpdate)
+	state.ScanInProgress = getPlannedBool(plan.ScanInProgress, state.ScanInProgress)
+	state.ScanStartTime = getPlannedInt64(plan.ScanStartTime, state.ScanStartTime)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -246,11 +325,11 @@ func (r *folderResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 
 	// Delete existing folder
-	err := r.client.DeleteFolder(state.Name.ValueString())
+	err := r.client.DeleteFolder(ctx, state.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting SFTPGo folder",
-			"Could not delete folder, unexpected error: "+err.Error(),
+			"Could not delete folder: "+parseAPIError(err),
 		)
 		return
 	}
@@ -262,7 +341,25 @@ func (*folderResource) ImportState(ctx context.Context, req resource.ImportState
 	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 }
 
-func (*folderResource) preservePlanFields(ctx context.Context, plan, state *virtualFolderResourceModel) diag.Diagnostics {
+// setQuotaScan sets the status of the quota scan running for the folder, if any.
+func (r *folderResource) setQuotaScan(ctx context.Context, state *virtualFolderResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	scans, err := r.client.GetFolderQuotaScans(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error Reading SFTPGo Folder Quota Scans",
+			"Could not read SFTPGo Folder quota scans: "+parseAPIError(err),
+		)
+		return diags
+	}
+	state.setQuotaScan(scans)
+
+	return diags
+}
+
+func (r *folderResource) preservePlanFields(ctx context.Context, plan, state *virtualFolderResourceModel) diag.Diagnostics {
+	state.Description = preserveEmptyString(plan.Description, state.Description)
 	if plan.FsConfig.IsNull() {
 		return nil
 	}
@@ -283,7 +380,7 @@ func (*folderResource) preservePlanFields(ctx context.Context, plan, state *virt
 		return diags
 	}
 
-	fs, diags := preserveFsConfigPlanFields(ctx, fsPlan, fsState)
+	fs, diags := preserveFsConfigPlanFields(ctx, fsPlan, fsState, r.normalizeSFTPEndpoint)
 	if diags.HasError() {
 		return diags
 	}
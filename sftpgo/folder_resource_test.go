@@ -15,9 +15,14 @@
 package sftpgo
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 func TestAccFolderResource(t *testing.T) {
@@ -49,6 +54,8 @@ func TestAccFolderResource(t *testing.T) {
 					resource.TestCheckResourceAttrSet("sftpgo_folder.test", "used_quota_size"),
 					resource.TestCheckResourceAttrSet("sftpgo_folder.test", "used_quota_files"),
 					resource.TestCheckResourceAttrSet("sftpgo_folder.test", "last_quota_update"),
+					resource.TestCheckResourceAttr("sftpgo_folder.test", "scan_in_progress", "false"),
+					resource.TestCheckResourceAttr("sftpgo_folder.test", "scan_start_time", "0"),
 					resource.TestCheckResourceAttr("sftpgo_folder.test", "filesystem.provider", "3"),
 					resource.TestCheckNoResourceAttr("sftpgo_folder.test", "filesystem.s3config"),
 					resource.TestCheckNoResourceAttr("sftpgo_folder.test", "filesystem.gcsconfig"),
@@ -67,7 +74,9 @@ func TestAccFolderResource(t *testing.T) {
 			{
 				ResourceName:      "sftpgo_folder.test",
 				ImportState:       true,
-				ImportStateVerify: false, // SFTPGo will not return plain text secrets
+				ImportStateVerify: true,
+				// SFTPGo will not return plain text password/secrets
+				ImportStateVerifyIgnore: testAccImportStateVerifyIgnore("sftpgo_folder"),
 			},
 			// Update and Read testing
 			{
@@ -168,3 +177,73 @@ func TestAccFolderResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccFolderResourceDeletedOutsideTerraform(t *testing.T) {
+	testAccDeletedOutsideTerraform(t, "sftpgo_folder.test", `
+		resource "sftpgo_folder" "test" {
+		  name = "test folder deleted"
+		  mapped_path = "/tmp/testfolderdeleted"
+		}`, func(c *client.Client) error {
+		return c.DeleteFolder(context.Background(), "test folder deleted")
+	})
+}
+
+func TestAccFolderResourceImportFsSecrets(t *testing.T) {
+	testAccImportFsSecrets(t, "sftpgo_folder.test", `
+		resource "sftpgo_folder" "test" {
+		  name = "test folder import secrets"
+		  mapped_path = "/tmp/testfolderimportsecrets"
+		  filesystem = {
+			provider = 1
+			s3config = {
+			  bucket = "bucket"
+			  region = "us-east-1"
+			  access_key = "access key"
+			  access_secret = "access secret"
+			}
+		  }
+		}`, "filesystem.", "s3config.access_secret")
+}
+
+func TestAccFolderResourceEmptyDescription(t *testing.T) {
+	getConfig := func(description string) string {
+		return fmt.Sprintf(`
+			resource "sftpgo_folder" "test" {
+			  name = "test folder empty description"
+			  mapped_path = "/tmp/testfolderemptydesc"
+			  description = %q
+			}`, description)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_folder.test", "description", ""),
+				),
+			},
+			{
+				Config: getConfig(""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: getConfig("desc"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_folder.test", "description", "desc"),
+				),
+			},
+			{
+				Config: getConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_folder.test", "description", ""),
+				),
+			},
+		},
+	})
+}
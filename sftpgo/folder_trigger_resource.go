@@ -0,0 +1,166 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &folderTriggerResource{}
+	_ resource.ResourceWithConfigure = &folderTriggerResource{}
+)
+
+// folderTriggerResource is a one-shot resource that runs an action on a
+// virtual folder when created, there is nothing to update or delete in
+// SFTPGo. The folder quota reset and scan resources are built on it.
+type folderTriggerResource struct {
+	client *client.Client
+	// typeName is appended to the provider type name
+	typeName            string
+	description         string
+	triggersDescription string
+	// timestampAttribute is the computed attribute set to the action time
+	timestampAttribute   string
+	timestampDescription string
+	errorSummary         string
+	errorDetail          string
+	action               func(ctx context.Context, c *client.Client, folder string) error
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *folderTriggerResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*providerData).client
+}
+
+// Metadata returns the resource type name.
+func (r *folderTriggerResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.typeName
+}
+
+// Schema defines the schema for the resource.
+func (r *folderTriggerResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: r.description,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Required to use the test framework. Matches the folder name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"folder": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the virtual folder.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: r.triggersDescription,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			r.timestampAttribute: schema.Int64Attribute{
+				Computed:    true,
+				Description: r.timestampDescription,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create runs the folder action and sets the initial Terraform state.
+func (r *folderTriggerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var folder types.String
+	diags := req.Plan.GetAttribute(ctx, path.Root("folder"), &folder)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.action(ctx, r.client, folder.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			r.errorSummary,
+			r.errorDetail+folder.ValueString()+": "+parseAPIError(err),
+		)
+		return
+	}
+
+	// Set state to fully populated data
+	resp.State.Raw = req.Plan.Raw
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), folder)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(r.timestampAttribute),
+		types.Int64Value(time.Now().UnixMilli()))...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *folderTriggerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var folder types.String
+	diags := req.State.GetAttribute(ctx, path.Root("folder"), &folder)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.client.GetFolder(ctx, folder.ValueString())
+	if err != nil {
+		if client.IsNotFound(err) {
+			// the folder was removed, the action must be run again if it is recreated
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading SFTPGo Folder",
+			"Could not read SFTPGo Folder "+folder.ValueString()+": "+parseAPIError(err),
+		)
+		return
+	}
+}
+
+// Update sets the updated Terraform state, all the changes replace the resource.
+func (r *folderTriggerResource) Update(_ context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.State.Raw = req.Plan.Raw
+}
+
+// Delete removes the Terraform state, SFTPGo is not changed.
+func (r *folderTriggerResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
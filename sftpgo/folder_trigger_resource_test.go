@@ -0,0 +1,91 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+func TestFolderTriggerResource(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		r                  resource.Resource
+		typeName           string
+		timestampAttribute string
+	}{
+		{NewFolderQuotaResetResource(), "sftpgo_folder_quota_reset", "reset_at"},
+		{NewFolderQuotaScanResource(), "sftpgo_folder_quota_scan", "started_at"},
+	} {
+		var metadataResp resource.MetadataResponse
+		tc.r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "sftpgo"}, &metadataResp)
+		require.Equal(t, tc.typeName, metadataResp.TypeName)
+		var schemaResp resource.SchemaResponse
+		tc.r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+		require.False(t, schemaResp.Diagnostics.HasError())
+		_, ok := schemaResp.Schema.Attributes[tc.timestampAttribute]
+		require.True(t, ok, tc.typeName)
+	}
+
+	var folders []string
+	actionErr := errors.New("action error")
+	r := NewFolderQuotaScanResource().(*folderTriggerResource)
+	r.action = func(_ context.Context, _ *client.Client, folder string) error {
+		folders = append(folders, folder)
+		if folder == "missing" {
+			return actionErr
+		}
+		return nil
+	}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	getPlan := func(folder string) tfsdk.Plan {
+		return tfsdk.Plan{
+			Schema: schemaResp.Schema,
+			Raw: getTestObject(objType, map[string]tftypes.Value{
+				"id":         tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"folder":     tftypes.NewValue(tftypes.String, folder),
+				"started_at": tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+			}),
+		}
+	}
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	r.Create(ctx, resource.CreateRequest{Plan: getPlan("folder1")}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	var id types.String
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	require.Equal(t, "folder1", id.ValueString())
+	var startedAt types.Int64
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("started_at"), &startedAt)...)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Greater(t, startedAt.ValueInt64(), int64(0))
+
+	resp = resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	r.Create(ctx, resource.CreateRequest{Plan: getPlan("missing")}, &resp)
+	require.True(t, resp.Diagnostics.HasError())
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "Could not start quota scan for folder missing")
+	require.Equal(t, []string{"folder1", "missing"}, folders)
+}
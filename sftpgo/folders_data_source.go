@@ -86,6 +86,14 @@ func (d *foldersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 							Computed:    true,
 							Description: "Last quota update as unix timestamp in milliseconds",
 						},
+						"scan_in_progress": schema.BoolAttribute{
+							Computed:    true,
+							Description: "True if a quota scan is running for the folder.",
+						},
+						"scan_start_time": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Start time of the running quota scan as unix timestamp in milliseconds. 0 if no scan is running.",
+						},
 						"filesystem": getComputedSchemaForFilesystem(),
 					},
 				},
@@ -100,18 +108,26 @@ func (d *foldersDataSource) Configure(_ context.Context, req datasource.Configur
 		return
 	}
 
-	d.client = req.ProviderData.(*client.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *foldersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state foldersDataSourceModel
 
-	folders, err := d.client.GetFolders()
+	folders, err := d.client.GetFolders(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read SFTPGo Virtual Folders",
-			err.Error(),
+			parseAPIError(err),
+		)
+		return
+	}
+	scans, err := d.client.GetFolderQuotaScans(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read SFTPGo Folder Quota Scans",
+			"Could not read SFTPGo Folder quota scans: "+parseAPIError(err),
 		)
 		return
 	}
@@ -124,6 +140,7 @@ func (d *foldersDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		if resp.Diagnostics.HasError() {
 			return
 		}
+		folderState.setQuotaScan(scans)
 
 		state.Folders = append(state.Folders, folderState)
 	}
@@ -15,6 +15,7 @@
 package sftpgo
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -60,11 +61,11 @@ func TestAccFoldersDataSource(t *testing.T) {
 	}
 	c, err := getClient()
 	require.NoError(t, err)
-	_, err = c.CreateFolder(testFolder)
+	_, err = c.CreateFolder(context.Background(), testFolder)
 	require.NoError(t, err)
 
 	defer func() {
-		err = c.DeleteFolder(testFolder.Name)
+		err = c.DeleteFolder(context.Background(), testFolder.Name)
 		require.NoError(t, err)
 	}()
 
@@ -85,6 +86,8 @@ func TestAccFoldersDataSource(t *testing.T) {
 					resource.TestCheckResourceAttrSet("data.sftpgo_folders.test", "folders.0.used_quota_size"),
 					resource.TestCheckResourceAttrSet("data.sftpgo_folders.test", "folders.0.used_quota_files"),
 					resource.TestCheckResourceAttrSet("data.sftpgo_folders.test", "folders.0.last_quota_update"),
+					resource.TestCheckResourceAttr("data.sftpgo_folders.test", "folders.0.scan_in_progress", "false"),
+					resource.TestCheckResourceAttr("data.sftpgo_folders.test", "folders.0.scan_start_time", "0"),
 					resource.TestCheckResourceAttr("data.sftpgo_folders.test", "folders.0.filesystem.provider",
 						fmt.Sprintf("%d", testFolder.FsConfig.Provider)),
 					resource.TestCheckResourceAttr("data.sftpgo_folders.test", "folders.0.filesystem.s3config.bucket",
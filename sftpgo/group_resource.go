@@ -16,6 +16,7 @@ package sftpgo
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -24,17 +25,20 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/sftpgo/sdk"
 
 	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &groupResource{}
-	_ resource.ResourceWithConfigure   = &groupResource{}
-	_ resource.ResourceWithImportState = &groupResource{}
+	_ resource.Resource                   = &groupResource{}
+	_ resource.ResourceWithConfigure      = &groupResource{}
+	_ resource.ResourceWithImportState    = &groupResource{}
+	_ resource.ResourceWithValidateConfig = &groupResource{}
 )
 
 // NewGroupResource is a helper function to simplify the provider implementation.
@@ -45,15 +49,19 @@ func NewGroupResource() resource.Resource {
 // groupResource is the resource implementation.
 type groupResource struct {
 	client *client.Client
+	// normalizeSFTPEndpoint is the provider level setting
+	normalizeSFTPEndpoint bool
 }
 
-// Configure adds the provider configured client to the resource.
+// Configure adds the provider configured client and settings to the resource.
 func (r *groupResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	r.client = req.ProviderData.(*client.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.normalizeSFTPEndpoint = data.normalizeSFTPEndpoint
 }
 
 // Metadata returns the resource type name.
@@ -116,7 +124,10 @@ func (r *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					"permissions": schema.MapAttribute{
 						Optional:    true,
 						ElementType: types.StringType,
-						Description: "Comma separated, per-directory, permissions.",
+						Description: "Comma separated, per-directory, permissions. An empty map is kept as configured, SFTPGo does not distinguish it from no permissions.",
+						Validators: []validator.Map{
+							permissionsValidator{},
+						},
 					},
 					"upload_bandwidth": schema.Int64Attribute{
 						Optional:    true,
@@ -128,15 +139,15 @@ func (r *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					},
 					"upload_data_transfer": schema.Int64Attribute{
 						Optional:    true,
-						Description: "Maximum data transfer allowed for uploads as MB.",
+						Description: "Maximum data transfer allowed for uploads as MB. It cannot be set together with total_data_transfer.",
 					},
 					"download_data_transfer": schema.Int64Attribute{
 						Optional:    true,
-						Description: "Maximum data transfer allowed for downloads as MB",
+						Description: "Maximum data transfer allowed for downloads as MB. It cannot be set together with total_data_transfer.",
 					},
 					"total_data_transfer": schema.Int64Attribute{
 						Optional:    true,
-						Description: "Maximum total data transfer as MB. You can set a total data transfer instead of the individual values for uploads and downloads.",
+						Description: "Maximum total data transfer as MB. You can set a total data transfer instead of the individual values for uploads and downloads, they are mutually exclusive.",
 					},
 					"expires_in": schema.Int64Attribute{
 						Optional:    true,
@@ -146,15 +157,34 @@ func (r *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					"filesystem": getSchemaForFilesystem(),
 				},
 			},
-			"virtual_folders": getSchemaForVirtualFolders(),
+			"virtual_folders": getSetSchemaForVirtualFolders(),
+			"force_delete": schema.BoolAttribute{
+				Optional: true,
+				Description: "If enabled, the group is removed from its members before deleting it. By default a " +
+					"group cannot be deleted while users are still members of it.",
+			},
 		},
 	}
 }
 
+// ValidateConfig validates the resource configuration.
+func (r *groupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validateFilesystemConfig(ctx, req.Config, path.Root("user_settings").AtName("filesystem"))...)
+	resp.Diagnostics.Append(validateGCSCredentials(ctx, req.Config, path.Root("user_settings").AtName("filesystem"))...)
+	resp.Diagnostics.Append(validateS3Credentials(ctx, req.Config, path.Root("user_settings").AtName("filesystem"))...)
+	resp.Diagnostics.Append(validateS3Endpoint(ctx, req.Config, path.Root("user_settings").AtName("filesystem"))...)
+	resp.Diagnostics.Append(validateSkipTLSVerify(ctx, req.Config, path.Root("user_settings").AtName("filesystem"))...)
+	resp.Diagnostics.Append(validateAzBlobEmulator(ctx, req.Config, path.Root("user_settings").AtName("filesystem"))...)
+	resp.Diagnostics.Append(validateDataTransferConfig(ctx, req.Config, path.Root("user_settings"))...)
+	resp.Diagnostics.Append(validateTLSUsername(ctx, req.Config, path.Root("user_settings").AtName("filters"))...)
+	resp.Diagnostics.Append(validateTwoFactorProtocols(ctx, req.Config, path.Root("user_settings").AtName("filters"))...)
+	resp.Diagnostics.Append(validateBandwidthLimits(ctx, req.Config, path.Root("user_settings").AtName("filters"))...)
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Retrieve values from plan
-	var plan groupResourceModel
+	var plan groupResourceWithOptionsModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -167,25 +197,36 @@ func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	group, err := r.client.CreateGroup(*group)
+	group, adopted, err := createOrAdopt(ctx, r.client,
+		func() (*sdk.Group, error) { return r.client.CreateGroup(ctx, *group) },
+		func() (*sdk.Group, error) { return r.client.GetGroup(ctx, plan.Name.ValueString()) },
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating group",
-			"Could not create group, unexpected error: "+err.Error(),
+			"Could not create group: "+parseAPIError(err),
 		)
 		return
 	}
-	var state groupResourceModel
+	var state groupResourceWithOptionsModel
 	diags = state.fromSFTPGo(ctx, group)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	diags = r.preservePlanFields(ctx, &plan, &state)
+	diags = r.preservePlanFields(ctx, &plan.groupResourceModel, &state.groupResourceModel)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.ForceDelete = plan.ForceDelete
+
+	if adopted {
+		resp.Diagnostics.Append(checkAdoptedState(ctx, req.Plan, state, "group", plan.Name.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
@@ -198,33 +239,38 @@ func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest,
 // Read refreshes the Terraform state with the latest data.
 func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	// Get current state
-	var state groupResourceModel
+	var state groupResourceWithOptionsModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	group, err := r.client.GetGroup(state.Name.ValueString())
+	group, err := r.client.GetGroup(ctx, state.Name.ValueString())
 	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Group",
-			"Could not read SFTPGo Group "+state.Name.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Group "+state.Name.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
-	var newState groupResourceModel
+	var newState groupResourceWithOptionsModel
 	diags = newState.fromSFTPGo(ctx, group)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	diags = r.preservePlanFields(ctx, &state, &newState)
+	diags = r.preservePlanFields(ctx, &state.groupResourceModel, &newState.groupResourceModel)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	newState.ForceDelete = state.ForceDelete
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &newState)
@@ -237,7 +283,7 @@ func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	// Retrieve values from plan
-	var plan groupResourceModel
+	var plan groupResourceWithOptionsModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -249,35 +295,36 @@ func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	err := r.client.UpdateGroup(*group)
+	err := r.client.UpdateGroup(ctx, *group)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating group",
-			"Could not update group, unexpected error: "+err.Error(),
+			"Could not update group: "+parseAPIError(err),
 		)
 		return
 	}
 
-	group, err = r.client.GetGroup(plan.Name.ValueString())
+	group, err = r.client.GetGroup(ctx, plan.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Group",
-			"Could not read SFTPGo Group "+plan.Name.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Group "+plan.Name.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
-	var state groupResourceModel
+	var state groupResourceWithOptionsModel
 	diags = state.fromSFTPGo(ctx, group)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	diags = r.preservePlanFields(ctx, &plan, &state)
+	diags = r.preservePlanFields(ctx, &plan.groupResourceModel, &state.groupResourceModel)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.ForceDelete = plan.ForceDelete
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -289,19 +336,53 @@ func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *groupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state groupResourceModel
+	var state groupResourceWithOptionsModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// SFTPGo refuses to delete a group with members and reports a generic
+	// error, check the members before deleting
+	group, err := r.client.GetGroup(ctx, state.Name.ValueString())
+	if err != nil {
+		if client.IsNotFound(err) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading SFTPGo Group",
+			"Could not read SFTPGo Group "+state.Name.ValueString()+": "+parseAPIError(err),
+		)
+		return
+	}
+	if len(group.Users) > 0 {
+		if !state.ForceDelete.ValueBool() {
+			resp.Diagnostics.AddError(
+				"Group In Use",
+				fmt.Sprintf("Could not delete group %q, %d users are members of it: %v. Remove the group from "+
+					"these users or set force_delete to remove it automatically.", group.Name, len(group.Users),
+					group.Users),
+			)
+			return
+		}
+		for _, username := range group.Users {
+			if err := r.removeGroupMember(ctx, group.Name, username); err != nil {
+				resp.Diagnostics.AddError(
+					"Error Removing SFTPGo Group Member",
+					fmt.Sprintf("Could not remove group %q from user %q: %s", group.Name, username, parseAPIError(err)),
+				)
+				return
+			}
+		}
+	}
+
 	// Delete existing group
-	err := r.client.DeleteGroup(state.Name.ValueString())
+	err = r.client.DeleteGroup(ctx, state.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting SFTPGo group",
-			"Could not delete group, unexpected error: "+err.Error(),
+			"Could not delete group: "+parseAPIError(err),
 		)
 		return
 	}
@@ -313,7 +394,30 @@ func (*groupResource) ImportState(ctx context.Context, req resource.ImportStateR
 	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 }
 
-func (*groupResource) preservePlanFields(ctx context.Context, plan, state *groupResourceModel) diag.Diagnostics {
+// removeGroupMember removes the group from the specified user.
+func (r *groupResource) removeGroupMember(ctx context.Context, groupName, username string) error {
+	user, err := r.client.GetUser(ctx, username)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	var groups []sdk.GroupMapping
+	for _, g := range user.Groups {
+		if g.Name != groupName {
+			groups = append(groups, g)
+		}
+	}
+	if len(groups) == len(user.Groups) {
+		return nil
+	}
+	user.Groups = groups
+	return r.client.UpdateUser(ctx, *user)
+}
+
+func (r *groupResource) preservePlanFields(ctx context.Context, plan, state *groupResourceModel) diag.Diagnostics {
+	state.Description = preserveEmptyString(plan.Description, state.Description)
 	if plan.UserSettings.IsNull() {
 		return nil
 	}
@@ -334,6 +438,18 @@ func (*groupResource) preservePlanFields(ctx context.Context, plan, state *group
 		return diags
 	}
 
+	settingsState.Permissions = preserveEmptyMap(settingsPlan.Permissions, settingsState.Permissions)
+	// 0 means unlimited and SFTPGo omits it, keep an explicit 0
+	settingsState.QuotaSize = preserveZeroInt64(settingsPlan.QuotaSize, settingsState.QuotaSize)
+	settingsState.QuotaFiles = preserveZeroInt64(settingsPlan.QuotaFiles, settingsState.QuotaFiles)
+	settingsState.UploadBandwidth = preserveZeroInt64(settingsPlan.UploadBandwidth, settingsState.UploadBandwidth)
+	settingsState.DownloadBandwidth = preserveZeroInt64(settingsPlan.DownloadBandwidth, settingsState.DownloadBandwidth)
+	settingsState.UploadDataTransfer = preserveZeroInt64(settingsPlan.UploadDataTransfer,
+		settingsState.UploadDataTransfer)
+	settingsState.DownloadDataTransfer = preserveZeroInt64(settingsPlan.DownloadDataTransfer,
+		settingsState.DownloadDataTransfer)
+	settingsState.TotalDataTransfer = preserveZeroInt64(settingsPlan.TotalDataTransfer, settingsState.TotalDataTransfer)
+
 	var fsPlan filesystem
 	diags = settingsPlan.FsConfig.As(ctx, &fsPlan, basetypes.ObjectAsOptions{
 		UnhandledNullAsEmpty:    true,
@@ -351,12 +467,37 @@ func (*groupResource) preservePlanFields(ctx context.Context, plan, state *group
 		return diags
 	}
 
-	fs, diags := preserveFsConfigPlanFields(ctx, fsPlan, fsState)
+	fs, diags := preserveFsConfigPlanFields(ctx, fsPlan, fsState, r.normalizeSFTPEndpoint)
 	if diags.HasError() {
 		return diags
 	}
 	settingsState.FsConfig = fs
 
+	if !settingsPlan.Filters.IsNull() && !settingsPlan.Filters.IsUnknown() && !settingsState.Filters.IsNull() {
+		var filtersPlan baseUserFilters
+		diags = settingsPlan.Filters.As(ctx, &filtersPlan, basetypes.ObjectAsOptions{
+			UnhandledNullAsEmpty:    true,
+			UnhandledUnknownAsEmpty: true,
+		})
+		if diags.HasError() {
+			return diags
+		}
+		var filtersState baseUserFilters
+		diags = settingsState.Filters.As(ctx, &filtersState, basetypes.ObjectAsOptions{
+			UnhandledNullAsEmpty:    true,
+			UnhandledUnknownAsEmpty: true,
+		})
+		if diags.HasError() {
+			return diags
+		}
+		filtersState.preservePlanFields(filtersPlan)
+		filters, diags := types.ObjectValueFrom(ctx, filtersState.getTFAttributes(), filtersState)
+		if diags.HasError() {
+			return diags
+		}
+		settingsState.Filters = filters
+	}
+
 	settings, diags := types.ObjectValueFrom(ctx, settingsState.getTFAttributes(), settingsState)
 	if diags.HasError() {
 		return diags
@@ -15,11 +15,20 @@
 package sftpgo
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/sftpgo/sdk"
 	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 func TestAccGroupResource(t *testing.T) {
@@ -28,11 +37,11 @@ func TestAccGroupResource(t *testing.T) {
 	}
 	c, err := getClient()
 	require.NoError(t, err)
-	_, err = c.CreateFolder(testFolder)
+	_, err = c.CreateFolder(context.Background(), testFolder)
 	require.NoError(t, err)
 
 	defer func() {
-		err = c.DeleteFolder(testFolder.Name)
+		err = c.DeleteFolder(context.Background(), testFolder.Name)
 		require.NoError(t, err)
 	}()
 
@@ -131,7 +140,9 @@ func TestAccGroupResource(t *testing.T) {
 			{
 				ResourceName:      "sftpgo_group.test",
 				ImportState:       true,
-				ImportStateVerify: false, // SFTPGo will not return plain text secrets
+				ImportStateVerify: true,
+				// SFTPGo will not return plain text password/secrets
+				ImportStateVerifyIgnore: testAccImportStateVerifyIgnore("sftpgo_group"),
 			},
 			// Update and Read testing
 			{
@@ -277,3 +288,247 @@ func TestAccGroupResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccGroupResourceVirtualFoldersOrder(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+	folder1 := sdk.BaseVirtualFolder{
+		Name:       "tfolder1",
+		MappedPath: filepath.Join(os.TempDir(), "tfolder1"),
+	}
+	folder2 := sdk.BaseVirtualFolder{
+		Name:       "tfolder2",
+		MappedPath: filepath.Join(os.TempDir(), "tfolder2"),
+	}
+	_, err = c.CreateFolder(context.Background(), folder1)
+	require.NoError(t, err)
+	_, err = c.CreateFolder(context.Background(), folder2)
+	require.NoError(t, err)
+
+	defer func() {
+		err = c.DeleteFolder(context.Background(), folder1.Name)
+		require.NoError(t, err)
+		err = c.DeleteFolder(context.Background(), folder2.Name)
+		require.NoError(t, err)
+	}()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "sftpgo_group" "test" {
+				  name = "test group"
+				  virtual_folders = [
+					{
+						name = "tfolder2"
+						virtual_path = "/f2"
+						quota_size = 0
+						quota_files = 0
+					},
+					{
+						name = "tfolder1"
+						virtual_path = "/f1"
+						quota_size = -1
+						quota_files = -1
+					}
+				  ]
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_group.test", "virtual_folders.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("sftpgo_group.test", "virtual_folders.*", map[string]string{
+						"name":         "tfolder1",
+						"virtual_path": "/f1",
+						"quota_size":   "-1",
+						"quota_files":  "-1",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("sftpgo_group.test", "virtual_folders.*", map[string]string{
+						"name":         "tfolder2",
+						"virtual_path": "/f2",
+						"quota_size":   "0",
+						"quota_files":  "0",
+					}),
+				),
+			},
+			// Reordering the folders must not produce a diff
+			{
+				Config: `
+				resource "sftpgo_group" "test" {
+				  name = "test group"
+				  virtual_folders = [
+					{
+						name = "tfolder1"
+						virtual_path = "/f1"
+						quota_size = -1
+						quota_files = -1
+					},
+					{
+						name = "tfolder2"
+						virtual_path = "/f2"
+						quota_size = 0
+						quota_files = 0
+					}
+				  ]
+				}`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccGroupResourceDeletedOutsideTerraform(t *testing.T) {
+	testAccDeletedOutsideTerraform(t, "sftpgo_group.test", `
+		resource "sftpgo_group" "test" {
+		  name = "test group deleted"
+		}`, func(c *client.Client) error {
+		return c.DeleteGroup(context.Background(), "test group deleted")
+	})
+}
+
+func TestAccGroupResourceImportFsSecrets(t *testing.T) {
+	testAccImportFsSecrets(t, "sftpgo_group.test", `
+		resource "sftpgo_group" "test" {
+		  name = "test group import secrets"
+		  user_settings = {
+			filesystem = {
+			  provider = 4
+			  cryptconfig = {
+				passphrase = "crypt passphrase"
+			  }
+			}
+		  }
+		}`, "user_settings.filesystem.", "cryptconfig.passphrase")
+}
+
+func TestAccGroupResourceForceDelete(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	getConfig := func(forceDelete bool) string {
+		return fmt.Sprintf(`
+		resource "sftpgo_group" "test" {
+		  name = "test group force delete"
+		  force_delete = %t
+		}`, forceDelete)
+	}
+	user := client.User{
+		User: sdk.User{
+			BaseUser: sdk.BaseUser{
+				Username: "test user group member",
+				HomeDir:  "/tmp/testusergroupmember",
+				Status:   1,
+				Permissions: map[string][]string{
+					"/": {"*"},
+				},
+				Groups: []sdk.GroupMapping{
+					{
+						Name: "test group force delete",
+						Type: sdk.GroupTypeSecondary,
+					},
+				},
+			},
+		},
+		Password: "ahgh3Eethoo6ohpai2Eequ7ai",
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			u, err := c.GetUser(context.Background(), user.Username)
+			if err != nil {
+				return err
+			}
+			if len(u.Groups) > 0 {
+				return fmt.Errorf("group not removed from user %q: %+v", u.Username, u.Groups)
+			}
+			return c.DeleteUser(context.Background(), user.Username)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(false),
+				Check:  resource.TestCheckResourceAttr("sftpgo_group.test", "force_delete", "false"),
+			},
+			// the group has members and force_delete is disabled
+			{
+				PreConfig: func() {
+					_, err := c.CreateUser(context.Background(), user)
+					require.NoError(t, err)
+				},
+				Config:      getConfig(false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("Group In Use"),
+			},
+			{
+				Config: getConfig(true),
+				Check:  resource.TestCheckResourceAttr("sftpgo_group.test", "force_delete", "true"),
+			},
+			// Delete testing automatically occurs in TestCase, the group is
+			// removed from the user
+		},
+	})
+}
+
+func TestAccGroupResourceEmptyPermissions(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	// SFTPGo returns no permissions for an empty map, it must not cause a diff
+	// for the group settings and for a user inheriting the permissions
+	config := `
+		resource "sftpgo_group" "test" {
+		  name = "test group empty permissions"
+		  user_settings = {
+		    permissions = {}
+		  }
+		}
+
+		resource "sftpgo_group" "test_primary" {
+		  name = "test primary group permissions"
+		  user_settings = {
+		    permissions = {
+		      "/" = "*"
+		    }
+		  }
+		}
+
+		resource "sftpgo_user" "test" {
+		  username = "test user empty permissions"
+		  status = 1
+		  home_dir = "/tmp/testuseremptypermissions"
+		  permissions = {}
+		  groups = [
+		    {
+		      name = sftpgo_group.test_primary.name
+		      type = 1
+		    }
+		  ]
+		}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_group.test", "user_settings.permissions.%", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "permissions.%", "0"),
+				),
+			},
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
@@ -144,18 +144,18 @@ func (d *groupsDataSource) Configure(_ context.Context, req datasource.Configure
 		return
 	}
 
-	d.client = req.ProviderData.(*client.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *groupsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state groupsDataSourceModel
 
-	groups, err := d.client.GetGroups()
+	groups, err := d.client.GetGroups(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read SFTPGo Groups",
-			err.Error(),
+			parseAPIError(err),
 		)
 		return
 	}
@@ -15,6 +15,7 @@
 package sftpgo
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -78,15 +79,15 @@ func TestAccGroupsDataSource(t *testing.T) {
 	}
 	c, err := getClient()
 	require.NoError(t, err)
-	_, err = c.CreateFolder(testFolder)
+	_, err = c.CreateFolder(context.Background(), testFolder)
 	require.NoError(t, err)
-	_, err = c.CreateGroup(testGroup)
+	_, err = c.CreateGroup(context.Background(), testGroup)
 	require.NoError(t, err)
 
 	defer func() {
-		err = c.DeleteGroup(testGroup.Name)
+		err = c.DeleteGroup(context.Background(), testGroup.Name)
 		require.NoError(t, err)
-		err = c.DeleteFolder(testFolder.Name)
+		err = c.DeleteFolder(context.Background(), testFolder.Name)
 		require.NoError(t, err)
 	}()
 
@@ -17,11 +17,16 @@ package sftpgo
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/sftpgo/sdk"
@@ -37,6 +42,7 @@ type userResourceModel struct {
 	Email                    types.String       `tfsdk:"email"`
 	Status                   types.Int64        `tfsdk:"status"`
 	ExpirationDate           types.Int64        `tfsdk:"expiration_date"`
+	ExpirationDateRFC3339    types.String       `tfsdk:"expiration_date_rfc3339"`
 	Password                 types.String       `tfsdk:"password"`
 	PublicKeys               types.List         `tfsdk:"public_keys"`
 	HomeDir                  types.String       `tfsdk:"home_dir"`
@@ -57,11 +63,13 @@ type userResourceModel struct {
 	UsedUploadDataTransfer   types.Int64        `tfsdk:"used_upload_data_transfer"`
 	UsedDownloadDataTransfer types.Int64        `tfsdk:"used_download_data_transfer"`
 	LastLogin                types.Int64        `tfsdk:"last_login"`
+	LastLoginRFC3339         types.String       `tfsdk:"last_login_rfc3339"`
 	CreatedAt                types.Int64        `tfsdk:"created_at"`
 	UpdatedAt                types.Int64        `tfsdk:"updated_at"`
 	FirstDownload            types.Int64        `tfsdk:"first_download"`
 	FirstUpload              types.Int64        `tfsdk:"first_upload"`
 	LastPasswordChange       types.Int64        `tfsdk:"last_password_change"`
+	PasswordExpiresAt        types.Int64        `tfsdk:"password_expires_at"`
 	Description              types.String       `tfsdk:"description"`
 	AdditionalInfo           types.String       `tfsdk:"additional_info"`
 	Role                     types.String       `tfsdk:"role"`
@@ -71,6 +79,13 @@ type userResourceModel struct {
 	FsConfig                 types.Object       `tfsdk:"filesystem"`
 }
 
+// userResourceWithTimeoutsModel maps the user resource schema data.
+// The timeouts block is not available in the users data source.
+type userResourceWithTimeoutsModel struct {
+	userResourceModel
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
 func (u *userResourceModel) toSFTPGo(ctx context.Context) (*client.User, diag.Diagnostics) {
 	user := &client.User{
 		User: sdk.User{
@@ -95,7 +110,7 @@ func (u *userResourceModel) toSFTPGo(ctx context.Context) (*client.User, diag.Di
 				Role:                 u.Role.ValueString(),
 			},
 		},
-		Password: u.Password.ValueString(),
+		Password: normalizeSecret(u.Password.ValueString()),
 	}
 	if !u.PublicKeys.IsNull() {
 		diags := u.PublicKeys.ElementsAs(ctx, &user.PublicKeys, false)
@@ -114,6 +129,7 @@ func (u *userResourceModel) toSFTPGo(ctx context.Context) (*client.User, diag.Di
 	for k, v := range permissions {
 		user.Permissions[k] = strings.Split(v, ",")
 	}
+	// the configured order is kept, it defines the secondary groups precedence
 	for _, g := range u.Groups {
 		user.Groups = append(user.Groups, sdk.GroupMapping{
 			Name: g.Name.ValueString(),
@@ -140,15 +156,7 @@ func (u *userResourceModel) toSFTPGo(ctx context.Context) (*client.User, diag.Di
 		}
 		user.VirtualFolders = append(user.VirtualFolders, folder)
 	}
-	var fs filesystem
-	diags = u.FsConfig.As(ctx, &fs, basetypes.ObjectAsOptions{
-		UnhandledNullAsEmpty:    true,
-		UnhandledUnknownAsEmpty: true,
-	})
-	if diags.HasError() {
-		return user, diags
-	}
-	sftpgoFs, diags := fs.toSFTPGo(ctx)
+	sftpgoFs, diags := getSFTPGoFilesystem(ctx, u.FsConfig)
 	if diags.HasError() {
 		return user, diags
 	}
@@ -156,12 +164,39 @@ func (u *userResourceModel) toSFTPGo(ctx context.Context) (*client.User, diag.Di
 	return user, nil
 }
 
+// getPermissionsFromSFTPGo returns the per-directory permissions as comma
+// separated values. SFTPGo does not distinguish between no permissions and
+// an empty map, null is returned in both cases, the resources keep an empty
+// map from the configuration.
+func getPermissionsFromSFTPGo(ctx context.Context, permissions map[string][]string) (types.Map, diag.Diagnostics) {
+	if len(permissions) == 0 {
+		return types.MapNull(types.StringType), nil
+	}
+	values := make(map[string]string)
+	for k, v := range permissions {
+		values[k] = strings.Join(v, ",")
+	}
+	return types.MapValueFrom(ctx, types.StringType, values)
+}
+
+// getPasswordExpiresAt returns the password expiration time, as unix timestamp
+// in milliseconds, based on the user's password_expiration filter and on the
+// last password change. 0 means that the password does not expire. The
+// password_expiration inherited from groups is not taken into account
+func getPasswordExpiresAt(user *client.User) int64 {
+	if user.Filters.PasswordExpiration <= 0 || user.LastPasswordChange <= 0 {
+		return 0
+	}
+	return user.LastPasswordChange + int64(user.Filters.PasswordExpiration)*24*60*60*1000
+}
+
 func (u *userResourceModel) fromSFTPGo(ctx context.Context, user *client.User) diag.Diagnostics {
 	u.Username = types.StringValue(user.Username)
 	u.ID = u.Username
 	u.Status = types.Int64Value(int64(user.Status))
 	u.Email = getOptionalString(user.Email)
 	u.ExpirationDate = getOptionalInt64(user.ExpirationDate)
+	u.ExpirationDateRFC3339 = getRFC3339Timestamp(u.ExpirationDate)
 	u.Password = getOptionalString(user.Password)
 	u.HomeDir = types.StringValue(user.HomeDir)
 	u.UID = getOptionalInt64(int64(user.UID))
@@ -180,11 +215,13 @@ func (u *userResourceModel) fromSFTPGo(ctx context.Context, user *client.User) d
 	u.UsedUploadDataTransfer = getOptionalInt64(user.UsedUploadDataTransfer)
 	u.UsedDownloadDataTransfer = getOptionalInt64(user.UsedDownloadDataTransfer)
 	u.LastLogin = getOptionalInt64(user.LastLogin)
+	u.LastLoginRFC3339 = getRFC3339Timestamp(u.LastLogin)
 	u.CreatedAt = types.Int64Value(user.CreatedAt)
 	u.UpdatedAt = types.Int64Value(user.UpdatedAt)
 	u.FirstDownload = getOptionalInt64(user.FirstDownload)
 	u.FirstUpload = getOptionalInt64(user.FirstUpload)
 	u.LastPasswordChange = getOptionalInt64(user.LastPasswordChange)
+	u.PasswordExpiresAt = getOptionalInt64(getPasswordExpiresAt(user))
 	u.Description = getOptionalString(user.Description)
 	u.AdditionalInfo = getOptionalString(user.AdditionalInfo)
 	u.Role = getOptionalString(user.Role)
@@ -194,15 +231,11 @@ func (u *userResourceModel) fromSFTPGo(ctx context.Context, user *client.User) d
 	}
 	u.PublicKeys = pKeys
 
-	permissions := make(map[string]string)
-	for k, v := range user.Permissions {
-		permissions[k] = strings.Join(v, ",")
-	}
-	tfMap, diags := types.MapValueFrom(ctx, types.StringType, permissions)
+	permissions, diags := getPermissionsFromSFTPGo(ctx, user.Permissions)
 	if diags.HasError() {
 		return diags
 	}
-	u.Permissions = tfMap
+	u.Permissions = permissions
 
 	u.Groups = nil
 	for _, g := range user.Groups {
@@ -211,6 +244,7 @@ func (u *userResourceModel) fromSFTPGo(ctx context.Context, user *client.User) d
 			Type: types.Int64Value(int64(g.Type)),
 		})
 	}
+	sortUserGroupsByType(u.Groups)
 
 	var f userFilters
 	diags = f.fromSFTPGo(ctx, &user.Filters)
@@ -252,6 +286,29 @@ type userGroupMapping struct {
 	Type types.Int64  `tfsdk:"type"`
 }
 
+// sortUserGroupsByType sorts the group mappings by type. Groups of the same
+// type keep the order returned by SFTPGo, which is the order secondary
+// groups are applied in.
+func sortUserGroupsByType(groups []userGroupMapping) {
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Type.ValueInt64() < groups[j].Type.ValueInt64()
+	})
+}
+
+// hasSameUserGroups reports whether the specified group mappings contain
+// the same groups. The order matters only for groups of the same type, the
+// secondary groups are applied in order.
+func hasSameUserGroups(groups1, groups2 []userGroupMapping) bool {
+	if len(groups1) != len(groups2) {
+		return false
+	}
+	sorted1 := slices.Clone(groups1)
+	sorted2 := slices.Clone(groups2)
+	sortUserGroupsByType(sorted1)
+	sortUserGroupsByType(sorted2)
+	return slices.Equal(sorted1, sorted2)
+}
+
 type patternsFilter struct {
 	Path            types.String `tfsdk:"path"`
 	AllowedPatterns types.List   `tfsdk:"allowed_patterns"`
@@ -259,6 +316,54 @@ type patternsFilter struct {
 	DenyPolicy      types.Int64  `tfsdk:"deny_policy"`
 }
 
+// getKey returns a string identifying the patterns regardless of the order
+// of the allowed and denied patterns. It returns false if any value is unknown.
+func (p *patternsFilter) getKey() (string, bool) {
+	if p.Path.IsUnknown() || p.DenyPolicy.IsUnknown() {
+		return "", false
+	}
+	parts := []string{p.Path.ValueString(), strconv.FormatInt(p.DenyPolicy.ValueInt64(), 10)}
+	for _, list := range []types.List{p.AllowedPatterns, p.DeniedPatterns} {
+		if list.IsUnknown() {
+			return "", false
+		}
+		var values []string
+		for _, elem := range list.Elements() {
+			val, ok := elem.(types.String)
+			if !ok || val.IsUnknown() {
+				return "", false
+			}
+			values = append(values, val.ValueString())
+		}
+		slices.Sort(values)
+		parts = append(parts, strings.Join(values, "\x00"))
+	}
+	return strings.Join(parts, "\n"), true
+}
+
+// hasSameFilePatterns reports whether the specified file patterns are the
+// same, regardless of the order.
+func hasSameFilePatterns(patterns1, patterns2 []patternsFilter) bool {
+	if len(patterns1) != len(patterns2) {
+		return false
+	}
+	getKeys := func(patterns []patternsFilter) ([]string, bool) {
+		keys := make([]string, 0, len(patterns))
+		for _, p := range patterns {
+			key, ok := p.getKey()
+			if !ok {
+				return nil, false
+			}
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		return keys, true
+	}
+	keys1, ok1 := getKeys(patterns1)
+	keys2, ok2 := getKeys(patterns2)
+	return ok1 && ok2 && slices.Equal(keys1, keys2)
+}
+
 type bandwidthLimit struct {
 	Sources           types.List  `tfsdk:"sources"`
 	UploadBandwidth   types.Int64 `tfsdk:"upload_bandwidth"`
@@ -473,35 +578,68 @@ func (f *baseUserFilters) toSFTPGo(ctx context.Context) (sdk.BaseUserFilters, di
 	return filters, nil
 }
 
+// preservePlanFields keeps the empty lists and the zero sizes from the plan,
+// they are returned as null by SFTPGo, and the configured file patterns order.
+func (f *baseUserFilters) preservePlanFields(plan baseUserFilters) {
+	if hasSameFilePatterns(plan.FilePatterns, f.FilePatterns) {
+		f.FilePatterns = plan.FilePatterns
+	}
+	f.MaxUploadFileSize = preserveZeroInt64(plan.MaxUploadFileSize, f.MaxUploadFileSize)
+	f.PasswordExpiration = preserveZeroInt64(plan.PasswordExpiration, f.PasswordExpiration)
+	if len(plan.BandwidthLimits) == len(f.BandwidthLimits) {
+		for idx := range f.BandwidthLimits {
+			f.BandwidthLimits[idx].UploadBandwidth = preserveZeroInt64(plan.BandwidthLimits[idx].UploadBandwidth,
+				f.BandwidthLimits[idx].UploadBandwidth)
+			f.BandwidthLimits[idx].DownloadBandwidth = preserveZeroInt64(plan.BandwidthLimits[idx].DownloadBandwidth,
+				f.BandwidthLimits[idx].DownloadBandwidth)
+		}
+	}
+	f.AllowedIP = preserveEmptyList(plan.AllowedIP, f.AllowedIP)
+	f.DeniedIP = preserveEmptyList(plan.DeniedIP, f.DeniedIP)
+	f.DeniedLoginMethods = preserveEmptyList(plan.DeniedLoginMethods, f.DeniedLoginMethods)
+	f.DeniedProtocols = preserveEmptyList(plan.DeniedProtocols, f.DeniedProtocols)
+	f.WebClient = preserveEmptyList(plan.WebClient, f.WebClient)
+	f.TwoFactorAuthProtocols = preserveEmptyList(plan.TwoFactorAuthProtocols, f.TwoFactorAuthProtocols)
+}
+
 func (f *baseUserFilters) fromSFTPGo(ctx context.Context, filters *sdk.BaseUserFilters) diag.Diagnostics {
-	allowedIP, diags := types.ListValueFrom(ctx, types.StringType, filters.AllowedIP)
+	allowedIP, diags := getOptionalStringList(ctx, filters.AllowedIP)
 	if diags.HasError() {
 		return diags
 	}
 	f.AllowedIP = allowedIP
-	deniedIP, diags := types.ListValueFrom(ctx, types.StringType, filters.DeniedIP)
+	deniedIP, diags := getOptionalStringList(ctx, filters.DeniedIP)
 	if diags.HasError() {
 		return diags
 	}
 	f.DeniedIP = deniedIP
-	deniedLoginMethods, diags := types.ListValueFrom(ctx, types.StringType, filters.DeniedLoginMethods)
+	deniedLoginMethods, diags := getOptionalStringList(ctx, filters.DeniedLoginMethods)
 	if diags.HasError() {
 		return diags
 	}
 	f.DeniedLoginMethods = deniedLoginMethods
-	deniedProtocols, diags := types.ListValueFrom(ctx, types.StringType, filters.DeniedProtocols)
+	deniedProtocols, diags := getOptionalStringList(ctx, filters.DeniedProtocols)
 	if diags.HasError() {
 		return diags
 	}
 	f.DeniedProtocols = deniedProtocols
 
 	f.FilePatterns = nil
-	for _, patterns := range filters.FilePatterns {
-		allowedPatterns, diags := types.ListValueFrom(ctx, types.StringType, patterns.AllowedPatterns)
+	// SFTPGo may reorder the patterns, sort them for a stable state
+	filePatterns := slices.Clone(filters.FilePatterns)
+	sort.SliceStable(filePatterns, func(i, j int) bool {
+		return filePatterns[i].Path < filePatterns[j].Path
+	})
+	for _, patterns := range filePatterns {
+		allowed := slices.Clone(patterns.AllowedPatterns)
+		slices.Sort(allowed)
+		denied := slices.Clone(patterns.DeniedPatterns)
+		slices.Sort(denied)
+		allowedPatterns, diags := types.ListValueFrom(ctx, types.StringType, allowed)
 		if diags.HasError() {
 			return diags
 		}
-		deniedPatterns, diags := types.ListValueFrom(ctx, types.StringType, patterns.DeniedPatterns)
+		deniedPatterns, diags := types.ListValueFrom(ctx, types.StringType, denied)
 		if diags.HasError() {
 			return diags
 		}
@@ -519,7 +657,7 @@ func (f *baseUserFilters) fromSFTPGo(ctx context.Context, filters *sdk.BaseUserF
 	f.PreLoginDisabled = getOptionalBool(filters.Hooks.PreLoginDisabled)
 	f.CheckPasswordDisabled = getOptionalBool(filters.Hooks.CheckPasswordDisabled)
 	f.DisableFsChecks = getOptionalBool(filters.DisableFsChecks)
-	webClient, diags := types.ListValueFrom(ctx, types.StringType, filters.WebClient)
+	webClient, diags := getOptionalStringList(ctx, filters.WebClient)
 	if diags.HasError() {
 		return diags
 	}
@@ -550,7 +688,7 @@ func (f *baseUserFilters) fromSFTPGo(ctx context.Context, filters *sdk.BaseUserF
 
 	f.ExternalAuthCacheTime = getOptionalInt64(filters.ExternalAuthCacheTime)
 	f.StartDirectory = getOptionalString(filters.StartDirectory)
-	twoFactorProtos, diags := types.ListValueFrom(ctx, types.StringType, filters.TwoFactorAuthProtocols)
+	twoFactorProtos, diags := getOptionalStringList(ctx, filters.TwoFactorAuthProtocols)
 	if diags.HasError() {
 		return diags
 	}
@@ -708,7 +846,16 @@ func (f *userFilters) fromSFTPGo(ctx context.Context, filters *sdk.UserFilters)
 	}
 	f.fromBaseFilters(&base)
 	f.RequirePasswordChange = getOptionalBool(filters.RequirePasswordChange)
-	tlsCerts, diags := types.ListValueFrom(ctx, types.StringType, filters.TLSCerts)
+	// certificates are normalized, the configured values are preserved if
+	// they differ only for line endings or surrounding white spaces
+	var certs []string
+	if filters.TLSCerts != nil {
+		certs = make([]string, 0, len(filters.TLSCerts))
+	}
+	for _, cert := range filters.TLSCerts {
+		certs = append(certs, normalizePEM(cert))
+	}
+	tlsCerts, diags := types.ListValueFrom(ctx, types.StringType, certs)
 	if diags.HasError() {
 		return diags
 	}
@@ -805,14 +952,15 @@ type httpFsConfig struct {
 }
 
 type filesystem struct {
-	Provider     types.Int64     `tfsdk:"provider"`
-	OSConfig     *osFsConfig     `tfsdk:"osconfig"`
-	S3Config     *s3FsConfig     `tfsdk:"s3config"`
-	GCSConfig    *gcsFsConfig    `tfsdk:"gcsconfig"`
-	AzBlobConfig *azBlobFsConfig `tfsdk:"azblobconfig"`
-	CryptConfig  *cryptFsConfig  `tfsdk:"cryptconfig"`
-	SFTPConfig   *sftpFsConfig   `tfsdk:"sftpconfig"`
-	HTTPConfig   *httpFsConfig   `tfsdk:"httpconfig"`
+	Provider         types.Int64     `tfsdk:"provider"`
+	PlainTextSecrets types.Bool      `tfsdk:"plain_text_secrets"`
+	OSConfig         *osFsConfig     `tfsdk:"osconfig"`
+	S3Config         *s3FsConfig     `tfsdk:"s3config"`
+	GCSConfig        *gcsFsConfig    `tfsdk:"gcsconfig"`
+	AzBlobConfig     *azBlobFsConfig `tfsdk:"azblobconfig"`
+	CryptConfig      *cryptFsConfig  `tfsdk:"cryptconfig"`
+	SFTPConfig       *sftpFsConfig   `tfsdk:"sftpconfig"`
+	HTTPConfig       *httpFsConfig   `tfsdk:"httpconfig"`
 }
 
 func (f *filesystem) ensureNotNull() {
@@ -841,7 +989,8 @@ func (f *filesystem) ensureNotNull() {
 
 func (f *filesystem) getTFAttributes() map[string]attr.Type {
 	return map[string]attr.Type{
-		"provider": types.Int64Type,
+		"provider":           types.Int64Type,
+		"plain_text_secrets": types.BoolType,
 		"osconfig": types.ObjectType{
 			AttrTypes: map[string]attr.Type{
 				"read_buffer_size":  types.Int64Type,
@@ -935,8 +1084,43 @@ func (f *filesystem) getTFAttributes() map[string]attr.Type {
 	}
 }
 
+// getSFTPGoFilesystem converts the filesystem object to the SFTPGo format. A
+// null object means the local filesystem. An unknown object or provider is
+// an error, it must never silently become the local filesystem: the zero
+// value for the provider.
+func getSFTPGoFilesystem(ctx context.Context, fsConfig types.Object) (sdk.Filesystem, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if fsConfig.IsUnknown() {
+		diags.AddError(
+			"Unknown Filesystem Configuration",
+			"The filesystem configuration is not known yet, it cannot be sent to SFTPGo.",
+		)
+		return sdk.Filesystem{}, diags
+	}
+	var fs filesystem
+	diags = fsConfig.As(ctx, &fs, basetypes.ObjectAsOptions{
+		UnhandledNullAsEmpty:    true,
+		UnhandledUnknownAsEmpty: true,
+	})
+	if diags.HasError() {
+		return sdk.Filesystem{}, diags
+	}
+	if fs.Provider.IsUnknown() {
+		diags.AddError(
+			"Unknown Filesystem Provider",
+			"The filesystem provider is not known yet, it cannot be sent to SFTPGo.",
+		)
+		return sdk.Filesystem{}, diags
+	}
+	return fs.toSFTPGo(ctx)
+}
+
 func (f *filesystem) toSFTPGo(ctx context.Context) (sdk.Filesystem, diag.Diagnostics) {
 	f.ensureNotNull()
+	getSecret := getSFTPGoSecret
+	if f.PlainTextSecrets.ValueBool() {
+		getSecret = getSFTPGoPlainSecret
+	}
 	fs := sdk.Filesystem{
 		Provider: sdk.FilesystemProvider(f.Provider.ValueInt64()),
 		OSConfig: sdk.OSFsConfig{
@@ -963,8 +1147,8 @@ func (f *filesystem) toSFTPGo(ctx context.Context) (sdk.Filesystem, diag.Diagnos
 				ForcePathStyle:      f.S3Config.ForcePathStyle.ValueBool(),
 				SkipTLSVerify:       f.S3Config.SkipTLSVerify.ValueBool(),
 			},
-			AccessSecret:   getSFTPGoSecret(f.S3Config.AccessSecret.ValueString()),
-			SSECustomerKey: getSFTPGoSecret(f.S3Config.SSECustomerKey.ValueString()),
+			AccessSecret:   getSecret(f.S3Config.AccessSecret.ValueString()),
+			SSECustomerKey: getSecret(f.S3Config.SSECustomerKey.ValueString()),
 		},
 		GCSConfig: sdk.GCSFsConfig{
 			BaseGCSFsConfig: sdk.BaseGCSFsConfig{
@@ -976,7 +1160,7 @@ func (f *filesystem) toSFTPGo(ctx context.Context) (sdk.Filesystem, diag.Diagnos
 				UploadPartSize:       f.GCSConfig.UploadPartSize.ValueInt64(),
 				UploadPartMaxTime:    int(f.GCSConfig.UploadPartMaxTime.ValueInt64()),
 			},
-			Credentials: getSFTPGoSecret(f.GCSConfig.Credentials.ValueString()),
+			Credentials: getGCSCredentials(f.GCSConfig, getSecret),
 		},
 		AzBlobConfig: sdk.AzBlobFsConfig{
 			BaseAzBlobFsConfig: sdk.BaseAzBlobFsConfig{
@@ -991,11 +1175,11 @@ func (f *filesystem) toSFTPGo(ctx context.Context) (sdk.Filesystem, diag.Diagnos
 				UseEmulator:         f.AzBlobConfig.UseEmulator.ValueBool(),
 				AccessTier:          f.AzBlobConfig.AccessTier.ValueString(),
 			},
-			AccountKey: getSFTPGoSecret(f.AzBlobConfig.AccountKey.ValueString()),
-			SASURL:     getSFTPGoSecret(f.AzBlobConfig.SASURL.ValueString()),
+			AccountKey: getSecret(f.AzBlobConfig.AccountKey.ValueString()),
+			SASURL:     getSecret(f.AzBlobConfig.SASURL.ValueString()),
 		},
 		CryptConfig: sdk.CryptFsConfig{
-			Passphrase: getSFTPGoSecret(f.CryptConfig.Passphrase.ValueString()),
+			Passphrase: getSecret(f.CryptConfig.Passphrase.ValueString()),
 			OSFsConfig: sdk.OSFsConfig{
 				ReadBufferSize:  int(f.CryptConfig.ReadBufferSize.ValueInt64()),
 				WriteBufferSize: int(f.CryptConfig.WriteBufferSize.ValueInt64()),
@@ -1010,9 +1194,9 @@ func (f *filesystem) toSFTPGo(ctx context.Context) (sdk.Filesystem, diag.Diagnos
 				BufferSize:              f.SFTPConfig.BufferSize.ValueInt64(),
 				EqualityCheckMode:       int(f.SFTPConfig.EqualityCheckMode.ValueInt64()),
 			},
-			Password:      getSFTPGoSecret(f.SFTPConfig.Password.ValueString()),
-			PrivateKey:    getSFTPGoSecret(f.SFTPConfig.PrivateKey.ValueString()),
-			KeyPassphrase: getSFTPGoSecret(f.SFTPConfig.KeyPassphrase.ValueString()),
+			Password:      getSecret(f.SFTPConfig.Password.ValueString()),
+			PrivateKey:    getSecret(f.SFTPConfig.PrivateKey.ValueString()),
+			KeyPassphrase: getSecret(f.SFTPConfig.KeyPassphrase.ValueString()),
 		},
 		HTTPConfig: sdk.HTTPFsConfig{
 			BaseHTTPFsConfig: sdk.BaseHTTPFsConfig{
@@ -1021,8 +1205,8 @@ func (f *filesystem) toSFTPGo(ctx context.Context) (sdk.Filesystem, diag.Diagnos
 				SkipTLSVerify:     f.HTTPConfig.SkipTLSVerify.ValueBool(),
 				EqualityCheckMode: int(f.HTTPConfig.EqualityCheckMode.ValueInt64()),
 			},
-			Password: getSFTPGoSecret(f.HTTPConfig.Password.ValueString()),
-			APIKey:   getSFTPGoSecret(f.HTTPConfig.APIKey.ValueString()),
+			Password: getSecret(f.HTTPConfig.Password.ValueString()),
+			APIKey:   getSecret(f.HTTPConfig.APIKey.ValueString()),
 		},
 	}
 
@@ -1075,10 +1259,15 @@ func (f *filesystem) fromSFTPGo(ctx context.Context, fs *sdk.Filesystem) diag.Di
 			SkipTLSVerify:       getOptionalBool(fs.S3Config.SkipTLSVerify),
 		}
 	case sdk.GCSFilesystemProvider:
+		credentials := fs.GCSConfig.Credentials
+		// with automatic credentials the credentials secret is not used
+		if fs.GCSConfig.AutomaticCredentials > 0 {
+			credentials = kms.BaseSecret{}
+		}
 		f.GCSConfig = &gcsFsConfig{
 			Bucket:               getOptionalString(fs.GCSConfig.Bucket),
 			KeyPrefix:            getOptionalString(fs.GCSConfig.KeyPrefix),
-			Credentials:          getOptionalString(getSecretFromSFTPGo(fs.GCSConfig.Credentials)),
+			Credentials:          getOptionalString(getSecretFromSFTPGo(credentials)),
 			AutomaticCredentials: getOptionalInt64(int64(fs.GCSConfig.AutomaticCredentials)),
 			StorageClass:         getOptionalString(fs.GCSConfig.StorageClass),
 			ACL:                  getOptionalString(fs.GCSConfig.ACL),
@@ -1145,27 +1334,18 @@ type virtualFolderResourceModel struct {
 	UsedQuotaSize   types.Int64  `tfsdk:"used_quota_size"`
 	UsedQuotaFiles  types.Int64  `tfsdk:"used_quota_files"`
 	LastQuotaUpdate types.Int64  `tfsdk:"last_quota_update"`
+	ScanInProgress  types.Bool   `tfsdk:"scan_in_progress"`
+	ScanStartTime   types.Int64  `tfsdk:"scan_start_time"`
 	FsConfig        types.Object `tfsdk:"filesystem"`
 }
 
 func (f *virtualFolderResourceModel) toSFTPGo(ctx context.Context) (*sdk.BaseVirtualFolder, diag.Diagnostics) {
 	folder := &sdk.BaseVirtualFolder{
-		Name:            f.Name.ValueString(),
-		MappedPath:      f.MappedPath.ValueString(),
-		Description:     f.Description.ValueString(),
-		UsedQuotaSize:   f.UsedQuotaSize.ValueInt64(),
-		UsedQuotaFiles:  int(f.UsedQuotaFiles.ValueInt64()),
-		LastQuotaUpdate: f.LastQuotaUpdate.ValueInt64(),
-	}
-	var fs filesystem
-	diags := f.FsConfig.As(ctx, &fs, basetypes.ObjectAsOptions{
-		UnhandledNullAsEmpty:    true,
-		UnhandledUnknownAsEmpty: true,
-	})
-	if diags.HasError() {
-		return folder, diags
+		Name:        f.Name.ValueString(),
+		MappedPath:  f.MappedPath.ValueString(),
+		Description: f.Description.ValueString(),
 	}
-	sftpgoFs, diags := fs.toSFTPGo(ctx)
+	sftpgoFs, diags := getSFTPGoFilesystem(ctx, f.FsConfig)
 	if diags.HasError() {
 		return folder, diags
 	}
@@ -1182,6 +1362,8 @@ func (f *virtualFolderResourceModel) fromSFTPGo(ctx context.Context, folder *sdk
 	f.UsedQuotaSize = types.Int64Value(folder.UsedQuotaSize)
 	f.UsedQuotaFiles = types.Int64Value(int64(folder.UsedQuotaFiles))
 	f.LastQuotaUpdate = types.Int64Value(folder.LastQuotaUpdate)
+	f.ScanInProgress = types.BoolValue(false)
+	f.ScanStartTime = types.Int64Value(0)
 
 	var fsConfig filesystem
 	diags := fsConfig.fromSFTPGo(ctx, &folder.FsConfig)
@@ -1197,6 +1379,11 @@ func (f *virtualFolderResourceModel) fromSFTPGo(ctx context.Context, folder *sdk
 	return nil
 }
 
+// setQuotaScan sets the quota scan status from the active folder quota scans.
+func (f *virtualFolderResourceModel) setQuotaScan(scans []client.FolderQuotaScan) {
+	f.ScanInProgress, f.ScanStartTime = getFolderQuotaScan(scans, f.Name.ValueString())
+}
+
 type virtualFolder struct {
 	// embedded structs are not supported
 	//baseVirtualFolder
@@ -1269,25 +1456,72 @@ type roleResourceModel struct {
 	Description types.String `tfsdk:"description"`
 	CreatedAt   types.Int64  `tfsdk:"created_at"`
 	UpdatedAt   types.Int64  `tfsdk:"updated_at"`
+	Admins      types.List   `tfsdk:"admins"`
+	Users       types.List   `tfsdk:"users"`
 }
 
 func (r *roleResourceModel) toSFTPGo(_ context.Context) (*client.Role, diag.Diagnostics) {
 	role := &client.Role{
 		Name:        r.Name.ValueString(),
 		Description: r.Description.ValueString(),
-		CreatedAt:   r.CreatedAt.ValueInt64(),
-		UpdatedAt:   r.UpdatedAt.ValueInt64(),
 	}
 
 	return role, nil
 }
 
-func (r *roleResourceModel) fromSFTPGo(_ context.Context, role *client.Role) diag.Diagnostics {
+func (r *roleResourceModel) fromSFTPGo(ctx context.Context, role *client.Role) diag.Diagnostics {
 	r.Name = types.StringValue(role.Name)
 	r.ID = r.Name
 	r.Description = getOptionalString(role.Description)
 	r.CreatedAt = types.Int64Value(role.CreatedAt)
 	r.UpdatedAt = types.Int64Value(role.UpdatedAt)
+	admins, diags := types.ListValueFrom(ctx, types.StringType, role.Admins)
+	if diags.HasError() {
+		return diags
+	}
+	r.Admins = admins
+	users, diags := types.ListValueFrom(ctx, types.StringType, role.Users)
+	if diags.HasError() {
+		return diags
+	}
+	r.Users = users
+	return nil
+}
+
+type adminAPIKeyResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Admin       types.String `tfsdk:"admin"`
+	Description types.String `tfsdk:"description"`
+	ExpiresAt   types.Int64  `tfsdk:"expires_at"`
+	Key         types.String `tfsdk:"key"`
+	CreatedAt   types.Int64  `tfsdk:"created_at"`
+	UpdatedAt   types.Int64  `tfsdk:"updated_at"`
+}
+
+func (k *adminAPIKeyResourceModel) toSFTPGo(_ context.Context) (*client.APIKey, diag.Diagnostics) {
+	apiKey := &client.APIKey{
+		ID:          k.ID.ValueString(),
+		Name:        k.Name.ValueString(),
+		Scope:       client.APIKeyScopeAdmin,
+		Admin:       k.Admin.ValueString(),
+		Description: k.Description.ValueString(),
+		ExpiresAt:   k.ExpiresAt.ValueInt64(),
+	}
+
+	return apiKey, nil
+}
+
+// fromSFTPGo sets the model from the API key, the secret key is never
+// returned by SFTPGo and is not modified.
+func (k *adminAPIKeyResourceModel) fromSFTPGo(_ context.Context, apiKey *client.APIKey) diag.Diagnostics {
+	k.ID = types.StringValue(apiKey.ID)
+	k.Name = types.StringValue(apiKey.Name)
+	k.Admin = types.StringValue(apiKey.Admin)
+	k.Description = getOptionalString(apiKey.Description)
+	k.ExpiresAt = getOptionalInt64(apiKey.ExpiresAt)
+	k.CreatedAt = types.Int64Value(apiKey.CreatedAt)
+	k.UpdatedAt = types.Int64Value(apiKey.UpdatedAt)
 	return nil
 }
 
@@ -1374,15 +1608,7 @@ func (s *groupUserSettings) toSFTPGo(ctx context.Context) (sdk.GroupUserSettings
 	}
 	settings.Filters = sftpgoFilters
 
-	var fs filesystem
-	diags = s.FsConfig.As(ctx, &fs, basetypes.ObjectAsOptions{
-		UnhandledNullAsEmpty:    true,
-		UnhandledUnknownAsEmpty: true,
-	})
-	if diags.HasError() {
-		return settings, diags
-	}
-	sftpgoFs, diags := fs.toSFTPGo(ctx)
+	sftpgoFs, diags := getSFTPGoFilesystem(ctx, s.FsConfig)
 	if diags.HasError() {
 		return settings, diags
 	}
@@ -1397,19 +1623,11 @@ func (s *groupUserSettings) fromSFTPGo(ctx context.Context, settings *sdk.GroupU
 	s.QuotaSize = getOptionalInt64(settings.QuotaSize)
 	s.QuotaFiles = getOptionalInt64(int64(settings.QuotaFiles))
 
-	permissions := make(map[string]string)
-	for k, v := range settings.Permissions {
-		permissions[k] = strings.Join(v, ",")
-	}
-	if len(permissions) > 0 {
-		tfMap, diags := types.MapValueFrom(ctx, types.StringType, permissions)
-		if diags.HasError() {
-			return diags
-		}
-		s.Permissions = tfMap
-	} else {
-		s.Permissions = types.MapNull(types.StringType)
+	permissions, diags := getPermissionsFromSFTPGo(ctx, settings.Permissions)
+	if diags.HasError() {
+		return diags
 	}
+	s.Permissions = permissions
 
 	s.UploadBandwidth = getOptionalInt64(settings.UploadBandwidth)
 	s.DownloadBandwidth = getOptionalInt64(settings.DownloadBandwidth)
@@ -1419,7 +1637,7 @@ func (s *groupUserSettings) fromSFTPGo(ctx context.Context, settings *sdk.GroupU
 	s.ExpiresIn = getOptionalInt64(int64(settings.ExpiresIn))
 
 	var f baseUserFilters
-	diags := f.fromSFTPGo(ctx, &settings.Filters)
+	diags = f.fromSFTPGo(ctx, &settings.Filters)
 	if diags.HasError() {
 		return diags
 	}
@@ -1453,13 +1671,18 @@ type groupResourceModel struct {
 	VirtualFolders []virtualFolder `tfsdk:"virtual_folders"`
 }
 
+// groupResourceWithOptionsModel maps the group resource schema data.
+// The resource only options are not available in the groups data source.
+type groupResourceWithOptionsModel struct {
+	groupResourceModel
+	ForceDelete types.Bool `tfsdk:"force_delete"`
+}
+
 func (g *groupResourceModel) toSFTPGo(ctx context.Context) (*sdk.Group, diag.Diagnostics) {
 	group := &sdk.Group{
 		BaseGroup: sdk.BaseGroup{
 			Name:        g.Name.ValueString(),
 			Description: g.Description.ValueString(),
-			CreatedAt:   g.CreatedAt.ValueInt64(),
-			UpdatedAt:   g.UpdatedAt.ValueInt64(),
 		},
 	}
 
@@ -1680,9 +1903,6 @@ func (a *adminResourceModel) toSFTPGo(ctx context.Context) (*client.Admin, diag.
 		Password:       a.Password.ValueString(),
 		Description:    a.Description.ValueString(),
 		AdditionalInfo: a.AdditionalInfo.ValueString(),
-		CreatedAt:      a.CreatedAt.ValueInt64(),
-		UpdatedAt:      a.UpdatedAt.ValueInt64(),
-		LastLogin:      a.LastLogin.ValueInt64(),
 		Role:           a.Role.ValueString(),
 	}
 
@@ -2483,6 +2703,13 @@ type eventActionResourceModel struct {
 	Options     types.Object `tfsdk:"options"` // eventActionOptions
 }
 
+// eventActionResourceWithTimeoutsModel maps the event action resource schema data.
+// The timeouts block is not available in the actions data source.
+type eventActionResourceWithTimeoutsModel struct {
+	eventActionResourceModel
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
 func (a *eventActionResourceModel) toSFTPGo(ctx context.Context) (*client.BaseEventAction, diag.Diagnostics) {
 	action := &client.BaseEventAction{
 		Name:        a.Name.ValueString(),
@@ -2556,6 +2783,30 @@ type ruleConditionOptions struct {
 	ConcurrentExecution types.Bool             `tfsdk:"concurrent_execution"`
 }
 
+// preservePlanFields keeps the planned values that SFTPGo omits: false
+// booleans, zero sizes and empty lists.
+func (o *ruleConditionOptions) preservePlanFields(plan *ruleConditionOptions) {
+	o.MinFileSize = preserveZeroInt64(plan.MinFileSize, o.MinFileSize)
+	o.MaxFileSize = preserveZeroInt64(plan.MaxFileSize, o.MaxFileSize)
+	o.ConcurrentExecution = preserveFalseBool(plan.ConcurrentExecution, o.ConcurrentExecution)
+	o.Protocols = preserveEmptyList(plan.Protocols, o.Protocols)
+	o.ProviderObjects = preserveEmptyList(plan.ProviderObjects, o.ProviderObjects)
+	o.EventStatuses = preserveEmptyList(plan.EventStatuses, o.EventStatuses)
+	preservePatternsPlanFields(plan.Names, o.Names)
+	preservePatternsPlanFields(plan.GroupNames, o.GroupNames)
+	preservePatternsPlanFields(plan.RoleNames, o.RoleNames)
+	preservePatternsPlanFields(plan.FsPaths, o.FsPaths)
+}
+
+func preservePatternsPlanFields(plan, state []ruleConditionPattern) {
+	if len(plan) != len(state) {
+		return
+	}
+	for idx := range state {
+		state[idx].InverseMatch = preserveFalseBool(plan[idx].InverseMatch, state[idx].InverseMatch)
+	}
+}
+
 type ruleConditions struct {
 	FsEvents       types.List            `tfsdk:"fs_events"`
 	ProviderEvents types.List            `tfsdk:"provider_events"`
@@ -2784,15 +3035,23 @@ type ruleAction struct {
 }
 
 type eventRuleResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Status      types.Int64  `tfsdk:"status"`
-	Description types.String `tfsdk:"description"`
-	Trigger     types.Int64  `tfsdk:"trigger"`
-	Conditions  types.Object `tfsdk:"conditions"` // ruleConditions
-	Actions     []ruleAction `tfsdk:"actions"`
-	CreatedAt   types.Int64  `tfsdk:"created_at"`
-	UpdatedAt   types.Int64  `tfsdk:"updated_at"`
+	ID             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	Status         types.Int64  `tfsdk:"status"`
+	Description    types.String `tfsdk:"description"`
+	Trigger        types.Int64  `tfsdk:"trigger"`
+	Conditions     types.Object `tfsdk:"conditions"` // ruleConditions
+	Actions        []ruleAction `tfsdk:"actions"`
+	CreatedAt      types.Int64  `tfsdk:"created_at"`
+	UpdatedAt      types.Int64  `tfsdk:"updated_at"`
+	NextRunPreview types.List   `tfsdk:"next_run_preview"`
+}
+
+// eventRuleResourceWithTimeoutsModel maps the event rule resource schema data.
+// The timeouts block is not available in the rules data source.
+type eventRuleResourceWithTimeoutsModel struct {
+	eventRuleResourceModel
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *eventRuleResourceModel) toSFTPGo(ctx context.Context) (*client.EventRule, diag.Diagnostics) {
@@ -2841,8 +3100,15 @@ func (r *eventRuleResourceModel) fromSFTPGo(ctx context.Context, rule *client.Ev
 	r.CreatedAt = types.Int64Value(rule.CreatedAt)
 	r.UpdatedAt = types.Int64Value(rule.UpdatedAt)
 
+	// the configured sequence is stored in the action order, the API does not
+	// guarantee to return the actions sorted
+	actions := make([]client.EventAction, len(rule.Actions))
+	copy(actions, rule.Actions)
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].Order < actions[j].Order
+	})
 	r.Actions = nil
-	for _, action := range rule.Actions {
+	for _, action := range actions {
 		r.Actions = append(r.Actions, ruleAction{
 			Name:            types.StringValue(action.Name),
 			IsFailureAction: getOptionalBool(action.Options.IsFailureAction),
@@ -2861,8 +3127,58 @@ func (r *eventRuleResourceModel) fromSFTPGo(ctx context.Context, rule *client.Ev
 		return diags
 	}
 	r.Conditions = conditions
+	r.NextRunPreview, diags = getRuleNextRunPreview(ctx, r.Trigger, r.Conditions, time.Now())
 
-	return nil
+	return diags
+}
+
+// getRuleNextRunPreview returns the next run times of a scheduled rule as
+// RFC 3339 strings, the preview is null for the other triggers.
+func getRuleNextRunPreview(ctx context.Context, trigger types.Int64, conditions types.Object, from time.Time,
+) (types.List, diag.Diagnostics) {
+	if trigger.IsUnknown() || conditions.IsUnknown() {
+		return types.ListUnknown(types.StringType), nil
+	}
+	if trigger.ValueInt64() != 3 || conditions.IsNull() {
+		return types.ListNull(types.StringType), nil
+	}
+	var c ruleConditions
+	diags := conditions.As(ctx, &c, basetypes.ObjectAsOptions{
+		UnhandledNullAsEmpty:    true,
+		UnhandledUnknownAsEmpty: true,
+	})
+	if diags.HasError() {
+		return types.ListNull(types.StringType), diags
+	}
+	for _, schedule := range c.Schedules {
+		if schedule.Hours.IsUnknown() || schedule.DayOfWeek.IsUnknown() || schedule.DayOfMonth.IsUnknown() ||
+			schedule.Month.IsUnknown() {
+			return types.ListUnknown(types.StringType), nil
+		}
+	}
+	runs, err := getNextScheduledRuns(c.Schedules, from, nextRunPreviewSize)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("conditions").AtName("schedules"),
+			"Invalid Rule Schedule",
+			"Unable to compute the next run times: "+err.Error(),
+		)
+		return types.ListNull(types.StringType), diags
+	}
+	var preview []string
+	for _, run := range runs {
+		preview = append(preview, run.Format(time.RFC3339))
+	}
+	return types.ListValueFrom(ctx, types.StringType, preview)
+}
+
+// getOptionalStringList returns a null list if values is empty, SFTPGo does
+// not distinguish between empty and missing lists.
+func getOptionalStringList(ctx context.Context, values []string) (types.List, diag.Diagnostics) {
+	if len(values) == 0 {
+		return types.ListNull(types.StringType), nil
+	}
+	return types.ListValueFrom(ctx, types.StringType, values)
 }
 
 func getOptionalInt64(val int64) types.Int64 {
@@ -2872,6 +3188,19 @@ func getOptionalInt64(val int64) types.Int64 {
 	return types.Int64Value(val)
 }
 
+// getRFC3339Timestamp returns the specified unix timestamp in milliseconds as
+// RFC 3339 UTC date. Null and unknown values are returned as is, 0 means not
+// set and it is returned as null.
+func getRFC3339Timestamp(val types.Int64) types.String {
+	if val.IsUnknown() {
+		return types.StringUnknown()
+	}
+	if val.IsNull() || val.ValueInt64() == 0 {
+		return types.StringNull()
+	}
+	return types.StringValue(time.UnixMilli(val.ValueInt64()).UTC().Format(time.RFC3339))
+}
+
 func getOptionalString(val string) types.String {
 	if val == "" {
 		return types.StringNull()
@@ -2911,6 +3240,34 @@ func getSFTPGoSecret(val string) kms.BaseSecret {
 	}
 }
 
+// getGCSCredentials returns the GCS credentials secret, it is empty if
+// automatic credentials are enabled.
+func getGCSCredentials(config *gcsFsConfig, getSecret func(string) kms.BaseSecret) kms.BaseSecret {
+	if config.AutomaticCredentials.ValueInt64() > 0 {
+		return kms.BaseSecret{}
+	}
+	return getSecret(config.Credentials.ValueString())
+}
+
+// getSFTPGoPlainSecret returns a plain text secret even if the value matches
+// the SFTPGo secret format.
+func getSFTPGoPlainSecret(val string) kms.BaseSecret {
+	if val == "" {
+		return kms.BaseSecret{}
+	}
+	return kms.BaseSecret{
+		Status:  kms.SecretStatusPlain,
+		Payload: val,
+	}
+}
+
+// isSFTPGoSecretFormat returns true if the value is parsed as an SFTPGo
+// secret instead of a plain text one.
+func isSFTPGoSecretFormat(val string) bool {
+	secret := getSFTPGoSecret(val)
+	return secret.Status != "" && secret.Status != kms.SecretStatusPlain
+}
+
 func getSecretFromSFTPGo(secret kms.BaseSecret) string {
 	if secret.Status == "" {
 		return ""
@@ -15,10 +15,17 @@
 package sftpgo
 
 import (
+	"context"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/sftpgo/sdk"
 	"github.com/sftpgo/sdk/kms"
 	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 func TestSecretsConversion(t *testing.T) {
@@ -33,3 +40,432 @@ func TestSecretsConversion(t *testing.T) {
 	secretFromString := getSFTPGoSecret(secretString)
 	require.Equal(t, secret, secretFromString)
 }
+
+func TestPlainTextSecrets(t *testing.T) {
+	// a plain text secret that looks like an SFTPGo secret
+	val := "$AES-256-GCM$key$4$datapayload"
+	require.True(t, isSFTPGoSecretFormat(val))
+	require.False(t, isSFTPGoSecretFormat("plain secret"))
+	require.False(t, isSFTPGoSecretFormat(""))
+
+	fs := filesystem{
+		Provider: types.Int64Value(int64(sdk.S3FilesystemProvider)),
+		S3Config: &s3FsConfig{
+			Bucket:       types.StringValue("bucket"),
+			AccessSecret: types.StringValue(val),
+		},
+	}
+	sftpgoFs, diags := fs.toSFTPGo(context.Background())
+	require.False(t, diags.HasError())
+	require.Equal(t, kms.SecretStatusAES256GCM, sftpgoFs.S3Config.AccessSecret.Status)
+	require.Equal(t, "payload", sftpgoFs.S3Config.AccessSecret.Payload)
+
+	fs.PlainTextSecrets = types.BoolValue(true)
+	sftpgoFs, diags = fs.toSFTPGo(context.Background())
+	require.False(t, diags.HasError())
+	require.Equal(t, kms.SecretStatusPlain, sftpgoFs.S3Config.AccessSecret.Status)
+	require.Equal(t, val, sftpgoFs.S3Config.AccessSecret.Payload)
+	require.Empty(t, sftpgoFs.S3Config.SSECustomerKey.Status)
+}
+
+func TestGCSAutomaticCredentials(t *testing.T) {
+	// a user relying on workload identity
+	fs := filesystem{
+		Provider: types.Int64Value(int64(sdk.GCSFilesystemProvider)),
+		GCSConfig: &gcsFsConfig{
+			Bucket:               types.StringValue("bucket"),
+			AutomaticCredentials: types.Int64Value(1),
+		},
+	}
+	sftpgoFs, diags := fs.toSFTPGo(context.Background())
+	require.False(t, diags.HasError())
+	require.Equal(t, 1, sftpgoFs.GCSConfig.AutomaticCredentials)
+	require.Empty(t, sftpgoFs.GCSConfig.Credentials.Status)
+	require.Empty(t, sftpgoFs.GCSConfig.Credentials.Payload)
+	// stale credentials returned by SFTPGo must not cause a diff
+	sftpgoFs.GCSConfig.Credentials = kms.BaseSecret{
+		Status:  kms.SecretStatusAES256GCM,
+		Payload: "payload",
+		Key:     "key",
+	}
+	var fsState filesystem
+	diags = fsState.fromSFTPGo(context.Background(), &sftpgoFs)
+	require.False(t, diags.HasError())
+	require.True(t, fsState.GCSConfig.Credentials.IsNull())
+	require.Equal(t, int64(1), fsState.GCSConfig.AutomaticCredentials.ValueInt64())
+
+	fs.GCSConfig.Credentials = types.StringValue("{}")
+	obj, diags := preserveFsConfigPlanFields(context.Background(), fs, fsState, true)
+	require.False(t, diags.HasError())
+	diags = obj.As(context.Background(), &fsState, basetypes.ObjectAsOptions{})
+	require.False(t, diags.HasError())
+	require.True(t, fsState.GCSConfig.Credentials.IsNull())
+
+	fs.GCSConfig.AutomaticCredentials = types.Int64Value(0)
+	sftpgoFs, diags = fs.toSFTPGo(context.Background())
+	require.False(t, diags.HasError())
+	require.Equal(t, kms.SecretStatusPlain, sftpgoFs.GCSConfig.Credentials.Status)
+	require.Equal(t, "{}", sftpgoFs.GCSConfig.Credentials.Payload)
+}
+
+func TestUnknownFilesystem(t *testing.T) {
+	ctx := context.Background()
+	fsAttributes := (&filesystem{}).getTFAttributes()
+
+	_, diags := getSFTPGoFilesystem(ctx, types.ObjectUnknown(fsAttributes))
+	require.True(t, diags.HasError())
+	require.Equal(t, "Unknown Filesystem Configuration", diags.Errors()[0].Summary())
+
+	fsValues := map[string]attr.Value{
+		"provider":           types.Int64Unknown(),
+		"plain_text_secrets": types.BoolNull(),
+	}
+	for name, attrType := range fsAttributes {
+		if objType, ok := attrType.(types.ObjectType); ok {
+			fsValues[name] = types.ObjectNull(objType.AttrTypes)
+		}
+	}
+	obj, diags := types.ObjectValue(fsAttributes, fsValues)
+	require.False(t, diags.HasError(), "unexpected error: %v", diags)
+	_, diags = getSFTPGoFilesystem(ctx, obj)
+	require.True(t, diags.HasError())
+	require.Equal(t, "Unknown Filesystem Provider", diags.Errors()[0].Summary())
+
+	user := userResourceModel{
+		Username: types.StringValue("user"),
+		FsConfig: types.ObjectUnknown(fsAttributes),
+		Filters:  types.ObjectNull((&userFilters{}).getTFAttributes()),
+	}
+	_, diags = user.toSFTPGo(ctx)
+	require.True(t, diags.HasError())
+
+	// a null filesystem is the local one
+	sftpgoFs, diags := getSFTPGoFilesystem(ctx, types.ObjectNull(fsAttributes))
+	require.False(t, diags.HasError(), "unexpected error: %v", diags)
+	require.Equal(t, sdk.LocalFilesystemProvider, sftpgoFs.Provider)
+}
+
+func TestEmptyPermissions(t *testing.T) {
+	ctx := context.Background()
+	permissions, diags := getPermissionsFromSFTPGo(ctx, nil)
+	require.False(t, diags.HasError())
+	require.True(t, permissions.IsNull())
+	permissions, diags = getPermissionsFromSFTPGo(ctx, map[string][]string{})
+	require.False(t, diags.HasError())
+	require.True(t, permissions.IsNull())
+	permissions, diags = getPermissionsFromSFTPGo(ctx, map[string][]string{"/": {"list", "download"}})
+	require.False(t, diags.HasError())
+	require.Equal(t, types.StringValue("list,download"), permissions.Elements()["/"])
+
+	emptyMap := types.MapValueMust(types.StringType, map[string]attr.Value{})
+	require.Equal(t, emptyMap, preserveEmptyMap(emptyMap, types.MapNull(types.StringType)))
+	require.True(t, preserveEmptyMap(types.MapNull(types.StringType), types.MapNull(types.StringType)).IsNull())
+	require.Equal(t, permissions, preserveEmptyMap(emptyMap, permissions))
+}
+
+func TestRFC3339Timestamp(t *testing.T) {
+	require.True(t, getRFC3339Timestamp(types.Int64Null()).IsNull())
+	require.True(t, getRFC3339Timestamp(types.Int64Value(0)).IsNull())
+	require.True(t, getRFC3339Timestamp(types.Int64Unknown()).IsUnknown())
+	require.Equal(t, "2026-01-01T00:00:00Z", getRFC3339Timestamp(types.Int64Value(1767225600000)).ValueString())
+	require.Equal(t, "2026-01-01T00:00:01Z", getRFC3339Timestamp(types.Int64Value(1767225601999)).ValueString())
+
+	var u userResourceModel
+	diags := u.fromSFTPGo(context.Background(), &client.User{
+		User: sdk.User{
+			BaseUser: sdk.BaseUser{
+				Username:       "user",
+				ExpirationDate: 1767225600000,
+				LastLogin:      1767225600000,
+			},
+		},
+	})
+	require.False(t, diags.HasError(), "unexpected error: %v", diags)
+	require.Equal(t, "2026-01-01T00:00:00Z", u.ExpirationDateRFC3339.ValueString())
+	require.Equal(t, "2026-01-01T00:00:00Z", u.LastLoginRFC3339.ValueString())
+}
+
+func TestEmptyFilterLists(t *testing.T) {
+	var filters baseUserFilters
+	diags := filters.fromSFTPGo(context.Background(), &sdk.BaseUserFilters{
+		AllowedIP:              []string{},
+		DeniedProtocols:        []string{},
+		WebClient:              []string{},
+		TwoFactorAuthProtocols: []string{},
+	})
+	require.False(t, diags.HasError())
+	require.True(t, filters.AllowedIP.IsNull())
+	require.True(t, filters.DeniedProtocols.IsNull())
+	require.True(t, filters.WebClient.IsNull())
+	require.True(t, filters.TwoFactorAuthProtocols.IsNull())
+
+	emptyList := types.ListValueMust(types.StringType, []attr.Value{})
+	plan := baseUserFilters{
+		AllowedIP:              emptyList,
+		DeniedProtocols:        emptyList,
+		WebClient:              types.ListNull(types.StringType),
+		TwoFactorAuthProtocols: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("SSH")}),
+	}
+	filters.preservePlanFields(plan)
+	require.Equal(t, emptyList, filters.AllowedIP)
+	require.Equal(t, emptyList, filters.DeniedProtocols)
+	require.True(t, filters.WebClient.IsNull())
+	require.True(t, filters.TwoFactorAuthProtocols.IsNull())
+}
+
+func TestFilePatternsOrder(t *testing.T) {
+	var filters baseUserFilters
+	diags := filters.fromSFTPGo(context.Background(), &sdk.BaseUserFilters{
+		FilePatterns: []sdk.PatternsFilter{
+			{
+				Path:            "/b",
+				AllowedPatterns: []string{"*.txt", "*.jpg"},
+			},
+			{
+				Path:           "/a",
+				DeniedPatterns: []string{"*.zip", "*.exe"},
+				DenyPolicy:     1,
+			},
+		},
+	})
+	require.False(t, diags.HasError())
+	require.Len(t, filters.FilePatterns, 2)
+	require.Equal(t, "/a", filters.FilePatterns[0].Path.ValueString())
+	require.Equal(t, int64(1), filters.FilePatterns[0].DenyPolicy.ValueInt64())
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("*.exe"),
+		types.StringValue("*.zip")}), filters.FilePatterns[0].DeniedPatterns)
+	require.Equal(t, "/b", filters.FilePatterns[1].Path.ValueString())
+	require.True(t, filters.FilePatterns[1].DenyPolicy.IsNull())
+	require.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("*.jpg"),
+		types.StringValue("*.txt")}), filters.FilePatterns[1].AllowedPatterns)
+
+	// the configured order is kept
+	plan := baseUserFilters{
+		FilePatterns: []patternsFilter{
+			{
+				Path: types.StringValue("/b"),
+				AllowedPatterns: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("*.txt"),
+					types.StringValue("*.jpg")}),
+				DeniedPatterns: types.ListNull(types.StringType),
+				DenyPolicy:     types.Int64Null(),
+			},
+			{
+				Path:            types.StringValue("/a"),
+				AllowedPatterns: types.ListNull(types.StringType),
+				DeniedPatterns: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("*.zip"),
+					types.StringValue("*.exe")}),
+				DenyPolicy: types.Int64Value(1),
+			},
+		},
+	}
+	state := filters
+	state.preservePlanFields(plan)
+	require.Equal(t, plan.FilePatterns, state.FilePatterns)
+	// a different pattern is not preserved
+	plan.FilePatterns[0].AllowedPatterns = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("*.txt")})
+	state = filters
+	state.preservePlanFields(plan)
+	require.Equal(t, filters.FilePatterns, state.FilePatterns)
+}
+
+func TestUserGroupsOrder(t *testing.T) {
+	user := &client.User{
+		User: sdk.User{
+			BaseUser: sdk.BaseUser{
+				Username:    "user",
+				HomeDir:     "/tmp/user",
+				Permissions: map[string][]string{"/": {"*"}},
+				Groups: []sdk.GroupMapping{
+					{Name: "group3", Type: sdk.GroupTypeSecondary},
+					{Name: "group4", Type: sdk.GroupTypeMembership},
+					{Name: "group2", Type: sdk.GroupTypeSecondary},
+					{Name: "group1", Type: sdk.GroupTypePrimary},
+				},
+			},
+		},
+	}
+	var u userResourceModel
+	diags := u.fromSFTPGo(context.Background(), user)
+	require.False(t, diags.HasError(), "unexpected error: %v", diags)
+	expected := []userGroupMapping{
+		{Name: types.StringValue("group1"), Type: types.Int64Value(sdk.GroupTypePrimary)},
+		// secondary groups keep the order returned by SFTPGo
+		{Name: types.StringValue("group3"), Type: types.Int64Value(sdk.GroupTypeSecondary)},
+		{Name: types.StringValue("group2"), Type: types.Int64Value(sdk.GroupTypeSecondary)},
+		{Name: types.StringValue("group4"), Type: types.Int64Value(sdk.GroupTypeMembership)},
+	}
+	require.Equal(t, expected, u.Groups)
+
+	// the order matters only for groups of the same type
+	configured := []userGroupMapping{expected[3], expected[1], expected[0], expected[2]}
+	require.True(t, hasSameUserGroups(configured, u.Groups))
+	require.True(t, hasSameUserGroups(nil, nil))
+	require.False(t, hasSameUserGroups(configured[:3], u.Groups))
+	require.False(t, hasSameUserGroups([]userGroupMapping{expected[0], expected[2], expected[1], expected[3]},
+		u.Groups))
+	configured[0] = userGroupMapping{Name: types.StringValue("group3"), Type: types.Int64Value(sdk.GroupTypeMembership)}
+	require.False(t, hasSameUserGroups(configured, u.Groups))
+	// the configured order is not changed
+	require.Equal(t, "group3", configured[1].Name.ValueString())
+}
+
+func TestVirtualFolderQuota(t *testing.T) {
+	for _, quota := range []int64{-1, 0, 100} {
+		f := virtualFolder{
+			Name:        types.StringValue("folder"),
+			MappedPath:  types.StringValue("/tmp/folder"),
+			VirtualPath: types.StringValue("/vdir"),
+			QuotaSize:   types.Int64Value(quota),
+			QuotaFiles:  types.Int64Value(quota),
+			FsConfig: types.ObjectNull(map[string]attr.Type{
+				"provider": types.Int64Type,
+			}),
+		}
+		folder, diags := f.toSFTPGo(context.Background())
+		require.False(t, diags.HasError(), "unexpected error: %v", diags)
+		require.Equal(t, quota, folder.QuotaSize)
+		require.Equal(t, int(quota), folder.QuotaFiles)
+
+		var fromSFTPGo virtualFolder
+		diags = fromSFTPGo.fromSFTPGo(context.Background(), &folder)
+		require.False(t, diags.HasError(), "unexpected error: %v", diags)
+		require.Equal(t, types.Int64Value(quota), fromSFTPGo.QuotaSize)
+		require.Equal(t, types.Int64Value(quota), fromSFTPGo.QuotaFiles)
+	}
+}
+
+func TestRuleConditionOptionsDefaults(t *testing.T) {
+	var conditions ruleConditions
+	diags := conditions.fromSFTPGo(context.Background(), &client.EventRuleConditions{
+		FsEvents: []string{"upload"},
+		Options: client.ConditionOptions{
+			FsPaths: []client.ConditionPattern{
+				{
+					Pattern: "/upload/*",
+				},
+			},
+		},
+	}, 1)
+	require.False(t, diags.HasError())
+	require.NotNil(t, conditions.Options)
+	require.True(t, conditions.Options.MinFileSize.IsNull())
+	require.True(t, conditions.Options.MaxFileSize.IsNull())
+	require.True(t, conditions.Options.ConcurrentExecution.IsNull())
+	require.True(t, conditions.Options.Protocols.IsNull())
+	require.True(t, conditions.Options.FsPaths[0].InverseMatch.IsNull())
+
+	plan := ruleConditionOptions{
+		FsPaths: []ruleConditionPattern{
+			{
+				Pattern:      types.StringValue("/upload/*"),
+				InverseMatch: types.BoolValue(false),
+			},
+		},
+		Protocols:           types.ListValueMust(types.StringType, []attr.Value{}),
+		MinFileSize:         types.Int64Value(0),
+		MaxFileSize:         types.Int64Null(),
+		ConcurrentExecution: types.BoolValue(false),
+	}
+	conditions.Options.preservePlanFields(&plan)
+	require.Equal(t, types.Int64Value(0), conditions.Options.MinFileSize)
+	require.True(t, conditions.Options.MaxFileSize.IsNull())
+	require.Equal(t, types.BoolValue(false), conditions.Options.ConcurrentExecution)
+	require.Equal(t, plan.Protocols, conditions.Options.Protocols)
+	require.Equal(t, types.BoolValue(false), conditions.Options.FsPaths[0].InverseMatch)
+	// values returned by SFTPGo are never replaced
+	conditions.Options.MaxFileSize = types.Int64Value(100)
+	plan.MaxFileSize = types.Int64Value(0)
+	conditions.Options.preservePlanFields(&plan)
+	require.Equal(t, types.Int64Value(100), conditions.Options.MaxFileSize)
+}
+
+func TestRuleActionsOrder(t *testing.T) {
+	rule := &client.EventRule{
+		Name:    "rule",
+		Trigger: 1,
+		Actions: []client.EventAction{
+			{
+				Name:  "b",
+				Order: 2,
+			},
+			{
+				Name:  "c",
+				Order: 3,
+			},
+			{
+				Name:  "a",
+				Order: 1,
+			},
+		},
+	}
+	var model eventRuleResourceModel
+	diags := model.fromSFTPGo(context.Background(), rule)
+	require.False(t, diags.HasError())
+	require.Len(t, model.Actions, 3)
+	require.Equal(t, "a", model.Actions[0].Name.ValueString())
+	require.Equal(t, "b", model.Actions[1].Name.ValueString())
+	require.Equal(t, "c", model.Actions[2].Name.ValueString())
+	// the API response is not modified
+	require.Equal(t, "b", rule.Actions[0].Name)
+}
+
+func TestZeroLimits(t *testing.T) {
+	var filters baseUserFilters
+	diags := filters.fromSFTPGo(context.Background(), &sdk.BaseUserFilters{
+		BandwidthLimits: []sdk.BandwidthLimit{
+			{
+				Sources:         []string{"192.168.1.0/24"},
+				UploadBandwidth: 100,
+			},
+		},
+	})
+	require.False(t, diags.HasError())
+	require.True(t, filters.MaxUploadFileSize.IsNull())
+	require.True(t, filters.BandwidthLimits[0].DownloadBandwidth.IsNull())
+
+	plan := baseUserFilters{
+		MaxUploadFileSize: types.Int64Value(0),
+		BandwidthLimits: []bandwidthLimit{
+			{
+				Sources:           types.ListValueMust(types.StringType, []attr.Value{types.StringValue("192.168.1.0/24")}),
+				UploadBandwidth:   types.Int64Value(100),
+				DownloadBandwidth: types.Int64Value(0),
+			},
+		},
+	}
+	filters.preservePlanFields(plan)
+	require.Equal(t, types.Int64Value(0), filters.MaxUploadFileSize)
+	require.Equal(t, types.Int64Value(100), filters.BandwidthLimits[0].UploadBandwidth)
+	require.Equal(t, types.Int64Value(0), filters.BandwidthLimits[0].DownloadBandwidth)
+
+	require.Equal(t, types.Int64Value(10), preserveZeroInt64(types.Int64Value(0), types.Int64Value(10)))
+	require.True(t, preserveZeroInt64(types.Int64Null(), types.Int64Null()).IsNull())
+	require.True(t, preserveZeroInt64(types.Int64Unknown(), types.Int64Null()).IsNull())
+}
+
+func TestPreserveEmptyString(t *testing.T) {
+	require.Equal(t, types.StringValue(""), preserveEmptyString(types.StringValue(""), types.StringNull()))
+	require.Equal(t, types.StringValue("desc"), preserveEmptyString(types.StringValue(""), types.StringValue("desc")))
+	require.True(t, preserveEmptyString(types.StringValue("desc"), types.StringNull()).IsNull())
+	require.True(t, preserveEmptyString(types.StringNull(), types.StringNull()).IsNull())
+	require.True(t, preserveEmptyString(types.StringUnknown(), types.StringNull()).IsNull())
+}
+
+func TestPasswordExpiresAt(t *testing.T) {
+	user := &client.User{}
+	user.LastPasswordChange = 1000
+	require.Equal(t, int64(0), getPasswordExpiresAt(user))
+	user.Filters.PasswordExpiration = 2
+	require.Equal(t, int64(1000+2*24*60*60*1000), getPasswordExpiresAt(user))
+	user.LastPasswordChange = 0
+	require.Equal(t, int64(0), getPasswordExpiresAt(user))
+
+	var filters baseUserFilters
+	diags := filters.fromSFTPGo(context.Background(), &sdk.BaseUserFilters{})
+	require.False(t, diags.HasError())
+	require.True(t, filters.PasswordExpiration.IsNull())
+	filters.preservePlanFields(baseUserFilters{
+		PasswordExpiration: types.Int64Value(0),
+	})
+	require.Equal(t, types.Int64Value(0), filters.PasswordExpiration)
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &parseSizeFunction{}
+
+// NewParseSizeFunction is a helper function to simplify the provider implementation.
+func NewParseSizeFunction() function.Function {
+	return &parseSizeFunction{}
+}
+
+// parseSizeFunction converts human readable sizes to bytes.
+type parseSizeFunction struct{}
+
+// Metadata returns the function name.
+func (f *parseSizeFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_size"
+}
+
+// Definition defines the function parameters and return type.
+func (f *parseSizeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts a human readable size to bytes",
+		Description: `Converts a human readable size, for example "100MB" or "1.5GiB", to bytes. ` +
+			`KB, MB, GB, TB are powers of 1000, KiB, MiB, GiB, TiB are powers of 1024, a number without unit is in bytes. ` +
+			"The result can be used for size attributes, such as max_upload_file_size.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "size",
+				Description: "Human readable size.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+// Run parses the size.
+func (f *parseSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var size string
+
+	resp.Error = req.Arguments.Get(ctx, &size)
+	if resp.Error != nil {
+		return
+	}
+
+	result, err := parseSize(size)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, result)
+}
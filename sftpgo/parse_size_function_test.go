@@ -0,0 +1,51 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSizeFunction(t *testing.T) {
+	f := NewParseSizeFunction()
+	run := func(size string) function.RunResponse {
+		resp := function.RunResponse{
+			Result: function.NewResultData(types.Int64Unknown()),
+		}
+		f.Run(context.Background(), function.RunRequest{
+			Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(size)}),
+		}, &resp)
+		return resp
+	}
+
+	resp := run("100MB")
+	require.Nil(t, resp.Error)
+	require.Equal(t, types.Int64Value(100000000), resp.Result.Value())
+
+	resp = run("1KiB")
+	require.Nil(t, resp.Error)
+	require.Equal(t, types.Int64Value(1024), resp.Result.Value())
+
+	resp = run("1XB")
+	require.NotNil(t, resp.Error)
+	require.NotNil(t, resp.Error.FunctionArgument)
+	require.Equal(t, int64(0), *resp.Error.FunctionArgument)
+}
@@ -17,14 +17,21 @@ package sftpgo
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -37,7 +44,8 @@ const (
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ provider.Provider = &sftpgoProvider{}
+	_ provider.Provider              = &sftpgoProvider{}
+	_ provider.ProviderWithFunctions = &sftpgoProvider{}
 )
 
 // New is a helper function to simplify provider server and testing implementation.
@@ -47,16 +55,50 @@ func New() provider.Provider {
 
 // sftpgoProviderModel maps provider schema data to a Go type.
 type sftpgoProviderModel struct {
-	Host     types.String `tfsdk:"host"`
-	Username types.String `tfsdk:"username"`
-	Password types.String `tfsdk:"password"`
-	APIKey   types.String `tfsdk:"api_key"`
-	Headers  []keyValue   `tfsdk:"headers"`
+	Host            types.String `tfsdk:"host"`
+	Hosts           types.List   `tfsdk:"hosts"`
+	BasePath        types.String `tfsdk:"base_path"`
+	Username        types.String `tfsdk:"username"`
+	Password        types.String `tfsdk:"password"`
+	APIKey          types.String `tfsdk:"api_key"`
+	Headers         []keyValue   `tfsdk:"headers"`
+	UserAgentSuffix types.String `tfsdk:"user_agent_suffix"`
+	Timeout         types.Int64  `tfsdk:"timeout"`
+	RetryMax        types.Int64  `tfsdk:"retry_max"`
+	RetryWait       types.Int64  `tfsdk:"retry_wait"`
+
+	CACert        types.String `tfsdk:"ca_cert"`
+	ClientCert    types.String `tfsdk:"client_cert"`
+	ClientKey     types.String `tfsdk:"client_key"`
+	SkipTLSVerify types.Bool   `tfsdk:"skip_tls_verify"`
+
+	CheckReferences       types.Bool `tfsdk:"check_references"`
+	NormalizeSFTPEndpoint types.Bool `tfsdk:"normalize_sftp_endpoint"`
+
+	DefaultUserStatus types.Int64  `tfsdk:"default_user_status"`
+	DefaultUserRole   types.String `tfsdk:"default_user_role"`
 }
 
 // sftpgoProvider is the provider implementation.
 type sftpgoProvider struct{}
 
+// providerData is made available to resources and data sources in their
+// Configure methods. It holds the API client and the provider level
+// settings that are not related to the API connection.
+type providerData struct {
+	client *client.Client
+	// checkReferences, if set, enables the plan time checks that the
+	// referenced objects exist
+	checkReferences bool
+	// normalizeSFTPEndpoint, if set, makes SFTP endpoints without a port
+	// equal to the same endpoints with the default port
+	normalizeSFTPEndpoint bool
+	// defaultUserStatus and defaultUserRole, if set, are applied to users
+	// not defining them. A nil status means no default
+	defaultUserStatus *int
+	defaultUserRole   string
+}
+
 // Metadata returns the provider type name.
 func (p *sftpgoProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "sftpgo"
@@ -65,12 +107,24 @@ func (p *sftpgoProvider) Metadata(_ context.Context, _ provider.MetadataRequest,
 // Schema defines the provider-level schema for configuration data.
 func (p *sftpgoProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Interact with SFTPGo.",
+		Description: "Interact with SFTPGo. Users, groups, folders and admins are updated by sending the whole object. " +
+			"If the SFTPGo server is newer than 2.6, the provider reads the object before each update, including its " +
+			"confidential data, so the fields that the provider does not know about are preserved. In this case, " +
+			"the admin used by the provider requires the permission to read confidential data.",
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
 				Optional:    true,
 				Description: "URI for SFTPGo API. May also be provided via SFTPGO_HOST environment variable.",
 			},
+			"hosts": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Additional URIs for SFTPGo API, tried in order if the host is unreachable. Useful for high availability setups without a load balancer. May also be provided via SFTPGO_HOSTS environment variable as a comma separated list.",
+			},
+			"base_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path prefix for SFTPGo API, required if SFTPGo is served under a sub-path, for example \"/sftpgo\" behind a reverse proxy. The prefix can also be included in the host URI. May also be provided via SFTPGO_BASE_PATH environment variable.",
+			},
 			"username": schema.StringAttribute{
 				Optional:    true,
 				Description: "Username for SFTPGo API. May also be provided via SFTPGO_USERNAME environment variable.",
@@ -83,7 +137,7 @@ func (p *sftpgoProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 			"api_key": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				Description: "SFTPGo API key. May also be provided via SFTPGO_API_KEY environment variable. You must provide an API key or username and password. If both an API key and username and password are provided, the API key will be used.",
+				Description: "SFTPGo API key. May also be provided via SFTPGO_API_KEY environment variable. You must provide either an API key or username and password, not both.",
 			},
 			"headers": schema.ListNestedAttribute{
 				Optional:    true,
@@ -101,6 +155,67 @@ func (p *sftpgoProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 					},
 				},
 			},
+			"user_agent_suffix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Text appended to the User-Agent sent with each request, useful to identify the changes made by a specific Terraform configuration in the SFTPGo logs. The User-Agent always includes the Terraform and provider versions. May also be provided via SFTPGO_USER_AGENT_SUFFIX environment variable.",
+			},
+			"timeout": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Timeout for SFTPGo API requests as seconds. Default: 20. May also be provided via SFTPGO_TIMEOUT environment variable.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"retry_max": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of retries for failed requests. Requests rejected with 429 or 503 status codes are always retried, idempotent requests (GET, PUT, DELETE) are also retried on network errors and 502, 504 status codes. Resource creations are retried on network errors too: if the retry reports that the object already exists, the existing object is adopted if it matches the configuration. Default: 0 (no retries). May also be provided via SFTPGO_RETRY_MAX environment variable.",
+				Validators: []validator.Int64{
+					int64validator.Between(0, 10),
+				},
+			},
+			"retry_wait": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Wait time before the first retry as seconds, it is doubled after each attempt. The Retry-After header, if returned, takes precedence. Default: 1. May also be provided via SFTPGO_RETRY_WAIT environment variable.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"ca_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded CA certificate, or path to a PEM file, used to verify the SFTPGo API server certificate. If not set, the system CAs are used. May also be provided via SFTPGO_CA_CERT environment variable.",
+			},
+			"client_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded client certificate, or path to a PEM file, for mutual TLS authentication. Must be set together with client_key. May also be provided via SFTPGO_CLIENT_CERT environment variable.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM encoded client private key, or path to a PEM file, for mutual TLS authentication. Must be set together with client_cert. May also be provided via SFTPGO_CLIENT_KEY environment variable.",
+			},
+			"skip_tls_verify": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If enabled, the SFTPGo API server certificate is not verified. This is insecure and should only be used for testing, prefer ca_cert to trust a private CA. May also be provided via SFTPGO_SKIP_TLS_VERIFY environment variable.",
+			},
+			"check_references": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If enabled, the groups referenced by users are checked at plan time, so typos are reported before applying any change. This requires additional API calls. May also be provided via SFTPGO_CHECK_REFERENCES environment variable.",
+			},
+			"normalize_sftp_endpoint": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If enabled, SFTP filesystem endpoints without a port are considered equal to the same endpoints with the default port 22 added by SFTPGo, so no changes are planned. If disabled, the port must always be specified. Default: true. May also be provided via SFTPGO_NORMALIZE_SFTP_ENDPOINT environment variable.",
+			},
+			"default_user_status": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Status applied to users that do not set it. 1 enabled, 0 disabled. If not set, the status is required for each user. May also be provided via SFTPGO_DEFAULT_USER_STATUS environment variable.",
+				Validators: []validator.Int64{
+					int64validator.Between(0, 1),
+				},
+			},
+			"default_user_role": schema.StringAttribute{
+				Optional:    true,
+				Description: "Role applied to users that do not set it. May also be provided via SFTPGO_DEFAULT_USER_ROLE environment variable.",
+			},
 		},
 	}
 }
@@ -130,6 +245,24 @@ func (p *sftpgoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		)
 	}
 
+	if config.Hosts.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hosts"),
+			"Unknown SFTPGo API Hosts",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the SFTPGo API hosts. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_HOSTS environment variable.",
+		)
+	}
+
+	if config.BasePath.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("base_path"),
+			"Unknown SFTPGo API Base Path",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the SFTPGo API base path. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_BASE_PATH environment variable.",
+		)
+	}
+
 	if config.Username.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("username"),
@@ -157,6 +290,114 @@ func (p *sftpgoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		)
 	}
 
+	if config.UserAgentSuffix.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("user_agent_suffix"),
+			"Unknown SFTPGo API User Agent Suffix",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the SFTPGo API user agent suffix. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_USER_AGENT_SUFFIX environment variable.",
+		)
+	}
+
+	if config.Timeout.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("timeout"),
+			"Unknown SFTPGo API Timeout",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the SFTPGo API timeout. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_TIMEOUT environment variable.",
+		)
+	}
+
+	if config.RetryMax.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry_max"),
+			"Unknown SFTPGo API Max Retries",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the SFTPGo API max retries. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_RETRY_MAX environment variable.",
+		)
+	}
+
+	if config.RetryWait.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry_wait"),
+			"Unknown SFTPGo API Retry Wait",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the SFTPGo API retry wait. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_RETRY_WAIT environment variable.",
+		)
+	}
+
+	if config.CACert.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_cert"),
+			"Unknown SFTPGo API CA Certificate",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the SFTPGo API CA certificate. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_CA_CERT environment variable.",
+		)
+	}
+
+	if config.ClientCert.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_cert"),
+			"Unknown SFTPGo API Client Certificate",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the SFTPGo API client certificate. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_CLIENT_CERT environment variable.",
+		)
+	}
+
+	if config.ClientKey.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_key"),
+			"Unknown SFTPGo API Client Key",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the SFTPGo API client key. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_CLIENT_KEY environment variable.",
+		)
+	}
+
+	if config.SkipTLSVerify.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("skip_tls_verify"),
+			"Unknown SFTPGo API Skip TLS Verify",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the SFTPGo API skip TLS verify setting. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_SKIP_TLS_VERIFY environment variable.",
+		)
+	}
+
+	if config.CheckReferences.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("check_references"),
+			"Unknown SFTPGo Check References",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the check references setting. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_CHECK_REFERENCES environment variable.",
+		)
+	}
+
+	if config.NormalizeSFTPEndpoint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("normalize_sftp_endpoint"),
+			"Unknown SFTPGo Normalize SFTP Endpoint",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the normalize SFTP endpoint setting. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_NORMALIZE_SFTP_ENDPOINT environment variable.",
+		)
+	}
+
+	if config.DefaultUserStatus.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_user_status"),
+			"Unknown SFTPGo Default User Status",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the default user status. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_DEFAULT_USER_STATUS environment variable.",
+		)
+	}
+
+	if config.DefaultUserRole.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_user_role"),
+			"Unknown SFTPGo Default User Role",
+			"The provider cannot create the SFTPGo API client as there is an unknown configuration value for the default user role. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SFTPGO_DEFAULT_USER_ROLE environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -165,15 +406,45 @@ func (p *sftpgoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	// with Terraform configuration value if set.
 
 	host := os.Getenv("SFTPGO_HOST")
+	hosts := getListFromEnv("SFTPGO_HOSTS")
+	basePath := os.Getenv("SFTPGO_BASE_PATH")
 	username := os.Getenv("SFTPGO_USERNAME")
 	password := os.Getenv("SFTPGO_PASSWORD")
 	apiKey := os.Getenv("SFTPGO_API_KEY")
 	headers := getHeadersFromEnv()
+	userAgentSuffix := os.Getenv("SFTPGO_USER_AGENT_SUFFIX")
+	timeout := getInt64FromEnv("SFTPGO_TIMEOUT", path.Root("timeout"), int64(client.DefaultTimeout/time.Second), 1,
+		math.MaxInt64, &resp.Diagnostics)
+	retryMax := getInt64FromEnv("SFTPGO_RETRY_MAX", path.Root("retry_max"), 0, 0, 10, &resp.Diagnostics)
+	retryWait := getInt64FromEnv("SFTPGO_RETRY_WAIT", path.Root("retry_wait"), int64(client.DefaultRetryWait/time.Second), 1,
+		math.MaxInt64, &resp.Diagnostics)
+	tlsConfig := client.TLSConfig{
+		CACert:     os.Getenv("SFTPGO_CA_CERT"),
+		ClientCert: os.Getenv("SFTPGO_CLIENT_CERT"),
+		ClientKey:  os.Getenv("SFTPGO_CLIENT_KEY"),
+		SkipVerify: getBoolFromEnv("SFTPGO_SKIP_TLS_VERIFY", path.Root("skip_tls_verify"), false, &resp.Diagnostics),
+	}
+	checkReferences := getBoolFromEnv("SFTPGO_CHECK_REFERENCES", path.Root("check_references"), false, &resp.Diagnostics)
+	normalizeSFTPEndpoint := getBoolFromEnv("SFTPGO_NORMALIZE_SFTP_ENDPOINT", path.Root("normalize_sftp_endpoint"), true,
+		&resp.Diagnostics)
+	defaultUserStatus := getInt64FromEnv("SFTPGO_DEFAULT_USER_STATUS", path.Root("default_user_status"), -1, 0, 1,
+		&resp.Diagnostics)
+	defaultUserRole := os.Getenv("SFTPGO_DEFAULT_USER_ROLE")
 
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
 	}
 
+	if !config.Hosts.IsNull() && !config.Hosts.IsUnknown() {
+		hosts = nil
+		diags = config.Hosts.ElementsAs(ctx, &hosts, false)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if !config.BasePath.IsNull() {
+		basePath = config.BasePath.ValueString()
+	}
+
 	if !config.Username.IsNull() {
 		username = config.Username.ValueString()
 	}
@@ -186,6 +457,54 @@ func (p *sftpgoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		apiKey = config.APIKey.ValueString()
 	}
 
+	if !config.UserAgentSuffix.IsNull() {
+		userAgentSuffix = config.UserAgentSuffix.ValueString()
+	}
+
+	if !config.Timeout.IsNull() {
+		timeout = config.Timeout.ValueInt64()
+	}
+
+	if !config.RetryMax.IsNull() {
+		retryMax = config.RetryMax.ValueInt64()
+	}
+
+	if !config.RetryWait.IsNull() {
+		retryWait = config.RetryWait.ValueInt64()
+	}
+
+	if !config.CACert.IsNull() {
+		tlsConfig.CACert = config.CACert.ValueString()
+	}
+
+	if !config.ClientCert.IsNull() {
+		tlsConfig.ClientCert = config.ClientCert.ValueString()
+	}
+
+	if !config.ClientKey.IsNull() {
+		tlsConfig.ClientKey = config.ClientKey.ValueString()
+	}
+
+	if !config.SkipTLSVerify.IsNull() {
+		tlsConfig.SkipVerify = config.SkipTLSVerify.ValueBool()
+	}
+
+	if !config.CheckReferences.IsNull() {
+		checkReferences = config.CheckReferences.ValueBool()
+	}
+
+	if !config.NormalizeSFTPEndpoint.IsNull() {
+		normalizeSFTPEndpoint = config.NormalizeSFTPEndpoint.ValueBool()
+	}
+
+	if !config.DefaultUserStatus.IsNull() {
+		defaultUserStatus = config.DefaultUserStatus.ValueInt64()
+	}
+
+	if !config.DefaultUserRole.IsNull() {
+		defaultUserRole = config.DefaultUserRole.ValueString()
+	}
+
 	if len(config.Headers) > 0 {
 		headers = nil
 		for _, h := range config.Headers {
@@ -209,6 +528,15 @@ func (p *sftpgoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		)
 	}
 
+	if apiKey != "" && (username != "" || password != "") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_key"),
+			"Conflicting SFTPGo API Credentials",
+			"The provider cannot create the SFTPGo API client as both an API key and a username/password are configured. "+
+				"Set either the api_key value or the username and password values in the configuration or environment variables, not both.",
+		)
+	}
+
 	if apiKey == "" {
 		if username == "" {
 			resp.Diagnostics.AddAttributeError(
@@ -231,15 +559,54 @@ func (p *sftpgoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		}
 	}
 
+	if defaultUserStatus > 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_user_status"),
+			"Invalid SFTPGo Default User Status",
+			fmt.Sprintf("The default user status must be 0 or 1, got: %d.", defaultUserStatus),
+		)
+	}
+
+	if tlsConfig.ClientCert != "" && tlsConfig.ClientKey == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_key"),
+			"Missing SFTPGo API Client Key",
+			"The provider cannot create the SFTPGo API client as a client certificate is set without the related key. "+
+				"Set the client_key value in the configuration or use the SFTPGO_CLIENT_KEY environment variable.",
+		)
+	}
+
+	if tlsConfig.ClientKey != "" && tlsConfig.ClientCert == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_cert"),
+			"Missing SFTPGo API Client Certificate",
+			"The provider cannot create the SFTPGo API client as a client key is set without the related certificate. "+
+				"Set the client_cert value in the configuration or use the SFTPGO_CLIENT_CERT environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	ctx = tflog.SetField(ctx, "SFTPGo_host", config.Host)
+	ctx = tflog.SetField(ctx, "SFTPGo_hosts", hosts)
+	ctx = tflog.SetField(ctx, "SFTPGo_base_path", basePath)
 	ctx = tflog.SetField(ctx, "SFTPGo_username", config.Username)
 	ctx = tflog.SetField(ctx, "SFTPGo_password", config.Password)
 	ctx = tflog.SetField(ctx, "SFTPGo_api_key", config.APIKey)
 	ctx = tflog.SetField(ctx, "SFTPGo_headers", config.Headers)
+	ctx = tflog.SetField(ctx, "SFTPGo_user_agent_suffix", userAgentSuffix)
+	ctx = tflog.SetField(ctx, "SFTPGo_timeout", timeout)
+	ctx = tflog.SetField(ctx, "SFTPGo_retry_max", retryMax)
+	ctx = tflog.SetField(ctx, "SFTPGo_retry_wait", retryWait)
+	ctx = tflog.SetField(ctx, "SFTPGo_ca_cert", tlsConfig.CACert)
+	ctx = tflog.SetField(ctx, "SFTPGo_client_cert", tlsConfig.ClientCert)
+	ctx = tflog.SetField(ctx, "SFTPGo_skip_tls_verify", tlsConfig.SkipVerify)
+	ctx = tflog.SetField(ctx, "SFTPGo_check_references", checkReferences)
+	ctx = tflog.SetField(ctx, "SFTPGo_normalize_sftp_endpoint", normalizeSFTPEndpoint)
+	ctx = tflog.SetField(ctx, "SFTPGo_default_user_status", defaultUserStatus)
+	ctx = tflog.SetField(ctx, "SFTPGo_default_user_role", defaultUserRole)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "SFTPGo_password")
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "SFTPGo_api_key")
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "SFTPGo_headers")
@@ -253,15 +620,49 @@ func (p *sftpgoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 			"Unable to Create SFTPGo API Client",
 			"An unexpected error occurred when creating the SFTPGo API client. "+
 				"If the error is not clear, please check the SFTPGo logs.\n\n"+
+				"SFTPGo Client Error: "+parseAPIError(err),
+		)
+		return
+	}
+	client.SetFallbackHosts(hosts)
+	client.SetBasePath(basePath)
+	client.UserAgent = getUserAgent(req.TerraformVersion, userAgentSuffix)
+	client.HTTPClient.Timeout = time.Duration(timeout) * time.Second
+	client.RetryMax = int(retryMax)
+	client.RetryWait = time.Duration(retryWait) * time.Second
+	if err := client.SetTLSConfig(tlsConfig); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid SFTPGo API TLS Configuration",
+			"An unexpected error occurred when configuring TLS for the SFTPGo API client. "+
+				"Check the ca_cert, client_cert and client_key values.\n\n"+
 				"SFTPGo Client Error: "+err.Error(),
 		)
 		return
 	}
+	if tlsConfig.SkipVerify {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("skip_tls_verify"),
+			"SFTPGo API TLS Certificate Verification Disabled",
+			"The SFTPGo API server certificate is not verified, the connection is susceptible to man-in-the-middle "+
+				"attacks. This should be used only for testing, use ca_cert to trust a private CA and client_cert "+
+				"and client_key for mutual TLS authentication.",
+		)
+	}
 
-	// Make the SFTPGo client available during DataSource and Resource
-	// type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	data := &providerData{
+		client:                client,
+		checkReferences:       checkReferences,
+		normalizeSFTPEndpoint: normalizeSFTPEndpoint,
+		defaultUserRole:       defaultUserRole,
+	}
+	if defaultUserStatus >= 0 {
+		status := int(defaultUserStatus)
+		data.defaultUserStatus = &status
+	}
+	// Make the SFTPGo client and the provider settings available during
+	// DataSource and Resource type Configure methods.
+	resp.DataSourceData = data
+	resp.ResourceData = data
 
 	tflog.Info(ctx, "Configured SFTPGo client", map[string]any{"success": true})
 }
@@ -274,11 +675,23 @@ func (p *sftpgoProvider) DataSources(_ context.Context) []func() datasource.Data
 		NewFoldersDataSource,
 		NewGroupsDataSource,
 		NewAdminsDataSource,
+		NewAdminDataSource,
 		NewDefenderEntriesDataSource,
 		NewAllowListEntriesDataSource,
 		NewRlSafeListEntriesDataSource,
 		NewActionsDataSource,
 		NewRulesDataSource,
+		NewCapabilitiesDataSource,
+		NewFilesystemTemplateDataSource,
+		NewUserQuotaUsageDataSource,
+		NewFolderQuotaUsageDataSource,
+	}
+}
+
+// Functions defines the functions implemented in the provider.
+func (p *sftpgoProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewParseSizeFunction,
 	}
 }
 
@@ -287,7 +700,10 @@ func (p *sftpgoProvider) Resources(_ context.Context) []func() resource.Resource
 	return []func() resource.Resource{
 		NewUserResource,
 		NewRoleResource,
+		NewAdminAPIKeyResource,
 		NewFolderResource,
+		NewFolderQuotaResetResource,
+		NewFolderQuotaScanResource,
 		NewGroupResource,
 		NewAdminResource,
 		NewDefenderEntryResource,
@@ -298,6 +714,20 @@ func (p *sftpgoProvider) Resources(_ context.Context) []func() resource.Resource
 	}
 }
 
+// getUserAgent returns the User-Agent for the SFTPGo API requests, it
+// identifies Terraform and the provider version so the changes made using
+// Terraform can be distinguished in the SFTPGo logs.
+func getUserAgent(terraformVersion, suffix string) string {
+	userAgent := fmt.Sprintf("%s/%s", client.DefaultUserAgent, getVersion())
+	if terraformVersion != "" {
+		userAgent = fmt.Sprintf("Terraform/%s (+https://www.terraform.io) %s", terraformVersion, userAgent)
+	}
+	if suffix = strings.TrimSpace(suffix); suffix != "" {
+		userAgent += " " + suffix
+	}
+	return userAgent
+}
+
 func getHeadersFromEnv() []client.KeyValue {
 	var headers []client.KeyValue
 
@@ -313,3 +743,58 @@ func getHeadersFromEnv() []client.KeyValue {
 	}
 	return headers
 }
+
+// getInt64FromEnv returns the integer value of the specified environment
+// variable or defaultValue if it is not set. An attribute error is added
+// if the value is not a valid integer or it is outside the minValue,
+// maxValue range. The range must match the attribute validators.
+func getInt64FromEnv(name string, attrPath path.Path, defaultValue, minValue, maxValue int64, diags *diag.Diagnostics) int64 {
+	val := strings.TrimSpace(os.Getenv(name))
+	if val == "" {
+		return defaultValue
+	}
+	result, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || result < minValue || result > maxValue {
+		detail := fmt.Sprintf("The %s environment variable must be an integer greater than or equal to %d, got: %q",
+			name, minValue, val)
+		if maxValue < math.MaxInt64 {
+			detail = fmt.Sprintf("The %s environment variable must be an integer between %d and %d, got: %q",
+				name, minValue, maxValue, val)
+		}
+		diags.AddAttributeError(attrPath, "Invalid "+name+" Environment Variable", detail)
+		return defaultValue
+	}
+	return result
+}
+
+// getBoolFromEnv returns the boolean value of the specified environment
+// variable or defaultValue if it is not set. An attribute error is added if the
+// value is not a valid boolean.
+func getBoolFromEnv(name string, attrPath path.Path, defaultValue bool, diags *diag.Diagnostics) bool {
+	val := strings.TrimSpace(os.Getenv(name))
+	if val == "" {
+		return defaultValue
+	}
+	result, err := strconv.ParseBool(val)
+	if err != nil {
+		diags.AddAttributeError(
+			attrPath,
+			"Invalid "+name+" Environment Variable",
+			fmt.Sprintf("The %s environment variable must be a boolean, got: %q", name, val),
+		)
+		return defaultValue
+	}
+	return result
+}
+
+// getListFromEnv returns the non empty values of the specified comma
+// separated environment variable.
+func getListFromEnv(name string) []string {
+	var result []string
+	for _, val := range strings.Split(os.Getenv(name), ",") {
+		if val = strings.TrimSpace(val); val != "" {
+			result = append(result, val)
+		}
+	}
+	return result
+}
@@ -1,10 +1,20 @@
 package sftpgo
 
 import (
+	"fmt"
+	"math"
 	"os"
+	"strings"
+	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
 
 	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
@@ -24,6 +34,46 @@ var (
 	}
 )
 
+// testAccFsSecretAttributes are the filesystem secret attributes relative to
+// the filesystem block.
+var testAccFsSecretAttributes = []string{"plain_text_secrets", "s3config.access_secret", "s3config.sse_customer_key",
+	"gcsconfig.credentials", "azblobconfig.account_key", "azblobconfig.sas_url", "cryptconfig.passphrase",
+	"sftpconfig.password", "sftpconfig.private_key", "sftpconfig.key_passphrase", "httpconfig.password",
+	"httpconfig.api_key"}
+
+// testAccImportStateVerifyIgnore returns the secret attributes of the
+// specified resource type to ignore when verifying an import. SFTPGo returns
+// secrets encrypted or does not return them at all, so the imported values
+// never match the configured ones. The other attributes must match.
+func testAccImportStateVerifyIgnore(resourceType string) []string {
+	getFsSecrets := func(prefix string) []string {
+		result := make([]string, 0, len(testAccFsSecretAttributes))
+		for _, name := range testAccFsSecretAttributes {
+			result = append(result, prefix+name)
+		}
+		return result
+	}
+
+	switch resourceType {
+	case "sftpgo_user":
+		return append([]string{"password", "timeouts"}, getFsSecrets("filesystem.")...)
+	case "sftpgo_folder":
+		return getFsSecrets("filesystem.")
+	case "sftpgo_group":
+		return getFsSecrets("user_settings.filesystem.")
+	case "sftpgo_admin":
+		return []string{"password"}
+	case "sftpgo_action":
+		return []string{"options.http_config.password", "timeouts"}
+	case "sftpgo_rule":
+		return []string{"timeouts"}
+	case "sftpgo_admin_api_key":
+		return []string{"key"}
+	default:
+		return nil
+	}
+}
+
 func getClient() (*client.Client, error) {
 	host := os.Getenv("SFTPGO_HOST")
 	user := os.Getenv("SFTPGO_USERNAME")
@@ -32,3 +82,127 @@ func getClient() (*client.Client, error) {
 
 	return client.NewClient(&host, &user, &pwd, nil, headers)
 }
+
+// testAccDeletedOutsideTerraform creates the resource defined in config,
+// deletes it using the API and checks that it is planned for creation again.
+func testAccDeletedOutsideTerraform(t *testing.T, resourceName, config string, deleteFn func(c *client.Client) error) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					require.NoError(t, deleteFn(c))
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+// testAccImportFsSecrets creates the resource defined in config and imports
+// it. The imported filesystem secrets, relative to fsPath, must be in the
+// SFTPGo secret format, all the other attributes must match. Applying config
+// after the import must send the configured secrets and keep them.
+func testAccImportFsSecrets(t *testing.T, resourceName, config, fsPath string, secrets ...string) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	resourceType, _, _ := strings.Cut(resourceName, ".")
+	checkImportedSecrets := func(states []*terraform.InstanceState) error {
+		if len(states) != 1 {
+			return fmt.Errorf("unexpected imported states: %d", len(states))
+		}
+		for _, name := range secrets {
+			if val := states[0].Attributes[fsPath+name]; !isSFTPGoSecretFormat(val) {
+				return fmt.Errorf("unexpected imported secret %q: %q", fsPath+name, val)
+			}
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: testAccImportStateVerifyIgnore(resourceType),
+				ImportStateCheck:        checkImportedSecrets,
+			},
+			{
+				ResourceName:       resourceName,
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+			// the imported secrets are encrypted, the configured ones are sent
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestGetInt64FromEnv(t *testing.T) {
+	name := "SFTPGO_TEST_INT64"
+	attrPath := path.Root("retry_max")
+	testCases := []struct {
+		value    string
+		expected int64
+		err      string
+	}{
+		{"", 3, ""},
+		{"0", 0, ""},
+		{" 10 ", 10, ""},
+		{"11", 3, "between 0 and 10"},
+		{"-1", 3, "between 0 and 10"},
+		{"a", 3, "between 0 and 10"},
+	}
+	for _, tc := range testCases {
+		t.Setenv(name, tc.value)
+		var diags diag.Diagnostics
+		require.Equal(t, tc.expected, getInt64FromEnv(name, attrPath, 3, 0, 10, &diags), tc.value)
+		if tc.err == "" {
+			require.False(t, diags.HasError(), tc.value)
+		} else {
+			require.True(t, diags.HasError(), tc.value)
+			require.Contains(t, diags.Errors()[0].Detail(), tc.err)
+		}
+	}
+
+	t.Setenv(name, "0")
+	var diags diag.Diagnostics
+	require.Equal(t, int64(30), getInt64FromEnv(name, attrPath, 30, 1, math.MaxInt64, &diags))
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), "greater than or equal to 1")
+}
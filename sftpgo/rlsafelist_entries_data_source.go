@@ -95,18 +95,18 @@ func (d *rlSafeListEntriesDataSource) Configure(_ context.Context, req datasourc
 		return
 	}
 
-	d.client = req.ProviderData.(*client.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *rlSafeListEntriesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state rlSafeListEntriesDataSourceModel
 
-	entries, err := d.client.GetIPListEntries(3)
+	entries, err := d.client.GetIPListEntries(ctx, 3)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read SFTPGo rate limiters safe list entries",
-			err.Error(),
+			parseAPIError(err),
 		)
 		return
 	}
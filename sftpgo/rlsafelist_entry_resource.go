@@ -50,7 +50,7 @@ func (r *rlSafeListEntryResource) Configure(_ context.Context, req resource.Conf
 		return
 	}
 
-	r.client = req.ProviderData.(*client.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -61,7 +61,7 @@ func (r *rlSafeListEntryResource) Metadata(_ context.Context, req resource.Metad
 // Schema defines the schema for the resource.
 func (r *rlSafeListEntryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Rate limiters safe list entry",
+		Description: "Rate limiters safe list entry. It can be imported using the IP or network, optionally prefixed by the list type (3), for example \"3/192.168.1.0/24\".",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:    true,
@@ -113,11 +113,16 @@ func (r *rlSafeListEntryResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
-	entry, err := r.client.CreateIPListEntry(*entry)
+	entry, adopted, err := createOrAdopt(ctx, r.client,
+		func() (*client.IPListEntry, error) { return r.client.CreateIPListEntry(ctx, *entry) },
+		func() (*client.IPListEntry, error) {
+			return r.client.GetIPListEntry(ctx, entry.Type, plan.IPOrNet.ValueString())
+		},
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating rate limiters safe list entry",
-			"Could not create rate limiters safe list entry, unexpected error: "+err.Error(),
+			"Could not create rate limiters safe list entry: "+parseAPIError(err),
 		)
 		return
 	}
@@ -127,6 +132,14 @@ func (r *rlSafeListEntryResource) Create(ctx context.Context, req resource.Creat
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(plan.Description, state.Description)
+
+	if adopted {
+		resp.Diagnostics.Append(checkAdoptedState(ctx, req.Plan, state, "rate limiters safe list entry", plan.IPOrNet.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
@@ -146,20 +159,26 @@ func (r *rlSafeListEntryResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
-	entry, err := r.client.GetIPListEntry(3, state.IPOrNet.ValueString())
+	entry, err := r.client.GetIPListEntry(ctx, 3, state.IPOrNet.ValueString())
 	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo rate limiters safe list entry",
-			"Could not read SFTPGo rate limiters safe list entry "+state.IPOrNet.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo rate limiters safe list entry "+state.IPOrNet.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
+	description := state.Description
 	diags = state.fromSFTPGo(ctx, entry)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(description, state.Description)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -184,20 +203,20 @@ func (r *rlSafeListEntryResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
-	err := r.client.UpdateIPListEntry(*entry)
+	err := r.client.UpdateIPListEntry(ctx, *entry)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating rate limiters safe list entry",
-			"Could not update rate limiters safe list entry, unexpected error: "+err.Error(),
+			"Could not update rate limiters safe list entry: "+parseAPIError(err),
 		)
 		return
 	}
 
-	entry, err = r.client.GetIPListEntry(3, plan.IPOrNet.ValueString())
+	entry, err = r.client.GetIPListEntry(ctx, 3, plan.IPOrNet.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo rate limiters safe list entry",
-			"Could not read SFTPGo rate limiters safe list entry "+plan.IPOrNet.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo rate limiters safe list entry "+plan.IPOrNet.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
@@ -208,6 +227,7 @@ func (r *rlSafeListEntryResource) Update(ctx context.Context, req resource.Updat
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(plan.Description, state.Description)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -227,18 +247,23 @@ func (r *rlSafeListEntryResource) Delete(ctx context.Context, req resource.Delet
 	}
 
 	// Delete existing entry
-	err := r.client.DeleteIPListEntry(3, state.IPOrNet.ValueString())
+	err := r.client.DeleteIPListEntry(ctx, 3, state.IPOrNet.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting SFTPGo rate limiters safe list entry",
-			"Could not delete rate limiters safe list entry, unexpected error: "+err.Error(),
+			"Could not delete rate limiters safe list entry: "+parseAPIError(err),
 		)
 		return
 	}
 }
 
 // ImportState imports an existing the resource and save the Terraform state
-func (*rlSafeListEntryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ipornet and save to ipornet attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("ipornet"), req, resp)
+func (r *rlSafeListEntryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The import ID is the ipornet, optionally prefixed by the list type
+	ipOrNet, diags := getIPListEntryImportID(ctx, r.client, req.ID, 3, "rate limiters safe list")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ipornet"), ipOrNet)...)
 }
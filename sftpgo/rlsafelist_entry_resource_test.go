@@ -15,9 +15,13 @@
 package sftpgo
 
 import (
+	"context"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 func TestAccRateLimitersListResource(t *testing.T) {
@@ -46,6 +50,18 @@ func TestAccRateLimitersListResource(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      "sftpgo_rlsafelist_entry.test",
+				ImportState:       true,
+				ImportStateId:     "3/172.16.3.0/24",
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  "sftpgo_rlsafelist_entry.test",
+				ImportState:   true,
+				ImportStateId: "1/172.16.3.0/24",
+				ExpectError:   regexp.MustCompile("Invalid Import Identifier"),
+			},
 			// Update and Read testing
 			{
 				Config: `
@@ -67,3 +83,13 @@ func TestAccRateLimitersListResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccRateLimitersListResourceDeletedOutsideTerraform(t *testing.T) {
+	testAccDeletedOutsideTerraform(t, "sftpgo_rlsafelist_entry.test", `
+		resource "sftpgo_rlsafelist_entry" "test" {
+		  ipornet = "172.16.6.0/24"
+		  protocols = 0
+		}`, func(c *client.Client) error {
+		return c.DeleteIPListEntry(context.Background(), 3, "172.16.6.0/24")
+	})
+}
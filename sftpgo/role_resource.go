@@ -16,6 +16,7 @@ package sftpgo
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -23,6 +24,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
@@ -50,7 +52,7 @@ func (r *roleResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	r.client = req.ProviderData.(*client.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -89,6 +91,16 @@ func (r *roleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Computed:    true,
 				Description: "Last update time as unix timestamp in milliseconds.",
 			},
+			"admins": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Admins with this role. A role cannot be deleted while it is in use.",
+			},
+			"users": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Users with this role. A role cannot be deleted while it is in use.",
+			},
 		},
 	}
 }
@@ -109,11 +121,14 @@ func (r *roleResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	role, err := r.client.CreateRole(*role)
+	role, adopted, err := createOrAdopt(ctx, r.client,
+		func() (*client.Role, error) { return r.client.CreateRole(ctx, *role) },
+		func() (*client.Role, error) { return r.client.GetRole(ctx, plan.Name.ValueString()) },
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating role",
-			"Could not create role, unexpected error: "+err.Error(),
+			"Could not create role: "+parseAPIError(err),
 		)
 		return
 	}
@@ -123,6 +138,14 @@ func (r *roleResource) Create(ctx context.Context, req resource.CreateRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(plan.Description, state.Description)
+
+	if adopted {
+		resp.Diagnostics.Append(checkAdoptedState(ctx, req.Plan, state, "role", plan.Name.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
@@ -142,20 +165,26 @@ func (r *roleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	role, err := r.client.GetRole(state.Name.ValueString())
+	role, err := r.client.GetRole(ctx, state.Name.ValueString())
 	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Role",
-			"Could not read SFTPGo Role "+state.Name.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Role "+state.Name.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
+	description := state.Description
 	diags = state.fromSFTPGo(ctx, role)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(description, state.Description)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -180,20 +209,20 @@ func (r *roleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	err := r.client.UpdateRole(*role)
+	err := r.client.UpdateRole(ctx, *role)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating role",
-			"Could not update role, unexpected error: "+err.Error(),
+			"Could not update role: "+parseAPIError(err),
 		)
 		return
 	}
 
-	role, err = r.client.GetRole(plan.Name.ValueString())
+	role, err = r.client.GetRole(ctx, plan.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Role",
-			"Could not read SFTPGo Role "+plan.Name.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Role "+plan.Name.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
@@ -204,6 +233,7 @@ func (r *roleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.Description = preserveEmptyString(plan.Description, state.Description)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -222,12 +252,34 @@ func (r *roleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
+	// refuse to delete a role that is still in use, the admins and users
+	// would silently lose their role
+	role, err := r.client.GetRole(ctx, state.Name.ValueString())
+	if err != nil {
+		if client.IsNotFound(err) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading SFTPGo Role",
+			"Could not read SFTPGo Role "+state.Name.ValueString()+": "+parseAPIError(err),
+		)
+		return
+	}
+	if len(role.Admins) > 0 || len(role.Users) > 0 {
+		resp.Diagnostics.AddError(
+			"Role In Use",
+			fmt.Sprintf("Could not delete role %q, it is assigned to admins %v and users %v. Remove the role from "+
+				"these accounts before deleting it.", role.Name, role.Admins, role.Users),
+		)
+		return
+	}
+
 	// Delete existing role
-	err := r.client.DeleteRole(state.Name.ValueString())
+	err = r.client.DeleteRole(ctx, state.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting SFTPGo role",
-			"Could not delete role, unexpected error: "+err.Error(),
+			"Could not delete role: "+parseAPIError(err),
 		)
 		return
 	}
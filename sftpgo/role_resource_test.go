@@ -15,9 +15,23 @@
 package sftpgo
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
 	"testing"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/sftpgo/sdk"
+	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 func TestAccRoleResource(t *testing.T) {
@@ -36,6 +50,8 @@ func TestAccRoleResource(t *testing.T) {
 					resource.TestCheckNoResourceAttr("sftpgo_role.test", "description"),
 					resource.TestCheckResourceAttrSet("sftpgo_role.test", "created_at"),
 					resource.TestCheckResourceAttrSet("sftpgo_role.test", "updated_at"),
+					resource.TestCheckResourceAttr("sftpgo_role.test", "admins.#", "0"),
+					resource.TestCheckResourceAttr("sftpgo_role.test", "users.#", "0"),
 				),
 			},
 			// ImportState testing
@@ -63,3 +79,151 @@ func TestAccRoleResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccRoleResourceInUse(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	config := `
+		resource "sftpgo_role" "test" {
+		  name = "test role in use"
+		}`
+	user := client.User{
+		User: sdk.User{
+			BaseUser: sdk.BaseUser{
+				Username: "test user role",
+				HomeDir:  "/tmp/testuserrole",
+				Status:   1,
+				Role:     "test role in use",
+				Permissions: map[string][]string{
+					"/": {"*"},
+				},
+			},
+		},
+		Password: "Cheiha0ahy7Ieghatiet4phei",
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_role.test", "users.#", "0"),
+				),
+			},
+			{
+				PreConfig: func() {
+					_, err := c.CreateUser(context.Background(), user)
+					require.NoError(t, err)
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_role.test", "admins.#", "0"),
+					resource.TestCheckResourceAttr("sftpgo_role.test", "users.#", "1"),
+					resource.TestCheckResourceAttr("sftpgo_role.test", "users.0", user.Username),
+				),
+			},
+			// the role is in use and cannot be deleted
+			{
+				Config:      config,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("Role In Use"),
+			},
+			{
+				PreConfig: func() {
+					err := c.DeleteUser(context.Background(), user.Username)
+					require.NoError(t, err)
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_role.test", "users.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRoleResourceDeletedOutsideTerraform(t *testing.T) {
+	testAccDeletedOutsideTerraform(t, "sftpgo_role.test", `
+		resource "sftpgo_role" "test" {
+		  name = "test role deleted"
+		}`, func(c *client.Client) error {
+		return c.DeleteRole(context.Background(), "test role deleted")
+	})
+}
+
+func TestRoleResourceDeleteNotFound(t *testing.T) {
+	var methods []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	apiKey := "key"
+	c, err := client.NewClient(&ts.URL, nil, nil, &apiKey, nil)
+	require.NoError(t, err)
+	r := &roleResource{client: c}
+	schemaResp := fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	req := fwresource.DeleteRequest{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: getTestObject(objType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "test role"),
+		})},
+	}
+	resp := &fwresource.DeleteResponse{State: req.State}
+	r.Delete(ctx, req, resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	// the role is already gone, nothing to delete
+	require.Equal(t, []string{http.MethodGet}, methods)
+}
+
+func TestAccRoleResourceEmptyDescription(t *testing.T) {
+	getConfig := func(description string) string {
+		return fmt.Sprintf(`
+			resource "sftpgo_role" "test" {
+			  name = "test role empty description"
+			  description = %q
+			}`, description)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_role.test", "description", ""),
+				),
+			},
+			{
+				Config: getConfig(""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: getConfig("desc"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_role.test", "description", "desc"),
+				),
+			},
+			{
+				Config: getConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_role.test", "description", ""),
+				),
+			},
+		},
+	})
+}
@@ -16,6 +16,7 @@ package sftpgo
 
 import (
 	"context"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -56,7 +57,7 @@ func (d *rolesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 			},
 			"roles": schema.ListNestedAttribute{
 				Computed:    true,
-				Description: "List of roles.",
+				Description: "List of roles sorted by name.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"id": schema.StringAttribute{
@@ -78,6 +79,16 @@ func (d *rolesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 							Computed:    true,
 							Description: "Last update time as unix timestamp in milliseconds.",
 						},
+						"admins": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "Admins with this role.",
+						},
+						"users": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "Users with this role.",
+						},
 					},
 				},
 			},
@@ -91,22 +102,26 @@ func (d *rolesDataSource) Configure(_ context.Context, req datasource.ConfigureR
 		return
 	}
 
-	d.client = req.ProviderData.(*client.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *rolesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state rolesDataSourceModel
 
-	roles, err := d.client.GetRoles()
+	roles, err := d.client.GetRoles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read SFTPGo Roles",
-			err.Error(),
+			parseAPIError(err),
 		)
 		return
 	}
 
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+
 	// Map response body to model
 	for _, role := range roles {
 		var roleState roleResourceModel
@@ -15,6 +15,7 @@
 package sftpgo
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -37,11 +38,11 @@ func TestAccRolesDataSource(t *testing.T) {
 	}
 	c, err := getClient()
 	require.NoError(t, err)
-	_, err = c.CreateRole(testRole)
+	_, err = c.CreateRole(context.Background(), testRole)
 	require.NoError(t, err)
 
 	defer func() {
-		err = c.DeleteRole(testRole.Name)
+		err = c.DeleteRole(context.Background(), testRole.Name)
 		require.NoError(t, err)
 	}()
 	resource.Test(t, resource.TestCase{
@@ -58,10 +59,43 @@ func TestAccRolesDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "roles.0.id", testRole.Name),
 					resource.TestCheckResourceAttrSet("data.sftpgo_roles.test", "roles.0.created_at"),
 					resource.TestCheckResourceAttrSet("data.sftpgo_roles.test", "roles.0.updated_at"),
+					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "roles.0.admins.#", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "roles.0.users.#", "0"),
 					// Verify placeholder id attribute
 					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "id", placeholderID),
 				),
 			},
+			// roles are sorted by name and list the users referencing them
+			{
+				Config: `
+					resource "sftpgo_role" "test" {
+					  name = "a test role"
+					}
+
+					resource "sftpgo_user" "test" {
+					  username = "test role user"
+					  password = "pwd"
+					  status = 1
+					  home_dir = "/tmp/testroleuser"
+					  permissions = {
+					    "/" = "*"
+					  }
+					  role = sftpgo_role.test.name
+					}
+
+					data "sftpgo_roles" "test" {
+					  depends_on = [sftpgo_user.test]
+					}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "roles.#", "2"),
+					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "roles.0.name", "a test role"),
+					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "roles.0.users.#", "1"),
+					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "roles.0.users.0", "test role user"),
+					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "roles.0.admins.#", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "roles.1.name", testRole.Name),
+					resource.TestCheckResourceAttr("data.sftpgo_roles.test", "roles.1.users.#", "0"),
+				),
+			},
 		},
 	})
 }
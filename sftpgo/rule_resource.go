@@ -16,11 +16,15 @@ package sftpgo
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -28,16 +32,20 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 
 	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &ruleResource{}
-	_ resource.ResourceWithConfigure   = &ruleResource{}
-	_ resource.ResourceWithImportState = &ruleResource{}
+	_ resource.Resource                   = &ruleResource{}
+	_ resource.ResourceWithConfigure      = &ruleResource{}
+	_ resource.ResourceWithImportState    = &ruleResource{}
+	_ resource.ResourceWithValidateConfig = &ruleResource{}
+	_ resource.ResourceWithModifyPlan     = &ruleResource{}
 )
 
 // NewRuleResource is a helper function to simplify the provider implementation.
@@ -56,7 +64,7 @@ func (r *ruleResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	r.client = req.ProviderData.(*client.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -65,7 +73,7 @@ func (r *ruleResource) Metadata(_ context.Context, req resource.MetadataRequest,
 }
 
 // Schema defines the schema for the resource.
-func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *ruleResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Event rule",
 		Attributes: map[string]schema.Attribute{
@@ -109,6 +117,11 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Computed:    true,
 				Description: "Last update time as unix timestamp in milliseconds.",
 			},
+			"next_run_preview": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "Next run times, in UTC and RFC 3339 format, for scheduled rules (trigger 3). It is a snapshot computed when the state is refreshed and, at plan time, when the schedules change.",
+			},
 			"conditions": schema.SingleNestedAttribute{
 				Optional:    true,
 				Computed:    true,
@@ -141,22 +154,34 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 							Attributes: map[string]schema.Attribute{
 								"hour": schema.StringAttribute{
 									Required: true,
+									Validators: []validator.String{
+										cronHourField,
+									},
 								},
 								"day_of_week": schema.StringAttribute{
 									Required: true,
+									Validators: []validator.String{
+										cronDayOfWeekField,
+									},
 								},
 								"day_of_month": schema.StringAttribute{
 									Required: true,
+									Validators: []validator.String{
+										cronDayOfMonthField,
+									},
 								},
 								"month": schema.StringAttribute{
 									Required: true,
+									Validators: []validator.String{
+										cronMonthField,
+									},
 								},
 							},
 						},
 					},
 					"idp_login_event": schema.Int64Attribute{
 						Optional:    true,
-						Description: `Identity Provider login event that trigger the rule. 0 any, 1 user, 2 admin.`,
+						Description: `Identity Provider login event that trigger the rule. 0 any, 1 user, 2 admin. Required for Identity Provider login rules (trigger 7) and not allowed for other triggers.`,
 						Validators: []validator.Int64{
 							int64validator.Between(0, 2),
 						},
@@ -267,7 +292,7 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 							},
 							"concurrent_execution": schema.BoolAttribute{
 								Optional:    true,
-								Description: `If enabled, allow to execute scheduled tasks concurrently from multiple SFTPGo instances.`,
+								Description: `If enabled, allow to execute scheduled tasks concurrently from multiple SFTPGo instances. Only supported for scheduled rules.`,
 							},
 						},
 					},
@@ -295,18 +320,63 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig validates the resource configuration.
+func (r *ruleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var trigger types.Int64
+	diags := req.Config.GetAttribute(ctx, path.Root("trigger"), &trigger)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if trigger.IsNull() || trigger.IsUnknown() {
+		return
+	}
+
+	concurrentExecutionPath := path.Root("conditions").AtName("options").AtName("concurrent_execution")
+	var concurrentExecution types.Bool
+	diags = req.Config.GetAttribute(ctx, concurrentExecutionPath, &concurrentExecution)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if concurrentExecution.ValueBool() && trigger.ValueInt64() != 3 {
+		resp.Diagnostics.AddAttributeWarning(
+			concurrentExecutionPath,
+			"Concurrent execution ignored",
+			"Concurrent execution is only supported for scheduled rules (trigger 3), it will be ignored for this rule.",
+		)
 	}
+
+	resp.Diagnostics.Append(validateRuleTriggerConditions(ctx, req.Config, trigger.ValueInt64())...)
 }
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *ruleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Retrieve values from plan
-	var plan eventRuleResourceModel
+	var plan eventRuleResourceWithTimeoutsModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
 
 	rule, diags := plan.toSFTPGo(ctx)
 	resp.Diagnostics.Append(diags...)
@@ -314,20 +384,42 @@ func (r *ruleResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	rule, err := r.client.CreateRule(*rule)
+	rule, adopted, err := createOrAdopt(ctx, r.client,
+		func() (*client.EventRule, error) { return r.client.CreateRule(ctx, *rule) },
+		func() (*client.EventRule, error) { return r.client.GetRule(ctx, plan.Name.ValueString()) },
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating rule",
-			"Could not create rule, unexpected error: "+err.Error(),
+			"Could not create rule: "+parseAPIError(err),
 		)
 		return
 	}
-	var state eventRuleResourceModel
+	var state eventRuleResourceWithTimeoutsModel
 	diags = state.fromSFTPGo(ctx, rule)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	diags = r.preservePlanFields(ctx, &plan.eventRuleResourceModel, &state.eventRuleResourceModel)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// the preview depends on the current time, keep the planned one. Read
+	// computes it again
+	if !plan.NextRunPreview.IsNull() && !plan.NextRunPreview.IsUnknown() {
+		state.NextRunPreview = plan.NextRunPreview
+	}
+
+	state.Timeouts = plan.Timeouts
+
+	if adopted {
+		resp.Diagnostics.Append(checkAdoptedState(ctx, req.Plan, state, "rule", plan.Name.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
@@ -340,30 +432,49 @@ func (r *ruleResource) Create(ctx context.Context, req resource.CreateRequest, r
 // Read refreshes the Terraform state with the latest data.
 func (r *ruleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	// Get current state
-	var state eventRuleResourceModel
+	var state eventRuleResourceWithTimeoutsModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
 
-	rule, err := r.client.GetRule(state.Name.ValueString())
+	rule, err := r.client.GetRule(ctx, state.Name.ValueString())
 	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Event Rule",
-			"Could not read SFTPGo Event Rule "+state.Name.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Event Rule "+state.Name.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
-	diags = state.fromSFTPGo(ctx, rule)
+	var newState eventRuleResourceWithTimeoutsModel
+	diags = newState.fromSFTPGo(ctx, rule)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	diags = r.preservePlanFields(ctx, &state.eventRuleResourceModel, &newState.eventRuleResourceModel)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	newState.Timeouts = state.Timeouts
+
 	// Set refreshed state
-	diags = resp.State.Set(ctx, &state)
+	diags = resp.State.Set(ctx, &newState)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -373,42 +484,62 @@ func (r *ruleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *ruleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	// Retrieve values from plan
-	var plan eventRuleResourceModel
+	var plan eventRuleResourceWithTimeoutsModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	rule, diags := plan.toSFTPGo(ctx)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err := r.client.UpdateRule(*rule)
+	err := r.client.UpdateRule(ctx, *rule)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating event rule",
-			"Could not update event rule, unexpected error: "+err.Error(),
+			"Could not update event rule: "+parseAPIError(err),
 		)
 		return
 	}
 
-	rule, err = r.client.GetRule(plan.Name.ValueString())
+	rule, err = r.client.GetRule(ctx, plan.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo Event Rule",
-			"Could not read SFTPGo Event Rule "+plan.Name.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo Event Rule "+plan.Name.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
-	var state eventRuleResourceModel
+	var state eventRuleResourceWithTimeoutsModel
 	diags = state.fromSFTPGo(ctx, rule)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	diags = r.preservePlanFields(ctx, &plan.eventRuleResourceModel, &state.eventRuleResourceModel)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// the preview depends on the current time, keep the planned one. Read
+	// computes it again
+	if !plan.NextRunPreview.IsNull() && !plan.NextRunPreview.IsUnknown() {
+		state.NextRunPreview = plan.NextRunPreview
+	}
+
+	state.Timeouts = plan.Timeouts
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -420,19 +551,26 @@ func (r *ruleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *ruleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state eventRuleResourceModel
+	var state eventRuleResourceWithTimeoutsModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
 
 	// Delete existing event rule
-	err := r.client.DeleteRule(state.Name.ValueString())
+	err := r.client.DeleteRule(ctx, state.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting SFTPGo event rule",
-			"Could not delete event rule, unexpected error: "+err.Error(),
+			"Could not delete event rule: "+parseAPIError(err),
 		)
 		return
 	}
@@ -443,3 +581,179 @@ func (*ruleResource) ImportState(ctx context.Context, req resource.ImportStateRe
 	// Retrieve import name and save to name attribute
 	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 }
+
+func (*ruleResource) preservePlanFields(ctx context.Context, plan, state *eventRuleResourceModel) diag.Diagnostics {
+	state.Description = preserveEmptyString(plan.Description, state.Description)
+	if plan.Conditions.IsNull() || plan.Conditions.IsUnknown() {
+		return nil
+	}
+
+	var conditionsPlan ruleConditions
+	diags := plan.Conditions.As(ctx, &conditionsPlan, basetypes.ObjectAsOptions{
+		UnhandledNullAsEmpty:    true,
+		UnhandledUnknownAsEmpty: true,
+	})
+	if diags.HasError() {
+		return diags
+	}
+	if conditionsPlan.Options == nil {
+		return nil
+	}
+
+	var conditionsState ruleConditions
+	diags = state.Conditions.As(ctx, &conditionsState, basetypes.ObjectAsOptions{
+		UnhandledNullAsEmpty:    true,
+		UnhandledUnknownAsEmpty: true,
+	})
+	if diags.HasError() {
+		return diags
+	}
+	if conditionsState.Options != nil {
+		conditionsState.Options.preservePlanFields(conditionsPlan.Options)
+	}
+
+	conditions, diags := types.ObjectValueFrom(ctx, conditionsState.getTFAttributes(), conditionsState)
+	if diags.HasError() {
+		return diags
+	}
+	state.Conditions = conditions
+
+	return nil
+}
+
+// ModifyPlan plans empty condition options if they are not configured and
+// computes the next run preview for scheduled rules. The preview from the
+// state, computed when the state was last refreshed, is kept unless the
+// trigger or the schedules change, so time passing does not cause a diff.
+func (r *ruleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		// the resource is being destroyed
+		return
+	}
+
+	var trigger types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("trigger"), &trigger)...)
+	var conditions types.Object
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("conditions"), &conditions)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setRuleConditionOptionsPlan(ctx, req.Config, &resp.Plan, conditions)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var stateTrigger types.Int64
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("trigger"), &stateTrigger)...)
+		var stateSchedules, planSchedules types.List
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("conditions").AtName("schedules"), &stateSchedules)...)
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("conditions").AtName("schedules"), &planSchedules)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if trigger.Equal(stateTrigger) && planSchedules.Equal(stateSchedules) {
+			var preview types.List
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("next_run_preview"), &preview)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("next_run_preview"), preview)...)
+			return
+		}
+	}
+
+	preview, diags := getRuleNextRunPreview(ctx, trigger, conditions, time.Now())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("next_run_preview"), preview)...)
+}
+
+// setRuleConditionOptionsPlan plans empty condition options if they are not
+// configured, this is what SFTPGo returns. Otherwise the computed options
+// would be unknown, and displayed as changed, on every update.
+func setRuleConditionOptionsPlan(ctx context.Context, config tfsdk.Config, plan *tfsdk.Plan, conditions types.Object,
+) diag.Diagnostics {
+	if conditions.IsNull() || conditions.IsUnknown() {
+		return nil
+	}
+	optionsPath := path.Root("conditions").AtName("options")
+	var options types.Object
+	diags := config.GetAttribute(ctx, optionsPath, &options)
+	if diags.HasError() || !options.IsNull() {
+		return diags
+	}
+	c := ruleConditions{}
+	emptyOptions, d := types.ObjectValueFrom(ctx, c.getTFAttributes()["options"].(types.ObjectType).AttrTypes,
+		ruleConditionOptions{
+			Protocols:       types.ListNull(types.StringType),
+			ProviderObjects: types.ListNull(types.StringType),
+			EventStatuses:   types.ListNull(types.Int32Type),
+		})
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	diags.Append(plan.SetAttribute(ctx, optionsPath, emptyOptions)...)
+	return diags
+}
+
+// ruleTriggerConditions maps the trigger specific list conditions to the
+// only trigger they apply to.
+var ruleTriggerConditions = map[string]int64{
+	"fs_events":       1,
+	"provider_events": 2,
+	"schedules":       3,
+}
+
+// validateRuleTriggerConditions checks that the trigger specific conditions
+// are only set for the matching trigger, SFTPGo silently ignores them for
+// other triggers.
+func validateRuleTriggerConditions(ctx context.Context, config tfsdk.Config, trigger int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, name := range []string{"fs_events", "provider_events", "schedules"} {
+		attrPath := path.Root("conditions").AtName(name)
+		var value types.List
+		d := config.GetAttribute(ctx, attrPath, &value)
+		diags.Append(d...)
+		if d.HasError() || value.IsNull() || value.IsUnknown() || len(value.Elements()) == 0 {
+			continue
+		}
+		if expectedTrigger := ruleTriggerConditions[name]; trigger != expectedTrigger {
+			diags.AddAttributeError(
+				attrPath,
+				"Invalid Rule Conditions",
+				fmt.Sprintf("%q is only supported for rules with trigger %d, the rule trigger is %d.",
+					name, expectedTrigger, trigger),
+			)
+		}
+	}
+
+	idpLoginEventPath := path.Root("conditions").AtName("idp_login_event")
+	var idpLoginEvent types.Int64
+	d := config.GetAttribute(ctx, idpLoginEventPath, &idpLoginEvent)
+	diags.Append(d...)
+	if d.HasError() || idpLoginEvent.IsUnknown() {
+		return diags
+	}
+	if trigger == 7 && idpLoginEvent.IsNull() {
+		diags.AddAttributeError(
+			idpLoginEventPath,
+			"Invalid Rule Conditions",
+			"The Identity Provider login event is required for Identity Provider login rules (trigger 7).",
+		)
+	}
+	if trigger != 7 && !idpLoginEvent.IsNull() {
+		diags.AddAttributeError(
+			idpLoginEventPath,
+			"Invalid Rule Conditions",
+			fmt.Sprintf("\"idp_login_event\" is only supported for rules with trigger 7, the rule trigger is %d.", trigger),
+		)
+	}
+
+	return diags
+}
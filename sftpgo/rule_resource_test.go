@@ -15,10 +15,16 @@
 package sftpgo
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 	"github.com/stretchr/testify/require"
 
 	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
@@ -38,15 +44,15 @@ func TestAccRuleResource(t *testing.T) {
 		Name: "action2",
 		Type: 5,
 	}
-	_, err = c.CreateAction(action1)
+	_, err = c.CreateAction(context.Background(), action1)
 	require.NoError(t, err)
-	_, err = c.CreateAction(action2)
+	_, err = c.CreateAction(context.Background(), action2)
 	require.NoError(t, err)
 
 	defer func() {
-		err = c.DeleteAction(action1.Name)
+		err = c.DeleteAction(context.Background(), action1.Name)
 		require.NoError(t, err)
-		err = c.DeleteAction(action2.Name)
+		err = c.DeleteAction(context.Background(), action2.Name)
 		require.NoError(t, err)
 	}()
 
@@ -145,6 +151,7 @@ func TestAccRuleResource(t *testing.T) {
 					resource.TestCheckResourceAttr("sftpgo_rule.test", "actions.1.stop_on_failure", "true"),
 					resource.TestCheckResourceAttrSet("sftpgo_rule.test", "created_at"),
 					resource.TestCheckResourceAttrSet("sftpgo_rule.test", "updated_at"),
+					resource.TestCheckNoResourceAttr("sftpgo_rule.test", "next_run_preview"),
 				),
 			},
 			// ImportState testing
@@ -216,6 +223,7 @@ func TestAccRuleResource(t *testing.T) {
 					resource.TestCheckNoResourceAttr("sftpgo_rule.test", "actions.0.stop_on_failure"),
 					resource.TestCheckResourceAttrSet("sftpgo_rule.test", "created_at"),
 					resource.TestCheckResourceAttrSet("sftpgo_rule.test", "updated_at"),
+					resource.TestCheckResourceAttr("sftpgo_rule.test", "next_run_preview.#", "5"),
 				),
 			},
 			{
@@ -481,3 +489,298 @@ func TestAccRuleResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccRuleResourceMinimalOptions(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+	action := client.BaseEventAction{
+		Name: "action minimal",
+		Type: 4,
+	}
+	_, err = c.CreateAction(context.Background(), action)
+	require.NoError(t, err)
+
+	defer func() {
+		err = c.DeleteAction(context.Background(), action.Name)
+		require.NoError(t, err)
+	}()
+
+	getConfig := func(description, options string) string {
+		return fmt.Sprintf(`
+			resource "sftpgo_rule" "test" {
+			  name = "test minimal rule"
+			  status = 1
+			  description = %q
+			  trigger = 1
+			  conditions = {
+				fs_events = ["upload"]
+				%s
+			  }
+			  actions = [
+				{
+				  name = "action minimal"
+				}
+			  ]
+			}`, description, options)
+	}
+	emptyOptions := knownvalue.ObjectPartial(map[string]knownvalue.Check{
+		"min_size":             knownvalue.Null(),
+		"max_size":             knownvalue.Null(),
+		"concurrent_execution": knownvalue.Null(),
+		"fs_paths":             knownvalue.Null(),
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig("desc", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue("sftpgo_rule.test", tfjsonpath.New("conditions").AtMapKey("options"),
+							emptyOptions),
+					},
+				},
+			},
+			{
+				Config: getConfig("desc", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// updating another field must not show the options as changed
+			{
+				Config: getConfig("updated desc", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("sftpgo_rule.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("sftpgo_rule.test", tfjsonpath.New("conditions").AtMapKey("options"),
+							emptyOptions),
+					},
+				},
+			},
+			// explicit zero and false values are preserved
+			{
+				Config: getConfig("updated desc", `options = {
+				  min_size = 0
+				  max_size = 0
+				  concurrent_execution = false
+				  fs_paths = [
+					{
+					  pattern = "/*.txt"
+					  inverse_match = false
+					}
+				  ]
+				}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_rule.test", "conditions.options.min_size", "0"),
+					resource.TestCheckResourceAttr("sftpgo_rule.test", "conditions.options.max_size", "0"),
+					resource.TestCheckResourceAttr("sftpgo_rule.test", "conditions.options.concurrent_execution", "false"),
+					resource.TestCheckResourceAttr("sftpgo_rule.test", "conditions.options.fs_paths.0.inverse_match", "false"),
+				),
+			},
+			{
+				Config: getConfig("updated desc", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("sftpgo_rule.test", "conditions.options.min_size"),
+					resource.TestCheckNoResourceAttr("sftpgo_rule.test", "conditions.options.fs_paths"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRuleResourceActionsOrder(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+	actions := []client.BaseEventAction{
+		{
+			Name: "order action1",
+			Type: 4,
+		},
+		{
+			Name: "order action2",
+			Type: 5,
+		},
+		{
+			Name: "order action3",
+			Type: 7,
+		},
+	}
+	for _, action := range actions {
+		_, err = c.CreateAction(context.Background(), action)
+		require.NoError(t, err)
+	}
+
+	defer func() {
+		for _, action := range actions {
+			err = c.DeleteAction(context.Background(), action.Name)
+			require.NoError(t, err)
+		}
+	}()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "sftpgo_rule" "test" {
+					  name = "test order rule"
+					  status = 1
+					  trigger = 1
+					  conditions = {
+						fs_events = ["upload"]
+					  }
+					  actions = [
+						{
+						  name = "order action3"
+						},
+						{
+						  name = "order action1"
+						},
+						{
+						  name = "order action2"
+						}
+					  ]
+					}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_rule.test", "actions.#", "3"),
+					resource.TestCheckResourceAttr("sftpgo_rule.test", "actions.0.name", "order action3"),
+					resource.TestCheckResourceAttr("sftpgo_rule.test", "actions.1.name", "order action1"),
+					resource.TestCheckResourceAttr("sftpgo_rule.test", "actions.2.name", "order action2"),
+				),
+			},
+			{
+				ResourceName:      "sftpgo_rule.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccRuleResourceDeletedOutsideTerraform(t *testing.T) {
+	testAccDeletedOutsideTerraform(t, "sftpgo_rule.test", `
+		resource "sftpgo_action" "test" {
+		  name = "test action rule deleted"
+		  type = 4
+		}
+
+		resource "sftpgo_rule" "test" {
+		  name = "test rule deleted"
+		  status = 1
+		  trigger = 1
+		  conditions = {
+			fs_events = ["upload"]
+		  }
+		  actions = [
+			{
+			  name = sftpgo_action.test.name
+			}
+		  ]
+		}`, func(c *client.Client) error {
+		return c.DeleteRule(context.Background(), "test rule deleted")
+	})
+}
+
+func TestAccRuleResourceStatusToggle(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+	action := client.BaseEventAction{
+		Name: "status toggle action",
+		Type: 7,
+	}
+	_, err = c.CreateAction(context.Background(), action)
+	require.NoError(t, err)
+
+	defer func() {
+		err = c.DeleteAction(context.Background(), action.Name)
+		require.NoError(t, err)
+	}()
+
+	getConfig := func(status int) string {
+		return fmt.Sprintf(`
+			resource "sftpgo_rule" "test" {
+			  name = "test status toggle rule"
+			  status = %d
+			  trigger = 1
+			  conditions = {
+				fs_events = ["upload"]
+				options = {
+				  fs_paths = [
+					{
+					  pattern = "/incoming/*"
+					}
+				  ]
+				}
+			  }
+			  actions = [
+				{
+				  name = "status toggle action"
+				  execute_sync = true
+				}
+			  ]
+			}`, status)
+	}
+	getStep := func(status int) resource.TestStep {
+		return resource.TestStep{
+			Config: getConfig(status),
+			ConfigPlanChecks: resource.ConfigPlanChecks{
+				PreApply: []plancheck.PlanCheck{
+					plancheck.ExpectResourceAction("sftpgo_rule.test", plancheck.ResourceActionUpdate),
+				},
+			},
+			Check: resource.ComposeAggregateTestCheckFunc(
+				resource.TestCheckResourceAttr("sftpgo_rule.test", "status", fmt.Sprintf("%d", status)),
+				resource.TestCheckResourceAttr("sftpgo_rule.test", "conditions.fs_events.#", "1"),
+				resource.TestCheckResourceAttr("sftpgo_rule.test", "conditions.options.fs_paths.0.pattern",
+					"/incoming/*"),
+				resource.TestCheckResourceAttr("sftpgo_rule.test", "actions.0.name", "status toggle action"),
+				resource.TestCheckResourceAttr("sftpgo_rule.test", "actions.0.execute_sync", "true"),
+				func(_ *terraform.State) error {
+					rule, err := c.GetRule(context.Background(), "test status toggle rule")
+					if err != nil {
+						return err
+					}
+					if len(rule.Conditions.FsEvents) != 1 || len(rule.Conditions.Options.FsPaths) != 1 ||
+						len(rule.Actions) != 1 || !rule.Actions[0].Options.ExecuteSync {
+						return fmt.Errorf("unexpected rule after status update: %+v", rule)
+					}
+					return nil
+				},
+			),
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(1),
+				Check:  resource.TestCheckResourceAttr("sftpgo_rule.test", "status", "1"),
+			},
+			getStep(0),
+			getStep(1),
+			getStep(0),
+			{
+				Config: getConfig(0),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
@@ -16,6 +16,7 @@ package sftpgo
 
 import (
 	"context"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -56,7 +57,7 @@ func (d *rulesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 			},
 			"rules": schema.ListNestedAttribute{
 				Computed:    true,
-				Description: "List of event rules.",
+				Description: "List of event rules, sorted by name.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"id": schema.StringAttribute{
@@ -86,6 +87,11 @@ func (d *rulesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 							Computed:    true,
 							Description: "Last update time as unix timestamp in milliseconds.",
 						},
+						"next_run_preview": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "Next run times, in UTC and RFC 3339 format, for scheduled rules (trigger 3).",
+						},
 						"conditions": schema.SingleNestedAttribute{
 							Computed:    true,
 							Description: "Defines the conditions that trigger the rule.",
@@ -249,22 +255,26 @@ func (d *rulesDataSource) Configure(_ context.Context, req datasource.ConfigureR
 		return
 	}
 
-	d.client = req.ProviderData.(*client.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *rulesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state rulesDataSourceModel
 
-	rules, err := d.client.GetRules()
+	rules, err := d.client.GetRules(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read SFTPGo Rules",
-			err.Error(),
+			parseAPIError(err),
 		)
 		return
 	}
 
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name < rules[j].Name
+	})
+
 	// Map response body to model
 	for _, rule := range rules {
 		var ruleState eventRuleResourceModel
@@ -15,6 +15,7 @@
 package sftpgo
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -35,7 +36,7 @@ func TestAccRulesDataSource(t *testing.T) {
 		Name: "action1",
 		Type: 4,
 	}
-	_, err = c.CreateAction(action1)
+	_, err = c.CreateAction(context.Background(), action1)
 	require.NoError(t, err)
 	rule := client.EventRule{
 		Name:    "test rule",
@@ -56,13 +57,17 @@ func TestAccRulesDataSource(t *testing.T) {
 			},
 		},
 	}
-	_, err = c.CreateRule(rule)
+	_, err = c.CreateRule(context.Background(), rule)
 	require.NoError(t, err)
 
+	otherRule := rule
+	otherRule.Name = "a rule"
+
 	defer func() {
-		err = c.DeleteRule(rule.Name)
+		_ = c.DeleteRule(context.Background(), otherRule.Name)
+		err = c.DeleteRule(context.Background(), rule.Name)
 		require.NoError(t, err)
-		err = c.DeleteAction(action1.Name)
+		err = c.DeleteAction(context.Background(), action1.Name)
 		require.NoError(t, err)
 	}()
 
@@ -111,6 +116,19 @@ func TestAccRulesDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.sftpgo_rules.test", "id", placeholderID),
 				),
 			},
+			// rules are sorted by name
+			{
+				PreConfig: func() {
+					_, err := c.CreateRule(context.Background(), otherRule)
+					require.NoError(t, err)
+				},
+				Config: `data "sftpgo_rules" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sftpgo_rules.test", "rules.#", "2"),
+					resource.TestCheckResourceAttr("data.sftpgo_rules.test", "rules.0.name", otherRule.Name),
+					resource.TestCheckResourceAttr("data.sftpgo_rules.test", "rules.1.name", rule.Name),
+				),
+			},
 		},
 	})
 }
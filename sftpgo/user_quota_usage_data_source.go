@@ -0,0 +1,146 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &userQuotaUsageDataSource{}
+	_ datasource.DataSourceWithConfigure = &userQuotaUsageDataSource{}
+)
+
+// NewUserQuotaUsageDataSource is a helper function to simplify the provider implementation.
+func NewUserQuotaUsageDataSource() datasource.DataSource {
+	return &userQuotaUsageDataSource{}
+}
+
+// userQuotaUsageDataSource is the data source implementation.
+type userQuotaUsageDataSource struct {
+	client *client.Client
+}
+
+// Metadata returns the data source type name.
+func (d *userQuotaUsageDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_quota_usage"
+}
+
+// Schema defines the schema for the data source.
+func (d *userQuotaUsageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the quota and data transfer usage of a user.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Required to use the test framework. Matches the username.",
+			},
+			"username": schema.StringAttribute{
+				Required:    true,
+				Description: "Username of the user to fetch the usage for.",
+			},
+			"used_quota_size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Used quota as bytes.",
+			},
+			"used_quota_files": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Used quota as number of files.",
+			},
+			"used_upload_data_transfer": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Uploaded size, as bytes, since the last reset.",
+			},
+			"used_download_data_transfer": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Downloaded size, as bytes, since the last reset.",
+			},
+			"last_quota_update": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Last quota update as unix timestamp in milliseconds. 0 means never updated.",
+			},
+			"last_login": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Last login as unix timestamp in milliseconds. 0 means never logged in.",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *userQuotaUsageDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*providerData).client
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *userQuotaUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config userQuotaUsageDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	user, err := d.client.GetUser(ctx, config.Username.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read SFTPGo User Quota Usage",
+			"Could not read SFTPGo User "+config.Username.ValueString()+": "+parseAPIError(err),
+		)
+		return
+	}
+
+	// usage values are always set, 0 is a meaningful value here
+	state := userQuotaUsageDataSourceModel{
+		ID:                       types.StringValue(user.Username),
+		Username:                 types.StringValue(user.Username),
+		UsedQuotaSize:            types.Int64Value(user.UsedQuotaSize),
+		UsedQuotaFiles:           types.Int64Value(int64(user.UsedQuotaFiles)),
+		UsedUploadDataTransfer:   types.Int64Value(user.UsedUploadDataTransfer),
+		UsedDownloadDataTransfer: types.Int64Value(user.UsedDownloadDataTransfer),
+		LastQuotaUpdate:          types.Int64Value(user.LastQuotaUpdate),
+		LastLogin:                types.Int64Value(user.LastLogin),
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// userQuotaUsageDataSourceModel maps the data source schema data.
+type userQuotaUsageDataSourceModel struct {
+	ID                       types.String `tfsdk:"id"`
+	Username                 types.String `tfsdk:"username"`
+	UsedQuotaSize            types.Int64  `tfsdk:"used_quota_size"`
+	UsedQuotaFiles           types.Int64  `tfsdk:"used_quota_files"`
+	UsedUploadDataTransfer   types.Int64  `tfsdk:"used_upload_data_transfer"`
+	UsedDownloadDataTransfer types.Int64  `tfsdk:"used_download_data_transfer"`
+	LastQuotaUpdate          types.Int64  `tfsdk:"last_quota_update"`
+	LastLogin                types.Int64  `tfsdk:"last_login"`
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+func TestAccUserQuotaUsageDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	config := `
+		resource "sftpgo_user" "test" {
+		  username = "test user usage"
+		  status = 1
+		  home_dir = "/tmp/testuserusage"
+		  permissions = {
+			"/" = "*"
+		  }
+		}
+
+		data "sftpgo_user_quota_usage" "test" {
+		  username = sftpgo_user.test.username
+		}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sftpgo_user_quota_usage.test", "id", "test user usage"),
+					resource.TestCheckResourceAttr("data.sftpgo_user_quota_usage.test", "username", "test user usage"),
+					resource.TestCheckResourceAttr("data.sftpgo_user_quota_usage.test", "used_quota_size", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_user_quota_usage.test", "used_quota_files", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_user_quota_usage.test", "used_upload_data_transfer", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_user_quota_usage.test", "used_download_data_transfer", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_user_quota_usage.test", "last_quota_update", "0"),
+					resource.TestCheckResourceAttr("data.sftpgo_user_quota_usage.test", "last_login", "0"),
+				),
+			},
+			{
+				PreConfig: func() {
+					err := c.UpdateUserQuotaUsage(context.Background(), "test user usage", client.QuotaUsage{
+						UsedQuotaSize:  4096,
+						UsedQuotaFiles: 5,
+					})
+					require.NoError(t, err)
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sftpgo_user_quota_usage.test", "used_quota_size", "4096"),
+					resource.TestCheckResourceAttr("data.sftpgo_user_quota_usage.test", "used_quota_files", "5"),
+					resource.TestCheckResourceAttrSet("data.sftpgo_user_quota_usage.test", "last_quota_update"),
+				),
+			},
+		},
+	})
+}
@@ -16,7 +16,10 @@ package sftpgo
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -26,17 +29,21 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/sftpgo/sdk"
 
 	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &userResource{}
-	_ resource.ResourceWithConfigure   = &userResource{}
-	_ resource.ResourceWithImportState = &userResource{}
+	_ resource.Resource                   = &userResource{}
+	_ resource.ResourceWithConfigure      = &userResource{}
+	_ resource.ResourceWithImportState    = &userResource{}
+	_ resource.ResourceWithValidateConfig = &userResource{}
+	_ resource.ResourceWithModifyPlan     = &userResource{}
 )
 
 // NewUserResource is a helper function to simplify the provider implementation.
@@ -47,15 +54,27 @@ func NewUserResource() resource.Resource {
 // userResource is the resource implementation.
 type userResource struct {
 	client *client.Client
+	// checkReferences enables the plan time check for the user groups
+	checkReferences bool
+	// normalizeSFTPEndpoint is the provider level setting
+	normalizeSFTPEndpoint bool
+	// defaultUserStatus and defaultUserRole are the provider level defaults
+	defaultUserStatus *int
+	defaultUserRole   string
 }
 
-// Configure adds the provider configured client to the resource.
+// Configure adds the provider configured client and settings to the resource.
 func (r *userResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	r.client = req.ProviderData.(*client.Client)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.checkReferences = data.checkReferences
+	r.normalizeSFTPEndpoint = data.normalizeSFTPEndpoint
+	r.defaultUserStatus = data.defaultUserStatus
+	r.defaultUserRole = data.defaultUserRole
 }
 
 // Metadata returns the resource type name.
@@ -64,7 +83,7 @@ func (r *userResource) Metadata(_ context.Context, req resource.MetadataRequest,
 }
 
 // Schema defines the schema for the resource.
-func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *userResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "User",
 		Attributes: map[string]schema.Attribute{
@@ -80,8 +99,9 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Description: "Unique username.",
 			},
 			"status": schema.Int64Attribute{
-				Required:    true,
-				Description: "1 enabled, 0 disabled (login is not allowed).",
+				Optional:    true,
+				Computed:    true,
+				Description: "1 enabled, 0 disabled (login is not allowed). Required unless default_user_status is set in the provider configuration.",
 				Validators: []validator.Int64{
 					int64validator.Between(0, 1),
 				},
@@ -89,20 +109,30 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			"expiration_date": schema.Int64Attribute{
 				Optional:    true,
 				Description: "Account expiration date as unix timestamp in milliseconds. An expired account cannot login.",
+				Validators: []validator.Int64{
+					millisecondsTimestampValidator{},
+				},
+			},
+			"expiration_date_rfc3339": schema.StringAttribute{
+				Computed:    true,
+				Description: "Account expiration date as RFC 3339 UTC date, derived from expiration_date.",
 			},
 			"password": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				Description: "Plain text password or hash format supported by SFTPGo. Set to empty to remove the password.",
+				Description: "Plain text password or hash format supported by SFTPGo. Set to empty to remove the password. Pre-hashed passwords are compared with the hash stored in SFTPGo, so changes made outside Terraform are detected.",
 			},
 			"public_keys": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
-				Description: "List of public keys in OpenSSH format.",
+				Description: "List of public keys in OpenSSH format. Keys are compared by key material, differences in comments and white spaces are ignored.",
 			},
 			"home_dir": schema.StringAttribute{
 				Required:    true,
 				Description: "The user cannot upload or download files outside this directory. Must be an absolute path.",
+				Validators: []validator.String{
+					localHomeDirValidator{},
+				},
 			},
 			"email": schema.StringAttribute{
 				Optional: true,
@@ -130,19 +160,31 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			"permissions": schema.MapAttribute{
 				Required:    true,
 				ElementType: types.StringType,
-				Description: "Comma separated, per-directory, permissions.",
+				Description: "Comma separated, per-directory, permissions. An empty map is kept as configured, SFTPGo does not distinguish it from no permissions.",
+				Validators: []validator.Map{
+					permissionsValidator{},
+				},
 			},
 			"used_quota_size": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Used quota as bytes.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"used_quota_files": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Used quota as number of files.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"last_quota_update": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Last quota update as unix timestamp in milliseconds.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"upload_bandwidth": schema.Int64Attribute{
 				Optional:    true,
@@ -154,15 +196,15 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			},
 			"upload_data_transfer": schema.Int64Attribute{
 				Optional:    true,
-				Description: "Maximum data transfer allowed for uploads as MB. Not set means no limit.",
+				Description: "Maximum data transfer allowed for uploads as MB. Not set means no limit. It cannot be set together with total_data_transfer.",
 			},
 			"download_data_transfer": schema.Int64Attribute{
 				Optional:    true,
-				Description: "Maximum data transfer allowed for downloads as MB. Not set means no limit.",
+				Description: "Maximum data transfer allowed for downloads as MB. Not set means no limit. It cannot be set together with total_data_transfer.",
 			},
 			"total_data_transfer": schema.Int64Attribute{
 				Optional:    true,
-				Description: "Maximum total data transfer as MB. Not set means unlimited. You can set a total data transfer instead of the individual values for uploads and downloads.",
+				Description: "Maximum total data transfer as MB. Not set means unlimited. You can set a total data transfer instead of the individual values for uploads and downloads, they are mutually exclusive.",
 			},
 			"used_upload_data_transfer": schema.Int64Attribute{
 				Computed:    true,
@@ -176,6 +218,10 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Computed:    true,
 				Description: "Last login as unix timestamp in milliseconds.",
 			},
+			"last_login_rfc3339": schema.StringAttribute{
+				Computed:    true,
+				Description: "Last login as RFC 3339 UTC date, derived from last_login.",
+			},
 			"created_at": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Creation time as unix timestamp in milliseconds.",
@@ -199,6 +245,10 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Computed:    true,
 				Description: "Last password change as unix timestamp in milliseconds.",
 			},
+			"password_expires_at": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Password expiration as unix timestamp in milliseconds, computed from last_password_change and the password_expiration filter. The password_expiration inherited from groups is not taken into account. Not set means no expiration.",
+			},
 			"description": schema.StringAttribute{
 				Optional:    true,
 				Description: "Optional description.",
@@ -209,11 +259,12 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			},
 			"role": schema.StringAttribute{
 				Optional:    true,
-				Description: "Role name.",
+				Computed:    true,
+				Description: "Role name. If not set, default_user_role from the provider configuration is applied, if any.",
 			},
 			"groups": schema.ListNestedAttribute{
 				Optional:    true,
-				Description: "Groups.",
+				Description: "Groups. A user can have at most one primary group. Groups are sent to SFTPGo in the configured order. SFTPGo applies the primary group first and then the secondary groups, in order: a setting already defined by the user or by a previously applied group, for example the permissions for a directory or a virtual folder with the same virtual path, is not overridden. Membership only groups do not change the user settings. The configured order is kept in the state if SFTPGo returns the same groups in a different order.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"name": schema.StringAttribute{
@@ -234,18 +285,218 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			"virtual_folders": getSchemaForVirtualFolders(),
 			"filesystem":      getSchemaForFilesystem(),
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
+// ValidateConfig validates the resource configuration.
+func (r *userResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validateFilesystemConfig(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateGCSCredentials(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateS3Credentials(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateS3Endpoint(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateSkipTLSVerify(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateAzBlobEmulator(ctx, req.Config, path.Root("filesystem"))...)
+	resp.Diagnostics.Append(validateDataTransferConfig(ctx, req.Config, path.Empty())...)
+	resp.Diagnostics.Append(validateTLSUsername(ctx, req.Config, path.Root("filters"))...)
+	resp.Diagnostics.Append(validateTwoFactorProtocols(ctx, req.Config, path.Root("filters"))...)
+	resp.Diagnostics.Append(validateBandwidthLimits(ctx, req.Config, path.Root("filters"))...)
+	resp.Diagnostics.Append(validateUserGroups(ctx, req.Config)...)
+
+	var folders types.List
+	diags := req.Config.GetAttribute(ctx, path.Root("virtual_folders"), &folders)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	if folders.IsNull() || folders.IsUnknown() {
+		return
+	}
+
+	var elems []types.Object
+	diags = folders.ElementsAs(ctx, &elems, false)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	virtualPaths := make([]types.String, len(elems))
+	for idx, elem := range elems {
+		virtualPaths[idx] = types.StringNull()
+		if elem.IsNull() || elem.IsUnknown() {
+			continue
+		}
+		var folder virtualFolder
+		diags = elem.As(ctx, &folder, basetypes.ObjectAsOptions{
+			UnhandledNullAsEmpty:    true,
+			UnhandledUnknownAsEmpty: true,
+		})
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+		virtualPaths[idx] = folder.VirtualPath
+	}
+	resp.Diagnostics.Append(checkVirtualFoldersPaths(virtualPaths)...)
+}
+
+// validateUserGroups checks that at most one primary group is configured.
+func validateUserGroups(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var groups types.List
+	diags := config.GetAttribute(ctx, path.Root("groups"), &groups)
+	if diags.HasError() || groups.IsNull() || groups.IsUnknown() {
+		return diags
+	}
+	var mappings []userGroupMapping
+	diags.Append(groups.ElementsAs(ctx, &mappings, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	var primaryGroups []string
+	for _, mapping := range mappings {
+		if mapping.Type.ValueInt64() == int64(sdk.GroupTypePrimary) {
+			primaryGroups = append(primaryGroups, mapping.Name.ValueString())
+		}
+	}
+	if len(primaryGroups) > 1 {
+		diags.AddAttributeError(
+			path.Root("groups"),
+			"Invalid User Groups",
+			fmt.Sprintf("A user can have at most one primary group, got: %s.", strings.Join(primaryGroups, ", ")),
+		)
+	}
+	return diags
+}
+
+// setPlanDefaults sets status and role, if not configured, to the provider
+// level defaults. Explicit values are never overridden.
+func (r *userResource) setPlanDefaults(ctx context.Context, config tfsdk.Config, plan *tfsdk.Plan) diag.Diagnostics {
+	var status types.Int64
+	diags := config.GetAttribute(ctx, path.Root("status"), &status)
+	if diags.HasError() {
+		return diags
+	}
+	if status.IsNull() {
+		if r.defaultUserStatus == nil {
+			diags.AddAttributeError(
+				path.Root("status"),
+				"Missing User Status",
+				"The status attribute is required unless default_user_status is set in the provider configuration.",
+			)
+			return diags
+		}
+		diags.Append(plan.SetAttribute(ctx, path.Root("status"), types.Int64Value(int64(*r.defaultUserStatus)))...)
+	}
+
+	var role types.String
+	diags.Append(config.GetAttribute(ctx, path.Root("role"), &role)...)
+	if diags.HasError() || !role.IsNull() {
+		return diags
+	}
+	diags.Append(plan.SetAttribute(ctx, path.Root("role"), getOptionalString(r.defaultUserRole))...)
+	return diags
+}
+
+// setUnknownDefaults sets status and role left unknown in the plan, because
+// the provider was not configured while planning, to the provider level
+// defaults. The status is required if there is no default.
+func (r *userResource) setUnknownDefaults(plan *userResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if plan.Status.IsUnknown() {
+		if r.defaultUserStatus == nil {
+			diags.AddAttributeError(
+				path.Root("status"),
+				"Missing User Status",
+				"The status attribute is required unless default_user_status is set in the provider configuration.",
+			)
+			return diags
+		}
+		plan.Status = types.Int64Value(int64(*r.defaultUserStatus))
+	}
+	if plan.Role.IsUnknown() {
+		plan.Role = getOptionalString(r.defaultUserRole)
+	}
+	return diags
+}
+
+// ModifyPlan applies the provider level defaults for status and role, sets
+// the derived expiration_date_rfc3339 and checks that the referenced groups
+// exist, if enabled in the provider configuration.
+func (r *userResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	// if the provider is not configured yet, for example because its
+	// configuration has unknown values, the defaults are not available:
+	// status and role are left unknown and resolved in Create and Update
+	if r.client != nil {
+		resp.Diagnostics.Append(r.setPlanDefaults(ctx, req.Config, &resp.Plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	// the RFC 3339 expiration date is derived from the planned value, so
+	// it is readable in the plan output
+	var expirationDate types.Int64
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("expiration_date"), &expirationDate)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("expiration_date_rfc3339"),
+		getRFC3339Timestamp(expirationDate))...)
+	if resp.Diagnostics.HasError() || !r.checkReferences {
+		return
+	}
+
+	var groups types.List
+	diags := req.Plan.GetAttribute(ctx, path.Root("groups"), &groups)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || groups.IsNull() || groups.IsUnknown() {
+		return
+	}
+	var mappings []userGroupMapping
+	diags = groups.ElementsAs(ctx, &mappings, false)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	var names []string
+	for _, mapping := range mappings {
+		if mapping.Name.IsNull() || mapping.Name.IsUnknown() {
+			continue
+		}
+		names = append(names, mapping.Name.ValueString())
+	}
+	resp.Diagnostics.Append(checkGroupsExist(ctx, r.client, names)...)
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Retrieve values from plan
-	var plan userResourceModel
+	var plan userResourceWithTimeoutsModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.setUnknownDefaults(&plan.userResourceModel)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	user, diags := plan.toSFTPGo(ctx)
 	resp.Diagnostics.Append(diags...)
@@ -253,26 +504,38 @@ func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	user, err := r.client.CreateUser(*user)
+	user, adopted, err := createOrAdopt(ctx, r.client,
+		func() (*client.User, error) { return r.client.CreateUser(ctx, *user) },
+		func() (*client.User, error) { return r.client.GetUser(ctx, plan.Username.ValueString()) },
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating user",
-			"Could not create user, unexpected error: "+err.Error(),
+			"Could not create user: "+parseAPIError(err),
 		)
 		return
 	}
-	var state userResourceModel
+	var state userResourceWithTimeoutsModel
 	diags = state.fromSFTPGo(ctx, user)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	diags = r.preservePlanFields(ctx, &plan, &state)
+	diags = r.preservePlanFields(ctx, &plan.userResourceModel, &state.userResourceModel)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	state.Timeouts = plan.Timeouts
+
+	if adopted {
+		resp.Diagnostics.Append(checkAdoptedState(ctx, req.Plan, state, "user", plan.Username.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
@@ -284,34 +547,47 @@ func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, r
 // Read refreshes the Terraform state with the latest data.
 func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	// Get current state
-	var state userResourceModel
+	var state userResourceWithTimeoutsModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
 
-	user, err := r.client.GetUser(state.Username.ValueString())
+	user, err := r.client.GetUser(ctx, state.Username.ValueString())
 	if err != nil {
+		if client.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo User",
-			"Could not read SFTPGo User "+state.Username.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo User "+state.Username.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
-	var newState userResourceModel
+	var newState userResourceWithTimeoutsModel
 	diags = newState.fromSFTPGo(ctx, user)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	diags = r.preservePlanFields(ctx, &state, &newState)
+	diags = r.preservePlanFields(ctx, &state.userResourceModel, &newState.userResourceModel)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	newState.Timeouts = state.Timeouts
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &newState)
 	resp.Diagnostics.Append(diags...)
@@ -323,47 +599,67 @@ func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	// Retrieve values from plan
-	var plan userResourceModel
+	var plan userResourceWithTimeoutsModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.setUnknownDefaults(&plan.userResourceModel)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	user, diags := plan.toSFTPGo(ctx)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err := r.client.UpdateUser(*user)
+	err := r.client.UpdateUser(ctx, *user)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating user",
-			"Could not update user, unexpected error: "+err.Error(),
+			"Could not update user: "+parseAPIError(err),
 		)
 		return
 	}
 
-	user, err = r.client.GetUser(plan.Username.ValueString())
+	user, err = r.client.GetUser(ctx, plan.Username.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading SFTPGo User",
-			"Could not read SFTPGo User "+plan.Username.ValueString()+": "+err.Error(),
+			"Could not read SFTPGo User "+plan.Username.ValueString()+": "+parseAPIError(err),
 		)
 		return
 	}
 
-	var state userResourceModel
+	var state userResourceWithTimeoutsModel
 	diags = state.fromSFTPGo(ctx, user)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	diags = r.preservePlanFields(ctx, &plan, &state)
+	diags = r.preservePlanFields(ctx, &plan.userResourceModel, &state.userResourceModel)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// the quota usage is updated by SFTPGo in the background, keep the
+	// planned values, the current ones are read on the next refresh
+	state.UsedQuotaSize = getPlannedInt64(plan.UsedQuotaSize, state.UsedQuotaSize)
+	state.UsedQuotaFiles = getPlannedInt64(plan.UsedQuotaFiles, state.UsedQuotaFiles)
+	state.LastQuotaUpdate = getPlannedInt64(plan.LastQuotaUpdate, state.LastQuotaUpdate)
+
+	state.Timeouts = plan.Timeouts
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -375,19 +671,26 @@ func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state userResourceModel
+	var state userResourceWithTimeoutsModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
 
 	// Delete existing user
-	err := r.client.DeleteUser(state.Username.ValueString())
+	err := r.client.DeleteUser(ctx, state.Username.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting SFTPGo user",
-			"Could not delete user, unexpected error: "+err.Error(),
+			"Could not delete user: "+parseAPIError(err),
 		)
 		return
 	}
@@ -399,9 +702,38 @@ func (*userResource) ImportState(ctx context.Context, req resource.ImportStateRe
 	resource.ImportStatePassthroughID(ctx, path.Root("username"), req, resp)
 }
 
-func (*userResource) preservePlanFields(ctx context.Context, plan, state *userResourceModel) diag.Diagnostics {
-	if !plan.Password.IsNull() {
-		state.Password = plan.Password
+func (r *userResource) preservePlanFields(ctx context.Context, plan, state *userResourceModel) diag.Diagnostics {
+	// SFTPGo stores pre-hashed passwords as is, so changes made outside
+	// Terraform are detected. Plain text passwords are always preserved
+	state.Password = preserveSecret(plan.Password, state.Password)
+	state.Description = preserveEmptyString(plan.Description, state.Description)
+	state.Permissions = preserveEmptyMap(plan.Permissions, state.Permissions)
+	// groups are returned sorted by type, keep the configured order if they match
+	if hasSameUserGroups(plan.Groups, state.Groups) {
+		state.Groups = plan.Groups
+	}
+	// SFTPGo does not distinguish between no public keys and an empty list,
+	// keep the empty list from the plan to avoid inconsistent results
+	if !plan.PublicKeys.IsNull() && !plan.PublicKeys.IsUnknown() && len(plan.PublicKeys.Elements()) == 0 &&
+		state.PublicKeys.IsNull() {
+		state.PublicKeys = plan.PublicKeys
+	}
+	// keys are compared by their key material, so changes to comments or
+	// white spaces made by SFTPGo do not cause diffs
+	if hasSamePublicKeys(plan.PublicKeys, state.PublicKeys) {
+		state.PublicKeys = plan.PublicKeys
+	}
+	// 0 means unlimited and SFTPGo omits it, keep an explicit 0
+	state.QuotaSize = preserveZeroInt64(plan.QuotaSize, state.QuotaSize)
+	state.QuotaFiles = preserveZeroInt64(plan.QuotaFiles, state.QuotaFiles)
+	state.UploadBandwidth = preserveZeroInt64(plan.UploadBandwidth, state.UploadBandwidth)
+	state.DownloadBandwidth = preserveZeroInt64(plan.DownloadBandwidth, state.DownloadBandwidth)
+	state.UploadDataTransfer = preserveZeroInt64(plan.UploadDataTransfer, state.UploadDataTransfer)
+	state.DownloadDataTransfer = preserveZeroInt64(plan.DownloadDataTransfer, state.DownloadDataTransfer)
+	state.TotalDataTransfer = preserveZeroInt64(plan.TotalDataTransfer, state.TotalDataTransfer)
+	diags := preserveUserFiltersPlanFields(ctx, plan, state)
+	if diags.HasError() {
+		return diags
 	}
 
 	if plan.FsConfig.IsNull() {
@@ -409,7 +741,7 @@ func (*userResource) preservePlanFields(ctx context.Context, plan, state *userRe
 	}
 
 	var fsPlan filesystem
-	diags := plan.FsConfig.As(ctx, &fsPlan, basetypes.ObjectAsOptions{
+	diags = plan.FsConfig.As(ctx, &fsPlan, basetypes.ObjectAsOptions{
 		UnhandledNullAsEmpty:    true,
 		UnhandledUnknownAsEmpty: true,
 	})
@@ -425,7 +757,7 @@ func (*userResource) preservePlanFields(ctx context.Context, plan, state *userRe
 		return diags
 	}
 
-	fs, diags := preserveFsConfigPlanFields(ctx, fsPlan, fsState)
+	fs, diags := preserveFsConfigPlanFields(ctx, fsPlan, fsState, r.normalizeSFTPEndpoint)
 	if diags.HasError() {
 		return diags
 	}
@@ -433,3 +765,92 @@ func (*userResource) preservePlanFields(ctx context.Context, plan, state *userRe
 
 	return nil
 }
+
+// preserveUserFiltersPlanFields keeps the boolean filters explicitly set to
+// false, SFTPGo omits them from the API response, and the TLS certificates
+// matching the returned ones once normalized.
+func preserveUserFiltersPlanFields(ctx context.Context, plan, state *userResourceModel) diag.Diagnostics {
+	if plan.Filters.IsNull() || plan.Filters.IsUnknown() || state.Filters.IsNull() || state.Filters.IsUnknown() {
+		return nil
+	}
+
+	var filtersPlan userFilters
+	diags := plan.Filters.As(ctx, &filtersPlan, basetypes.ObjectAsOptions{
+		UnhandledNullAsEmpty:    true,
+		UnhandledUnknownAsEmpty: true,
+	})
+	if diags.HasError() {
+		return diags
+	}
+	var filtersState userFilters
+	diags = state.Filters.As(ctx, &filtersState, basetypes.ObjectAsOptions{
+		UnhandledNullAsEmpty:    true,
+		UnhandledUnknownAsEmpty: true,
+	})
+	if diags.HasError() {
+		return diags
+	}
+
+	baseFilters := filtersState.getBaseFilters()
+	baseFilters.preservePlanFields(filtersPlan.getBaseFilters())
+	filtersState.fromBaseFilters(&baseFilters)
+	filtersState.AllowAPIKeyAuth = preserveFalseBool(filtersPlan.AllowAPIKeyAuth, filtersState.AllowAPIKeyAuth)
+	filtersState.CheckPasswordDisabled = preserveFalseBool(filtersPlan.CheckPasswordDisabled,
+		filtersState.CheckPasswordDisabled)
+	filtersState.RequirePasswordChange = preserveFalseBool(filtersPlan.RequirePasswordChange,
+		filtersState.RequirePasswordChange)
+	if hasSameTLSCerts(filtersPlan.TLSCerts, filtersState.TLSCerts) {
+		filtersState.TLSCerts = filtersPlan.TLSCerts
+	}
+	filters, diags := types.ObjectValueFrom(ctx, filtersState.getTFAttributes(), filtersState)
+	if diags.HasError() {
+		return diags
+	}
+	state.Filters = filters
+	return nil
+}
+
+// preserveEmptyList returns the planned value if it is an empty list and
+// SFTPGo returned no value.
+func preserveEmptyList(plan, state types.List) types.List {
+	if plan.IsNull() || plan.IsUnknown() || len(plan.Elements()) > 0 || !state.IsNull() {
+		return state
+	}
+	return plan
+}
+
+// preserveEmptyMap returns the planned value if it is an empty map and
+// SFTPGo returned no value.
+func preserveEmptyMap(plan, state types.Map) types.Map {
+	if plan.IsNull() || plan.IsUnknown() || len(plan.Elements()) > 0 || !state.IsNull() {
+		return state
+	}
+	return plan
+}
+
+// preserveZeroInt64 returns the planned value if it is 0 and SFTPGo returned
+// no value.
+func preserveZeroInt64(plan, state types.Int64) types.Int64 {
+	if plan.IsNull() || plan.IsUnknown() || plan.ValueInt64() != 0 || !state.IsNull() {
+		return state
+	}
+	return plan
+}
+
+// preserveEmptyString returns the planned value if it is empty and SFTPGo
+// returned no value.
+func preserveEmptyString(plan, state types.String) types.String {
+	if plan.IsNull() || plan.IsUnknown() || plan.ValueString() != "" || !state.IsNull() {
+		return state
+	}
+	return plan
+}
+
+// preserveFalseBool returns the planned value if it is false and SFTPGo
+// returned no value.
+func preserveFalseBool(plan, state types.Bool) types.Bool {
+	if plan.IsNull() || plan.IsUnknown() || plan.ValueBool() || !state.IsNull() {
+		return state
+	}
+	return plan
+}
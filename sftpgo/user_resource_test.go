@@ -15,11 +15,30 @@
 package sftpgo
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/sftpgo/sdk"
 	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
 func TestAccUserResource(t *testing.T) {
@@ -28,19 +47,19 @@ func TestAccUserResource(t *testing.T) {
 	}
 	c, err := getClient()
 	require.NoError(t, err)
-	_, err = c.CreateFolder(testFolder)
+	_, err = c.CreateFolder(context.Background(), testFolder)
 	require.NoError(t, err)
-	_, err = c.CreateGroup(testGroup)
+	_, err = c.CreateGroup(context.Background(), testGroup)
 	require.NoError(t, err)
-	_, err = c.CreateRole(testRole)
+	_, err = c.CreateRole(context.Background(), testRole)
 	require.NoError(t, err)
 
 	defer func() {
-		err = c.DeleteGroup(testGroup.Name)
+		err = c.DeleteGroup(context.Background(), testGroup.Name)
 		require.NoError(t, err)
-		err = c.DeleteFolder(testFolder.Name)
+		err = c.DeleteFolder(context.Background(), testFolder.Name)
 		require.NoError(t, err)
-		err = c.DeleteRole(testRole.Name)
+		err = c.DeleteRole(context.Background(), testRole.Name)
 		require.NoError(t, err)
 	}()
 
@@ -176,9 +195,10 @@ func TestAccUserResource(t *testing.T) {
 			},
 			// ImportState testing
 			{
-				ResourceName:      "sftpgo_user.test",
-				ImportState:       true,
-				ImportStateVerify: false, // SFTPGo will not return plain text password/secrets
+				ResourceName:            "sftpgo_user.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: testAccImportStateVerifyIgnore("sftpgo_user"), // SFTPGo will not return plain text password/secrets
 			},
 			// Update and Read testing
 			{
@@ -391,3 +411,1456 @@ func TestAccUserResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccUserResourcePasswordHash(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	config := `
+		resource "sftpgo_user" "test" {
+		  username = "test user hash"
+		  status = 1
+		  password = "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
+		  home_dir = "/tmp/testuserhash"
+		  permissions = {
+			"/" = "*"
+		  }
+		}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "username", "test user hash"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "password",
+						"$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"),
+				),
+			},
+			// The stored hash matches the configured one, no changes expected
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourcePublicKeys(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "sftpgo_user" "test" {
+				  username = "test user keys"
+				  status = 1
+				  home_dir = "/tmp/testuserkeys"
+				  public_keys = [
+					"ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBEUWwDwEWhTbF0MqAsp/oXK1HR2cElhM8oo1uVmL3ZeDKDiTm4ljMr92wfTgIGDqIoxmVqgYIkAOAhuykAVWBzc= user@host",
+					"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEJtGfxU2QRVlsU/rwG/c/sn5oUmvqpPD5qssXlLTaA2 user@host"
+				  ]
+				  permissions = {
+					"/" = "*"
+				  }
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "public_keys.#", "2"),
+				),
+			},
+			// Clear all the public keys
+			{
+				Config: `
+				resource "sftpgo_user" "test" {
+				  username = "test user keys"
+				  status = 1
+				  home_dir = "/tmp/testuserkeys"
+				  public_keys = []
+				  permissions = {
+					"/" = "*"
+				  }
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "public_keys.#", "0"),
+					func(_ *terraform.State) error {
+						user, err := c.GetUser(context.Background(), "test user keys")
+						if err != nil {
+							return err
+						}
+						if len(user.PublicKeys) > 0 {
+							return fmt.Errorf("public keys not cleared: %v", user.PublicKeys)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: `
+				resource "sftpgo_user" "test" {
+				  username = "test user keys"
+				  status = 1
+				  home_dir = "/tmp/testuserkeys"
+				  public_keys = []
+				  permissions = {
+					"/" = "*"
+				  }
+				}`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourceAllowAPIKeyAuth(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	getConfig := func(allowAPIKeyAuth bool) string {
+		return fmt.Sprintf(`
+			resource "sftpgo_user" "test" {
+			  username = "test user api key"
+			  status = 1
+			  home_dir = "/tmp/testuserapikey"
+			  permissions = {
+				"/" = "*"
+			  }
+			  filters = {
+				allow_api_key_auth = %t
+			  }
+			}`, allowAPIKeyAuth)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.allow_api_key_auth", "true"),
+					func(_ *terraform.State) error {
+						user, err := c.GetUser(context.Background(), "test user api key")
+						if err != nil {
+							return err
+						}
+						if !user.Filters.AllowAPIKeyAuth {
+							return fmt.Errorf("API key authentication not allowed")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: getConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.allow_api_key_auth", "false"),
+					func(_ *terraform.State) error {
+						user, err := c.GetUser(context.Background(), "test user api key")
+						if err != nil {
+							return err
+						}
+						if user.Filters.AllowAPIKeyAuth {
+							return fmt.Errorf("API key authentication still allowed")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   getConfig(false),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourceCheckPasswordDisabled(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	getConfig := func(checkPasswordDisabled bool) string {
+		return fmt.Sprintf(`
+			resource "sftpgo_user" "test" {
+			  username = "test user check password"
+			  status = 1
+			  home_dir = "/tmp/testusercheckpassword"
+			  permissions = {
+				"/" = "*"
+			  }
+			  filters = {
+				check_password_disabled = %t
+			  }
+			}`, checkPasswordDisabled)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.check_password_disabled", "false"),
+				),
+			},
+			{
+				Config: getConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.check_password_disabled", "true"),
+				),
+			},
+			{
+				Config:   getConfig(true),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourceRequirePasswordChange(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	getConfig := func(requirePasswordChange bool) string {
+		return fmt.Sprintf(`
+			resource "sftpgo_user" "test" {
+			  username = "test user password change"
+			  status = 1
+			  password = "secret pwd"
+			  home_dir = "/tmp/testuserpasswordchange"
+			  permissions = {
+				"/" = "*"
+			  }
+			  filters = {
+				require_password_change = %t
+			  }
+			}`, requirePasswordChange)
+	}
+
+	checkUser := func(requirePasswordChange bool) resource.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			user, err := c.GetUser(context.Background(), "test user password change")
+			if err != nil {
+				return err
+			}
+			if user.Filters.RequirePasswordChange != requirePasswordChange {
+				return fmt.Errorf("unexpected require password change: %t", user.Filters.RequirePasswordChange)
+			}
+			if user.Password == "" {
+				return fmt.Errorf("password unexpectedly removed")
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.require_password_change", "false"),
+					checkUser(false),
+				),
+			},
+			{
+				Config: getConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.require_password_change", "true"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "password", "secret pwd"),
+					checkUser(true),
+				),
+			},
+			{
+				Config:   getConfig(true),
+				PlanOnly: true,
+			},
+			{
+				Config: getConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.require_password_change", "false"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "password", "secret pwd"),
+					checkUser(false),
+				),
+			},
+			{
+				Config:   getConfig(false),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourceQuotaUsage(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	getConfig := func(description string) string {
+		return fmt.Sprintf(`
+			resource "sftpgo_user" "test" {
+			  username = "test user quota"
+			  status = 1
+			  description = %q
+			  home_dir = "/tmp/testuserquota"
+			  permissions = {
+				"/" = "*"
+			  }
+			  quota_size = 1048576
+			}`, description)
+	}
+	setQuotaUsage := func(size int64, files int) func() {
+		return func() {
+			err := c.UpdateUserQuotaUsage(context.Background(), "test user quota", client.QuotaUsage{
+				UsedQuotaSize:  size,
+				UsedQuotaFiles: files,
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig("desc"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "used_quota_size"),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "used_quota_files"),
+				),
+			},
+			// a quota update made by SFTPGo must not cause a diff
+			{
+				PreConfig: setQuotaUsage(1024, 2),
+				Config:    getConfig("desc"),
+				PlanOnly:  true,
+			},
+			{
+				PreConfig: setQuotaUsage(2048, 3),
+				Config:    getConfig("updated desc"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "description", "updated desc"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "used_quota_size", "2048"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "used_quota_files", "3"),
+				),
+			},
+			{
+				PreConfig: setQuotaUsage(4096, 4),
+				Config:    getConfig("updated desc"),
+				PlanOnly:  true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourceExternalUpdate(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	config := `
+		resource "sftpgo_user" "test" {
+		  username = "test user external update"
+		  status = 1
+		  password = "secret pwd"
+		  home_dir = "/tmp/testuserexternalupdate"
+		  permissions = {
+			"/" = "*"
+		  }
+		}`
+	var updatedAt int64
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sftpgo_user.test", "created_at"),
+					resource.TestCheckResourceAttrSet("sftpgo_user.test", "updated_at"),
+					func(_ *terraform.State) error {
+						user, err := c.GetUser(context.Background(), "test user external update")
+						if err != nil {
+							return err
+						}
+						updatedAt = user.UpdatedAt
+						return nil
+					},
+				),
+			},
+			// updating the user outside Terraform bumps updated_at, this
+			// must not produce a diff
+			{
+				PreConfig: func() {
+					// make sure the update time changes
+					time.Sleep(10 * time.Millisecond)
+					user, err := c.GetUser(context.Background(), "test user external update")
+					require.NoError(t, err)
+					err = c.UpdateUser(context.Background(), *user)
+					require.NoError(t, err)
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(s *terraform.State) error {
+						rs := s.RootModule().Resources["sftpgo_user.test"]
+						if rs.Primary.Attributes["updated_at"] == fmt.Sprintf("%d", updatedAt) {
+							return fmt.Errorf("updated_at not refreshed")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserResourceImportGroups(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+	groupNames := []string{"test import group1", "test import group2"}
+	for _, name := range groupNames {
+		_, err = c.CreateGroup(context.Background(), sdk.Group{
+			BaseGroup: sdk.BaseGroup{
+				Name: name,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	defer func() {
+		for _, name := range groupNames {
+			err = c.DeleteGroup(context.Background(), name)
+			require.NoError(t, err)
+		}
+	}()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "sftpgo_user" "test" {
+					  username = "test user import groups"
+					  status = 1
+					  password = "secret pwd"
+					  home_dir = "/tmp/testuserimportgroups"
+					  permissions = {
+						"/" = "*"
+					  }
+					  filesystem = {
+						provider = 1
+						s3config = {
+						  bucket = "bucket"
+						  region = "us-west-1"
+						  access_key = "key"
+						  access_secret = "secret payload"
+						}
+					  }
+					  groups = [
+						{
+						  name = "test import group2"
+						  type = 1
+						},
+						{
+						  name = "test import group1"
+						  type = 2
+						}
+					  ]
+					}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.#", "2"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.0.name", "test import group2"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.0.type", "1"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.1.name", "test import group1"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.1.type", "2"),
+				),
+			},
+			{
+				ResourceName:      "sftpgo_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// SFTPGo will not return plain text password/secrets
+				ImportStateVerifyIgnore: testAccImportStateVerifyIgnore("sftpgo_user"),
+			},
+		},
+	})
+}
+
+func TestAccUserResourceImportFsSecrets(t *testing.T) {
+	testAccImportFsSecrets(t, "sftpgo_user.test", `
+		resource "sftpgo_user" "test" {
+		  username = "test user import secrets"
+		  status = 1
+		  home_dir = "/tmp/testuserimportsecrets"
+		  permissions = {
+			"/" = "*"
+		  }
+		  filesystem = {
+			provider = 5
+			sftpconfig = {
+			  endpoint = "127.0.0.1:22"
+			  username = "root"
+			  password = "sftppwd"
+			  prefix = "/"
+			}
+		  }
+		}`, "filesystem.", "sftpconfig.password")
+}
+
+func TestAccUserResourceSecondaryGroupsOrder(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+	groupNames := []string{"test order group1", "test order group2", "test order group3"}
+	for _, name := range groupNames {
+		_, err = c.CreateGroup(context.Background(), sdk.Group{
+			BaseGroup: sdk.BaseGroup{
+				Name: name,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	defer func() {
+		for _, name := range groupNames {
+			err = c.DeleteGroup(context.Background(), name)
+			require.NoError(t, err)
+		}
+	}()
+
+	getConfig := func(secondaryGroups ...string) string {
+		var groups []string
+		for _, name := range secondaryGroups {
+			groups = append(groups, fmt.Sprintf(`{
+				name = %q
+				type = 2
+			  }`, name))
+		}
+		return fmt.Sprintf(`
+		resource "sftpgo_user" "test" {
+		  username = "test user groups order"
+		  status = 1
+		  home_dir = "/tmp/testusergroupsorder"
+		  permissions = {
+			"/" = "*"
+		  }
+		  groups = [
+			{
+			  name = "test order group2"
+			  type = 1
+			},
+			%s
+		  ]
+		}`, strings.Join(groups, ",\n"))
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig("test order group3", "test order group1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.#", "3"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.0.name", "test order group2"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.1.name", "test order group3"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.2.name", "test order group1"),
+				),
+			},
+			{
+				Config:   getConfig("test order group3", "test order group1"),
+				PlanOnly: true,
+			},
+			// changing the order updates the user
+			{
+				Config: getConfig("test order group1", "test order group3"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("sftpgo_user.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.1.name", "test order group1"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "groups.2.name", "test order group3"),
+				),
+			},
+			{
+				Config:   getConfig("test order group1", "test order group3"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourceFolderQuota(t *testing.T) {
+	config := `
+		resource "sftpgo_folder" "test1" {
+		  name = "tfolder quota1"
+		  mapped_path = "/tmp/tfolderquota1"
+		  filesystem = {
+		    provider = 0
+		  }
+		}
+
+		resource "sftpgo_folder" "test2" {
+		  name = "tfolder quota2"
+		  mapped_path = "/tmp/tfolderquota2"
+		  filesystem = {
+		    provider = 0
+		  }
+		}
+
+		resource "sftpgo_user" "test" {
+		  username = "test user folder quota"
+		  status = 1
+		  password = "secret pwd"
+		  home_dir = "/tmp/testuserfolderquota"
+		  permissions = {
+		    "/" = "*"
+		  }
+		  filesystem = {
+		    provider = 0
+		  }
+		  virtual_folders = [
+		    {
+		      name = sftpgo_folder.test1.name
+		      virtual_path = "/vdir1"
+		      quota_size = -1
+		      quota_files = -1
+		    },
+		    {
+		      name = sftpgo_folder.test2.name
+		      virtual_path = "/vdir2"
+		      quota_size = 0
+		      quota_files = 0
+		    }
+		  ]
+		}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "virtual_folders.#", "2"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "virtual_folders.0.quota_size", "-1"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "virtual_folders.0.quota_files", "-1"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "virtual_folders.1.quota_size", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "virtual_folders.1.quota_files", "0"),
+				),
+			},
+			// -1 and 0 must be preserved after a refresh
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourcePublicKeysComments(t *testing.T) {
+	config := `
+		resource "sftpgo_user" "test" {
+		  username = "test user public keys"
+		  status = 1
+		  home_dir = "/tmp/testuserpublickeys"
+		  public_keys = [
+		    "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMLhC8Kr6mD7o8fUdpQuFj0jOSzRy6JwZnlD+UyGUUwm",
+		    "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFuUV9LXy6rDlxPD7Ta3/WEgm+yZuRXfZEY5vVcCxlWy user@example.com\n"
+		  ]
+		  permissions = {
+		    "/" = "*"
+		  }
+		  filesystem = {
+		    provider = 0
+		  }
+		}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "public_keys.#", "2"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "public_keys.0",
+						"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMLhC8Kr6mD7o8fUdpQuFj0jOSzRy6JwZnlD+UyGUUwm"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "public_keys.1",
+						"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFuUV9LXy6rDlxPD7Ta3/WEgm+yZuRXfZEY5vVcCxlWy user@example.com\n"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourceTLSCertsNormalized(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	c, err := getClient()
+	require.NoError(t, err)
+
+	cert := `-----BEGIN CERTIFICATE-----
+MIICHTCCAaKgAwIBAgIUHnqw7QnB1Bj9oUsNpdb+ZkFPOxMwCgYIKoZIzj0EAwIw
+RTELMAkGA1UEBhMCQVUxEzARBgNVBAgMClNvbWUtU3RhdGUxITAfBgNVBAoMGElu
+dGVybmV0IFdpZGdpdHMgUHR5IEx0ZDAeFw0yMDAyMDQwOTUzMDRaFw0zMDAyMDEw
+OTUzMDRaMEUxCzAJBgNVBAYTAkFVMRMwEQYDVQQIDApTb21lLVN0YXRlMSEwHwYD
+VQQKDBhJbnRlcm5ldCBXaWRnaXRzIFB0eSBMdGQwdjAQBgcqhkjOPQIBBgUrgQQA
+IgNiAARCjRMqJ85rzMC998X5z761nJ+xL3bkmGVqWvrJ51t5OxV0v25NsOgR82CA
+NXUgvhVYs7vNFN+jxtb2aj6Xg+/2G/BNxkaFspIVCzgWkxiz7XE4lgUwX44FCXZM
+3+JeUbKjUzBRMB0GA1UdDgQWBBRhLw+/o3+Z02MI/d4tmaMui9W16jAfBgNVHSME
+GDAWgBRhLw+/o3+Z02MI/d4tmaMui9W16jAPBgNVHRMBAf8EBTADAQH/MAoGCCqG
+SM49BAMCA2kAMGYCMQDqLt2lm8mE+tGgtjDmtFgdOcI72HSbRQ74D5rYTzgST1rY
+/8wTi5xl8TiFUyLMUsICMQC5ViVxdXbhuG7gX6yEqSkMKZICHpO8hqFwOD/uaFVI
+dV4vKmHUzwK/eIx+8Ay3neE=
+-----END CERTIFICATE-----`
+	// CRLF line endings and a trailing newline
+	config := fmt.Sprintf(`
+		resource "sftpgo_user" "test" {
+		  username = "test user tls certs"
+		  status = 1
+		  home_dir = "/tmp/testusertlscerts"
+		  permissions = {
+		    "/" = "*"
+		  }
+		  filesystem = {
+		    provider = 0
+		  }
+		  filters = {
+		    tls_username = "CommonName"
+		    tls_certs = ["%s\r\n"]
+		  }
+		}`, strings.ReplaceAll(cert, "\n", `\r\n`))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.tls_certs.#", "1"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.tls_certs.0",
+						strings.ReplaceAll(cert, "\n", "\r\n")+"\r\n"),
+				),
+			},
+			// the certificate returned by SFTPGo differs only for line
+			// endings and white spaces, this must not produce a diff
+			{
+				PreConfig: func() {
+					user, err := c.GetUser(context.Background(), "test user tls certs")
+					require.NoError(t, err)
+					user.Filters.TLSCerts = []string{cert}
+					err = c.UpdateUser(context.Background(), *user)
+					require.NoError(t, err)
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourceEmptyFilterLists(t *testing.T) {
+	configEmpty := `
+		resource "sftpgo_user" "test" {
+		  username = "test user empty lists"
+		  status = 1
+		  home_dir = "/tmp/testuseremptylists"
+		  permissions = {
+		    "/" = "*"
+		  }
+		  filesystem = {
+		    provider = 0
+		  }
+		  filters = {
+		    allowed_ip = []
+		    denied_protocols = []
+		    web_client = []
+		    two_factor_protocols = []
+		  }
+		}`
+	configOmitted := `
+		resource "sftpgo_user" "test" {
+		  username = "test user empty lists"
+		  status = 1
+		  home_dir = "/tmp/testuseremptylists"
+		  permissions = {
+		    "/" = "*"
+		  }
+		  filesystem = {
+		    provider = 0
+		  }
+		  filters = {}
+		}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: configEmpty,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.allowed_ip.#", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.denied_protocols.#", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.web_client.#", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.two_factor_protocols.#", "0"),
+				),
+			},
+			{
+				Config:   configEmpty,
+				PlanOnly: true,
+			},
+			{
+				Config: configOmitted,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "filters.allowed_ip"),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "filters.denied_protocols"),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "filters.web_client"),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "filters.two_factor_protocols"),
+				),
+			},
+			{
+				Config:   configOmitted,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourceFilePatternsOrder(t *testing.T) {
+	config := `
+		resource "sftpgo_user" "test" {
+		  username = "test user file patterns"
+		  status = 1
+		  home_dir = "/tmp/testuserfilepatterns"
+		  permissions = {
+		    "/" = "*"
+		  }
+		  filesystem = {
+		    provider = 0
+		  }
+		  filters = {
+		    file_patterns = [
+		      {
+		        path = "/b"
+		        allowed_patterns = ["*.txt", "*.jpg"]
+		      },
+		      {
+		        path = "/a"
+		        denied_patterns = ["*.zip", "*.exe"]
+		        deny_policy = 1
+		      }
+		    ]
+		  }
+		}`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.file_patterns.#", "2"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.file_patterns.0.path", "/b"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.file_patterns.0.allowed_patterns.0", "*.txt"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.file_patterns.1.path", "/a"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.file_patterns.1.deny_policy", "1"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccUserResourceInPlaceUpdate(t *testing.T) {
+	getConfig := func(status int, expirationDate int64) string {
+		var expiration string
+		if expirationDate > 0 {
+			expiration = fmt.Sprintf("expiration_date = %d", expirationDate)
+		}
+		return fmt.Sprintf(`
+			resource "sftpgo_user" "test" {
+			  username = "test user in place"
+			  status = %d
+			  %s
+			  home_dir = "/tmp/testuserinplace"
+			  password = "secret"
+			  permissions = {
+			    "/" = "*"
+			  }
+			  filesystem = {
+			    provider = 1
+			    s3config = {
+			      bucket = "bucket"
+			      region = "us-east-1"
+			      access_key = "key"
+			      access_secret = "access secret"
+			    }
+			  }
+			}`, status, expiration)
+	}
+	expectUpdate := resource.ConfigPlanChecks{
+		PreApply: []plancheck.PlanCheck{
+			plancheck.ExpectResourceAction("sftpgo_user.test", plancheck.ResourceActionUpdate),
+		},
+	}
+	expirationDate := time.Now().Add(24 * time.Hour).UnixMilli()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(1, 0),
+				Check:  resource.TestCheckResourceAttr("sftpgo_user.test", "status", "1"),
+			},
+			{
+				Config:           getConfig(0, 0),
+				ConfigPlanChecks: expectUpdate,
+				Check:            resource.TestCheckResourceAttr("sftpgo_user.test", "status", "0"),
+			},
+			{
+				Config:           getConfig(1, 0),
+				ConfigPlanChecks: expectUpdate,
+				Check:            resource.TestCheckResourceAttr("sftpgo_user.test", "status", "1"),
+			},
+			{
+				Config:           getConfig(1, expirationDate),
+				ConfigPlanChecks: expectUpdate,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "expiration_date",
+						fmt.Sprintf("%d", expirationDate)),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "expiration_date_rfc3339",
+						time.UnixMilli(expirationDate).UTC().Format(time.RFC3339)),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "last_login_rfc3339"),
+				),
+			},
+			{
+				Config:   getConfig(1, expirationDate),
+				PlanOnly: true,
+			},
+			{
+				Config:           getConfig(1, 0),
+				ConfigPlanChecks: expectUpdate,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "expiration_date"),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "expiration_date_rfc3339"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserResourceProviderDefaults(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	getConfig := func(userSettings string) string {
+		return fmt.Sprintf(`
+			provider "sftpgo" {
+			  default_user_status = 0
+			  default_user_role = "default role"
+			}
+
+			resource "sftpgo_role" "default" {
+			  name = "default role"
+			}
+
+			resource "sftpgo_role" "other" {
+			  name = "other role"
+			}
+
+			resource "sftpgo_user" "test" {
+			  username = "test user defaults"
+			  home_dir = "/tmp/testuserdefaults"
+			  permissions = {
+				"/" = "*"
+			  }
+			  depends_on = [sftpgo_role.default, sftpgo_role.other]
+			  %s
+			}`, userSettings)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "status", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "role", "default role"),
+				),
+			},
+			{
+				Config: getConfig(""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// explicit values are never overridden
+			{
+				Config: getConfig(`status = 1
+				  role = "other role"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "status", "1"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "role", "other role"),
+				),
+			},
+			{
+				Config: getConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "status", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "role", "default role"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserResourceMissingStatus(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "sftpgo_user" "test" {
+					  username = "test user no status"
+					  home_dir = "/tmp/testusernostatus"
+					  permissions = {
+						"/" = "*"
+					  }
+					}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("status attribute is required"),
+			},
+		},
+	})
+}
+
+func TestUserPlanDefaultsWithoutProvider(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := fwresource.SchemaResponse{}
+	r := &userResource{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	config := getTestObject(objType, map[string]tftypes.Value{
+		"username": tftypes.NewValue(tftypes.String, "test user"),
+	})
+	// computed attributes not set in the configuration are planned as unknown
+	plan := getTestObject(objType, map[string]tftypes.Value{
+		"username": tftypes.NewValue(tftypes.String, "test user"),
+		"status":   tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+		"role":     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+	modifyPlan := func(r *userResource) *fwresource.ModifyPlanResponse {
+		req := fwresource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config},
+			Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan},
+		}
+		resp := &fwresource.ModifyPlanResponse{Plan: req.Plan}
+		r.ModifyPlan(ctx, req, resp)
+		return resp
+	}
+	getPlannedValues := func(resp *fwresource.ModifyPlanResponse) (types.Int64, types.String) {
+		var plannedStatus types.Int64
+		var plannedRole types.String
+		require.False(t, resp.Plan.GetAttribute(ctx, path.Root("status"), &plannedStatus).HasError())
+		require.False(t, resp.Plan.GetAttribute(ctx, path.Root("role"), &plannedRole).HasError())
+		return plannedStatus, plannedRole
+	}
+	// the provider is not configured, for example because its configuration
+	// has unknown values, so the defaults are not available yet: status and
+	// role are left unknown
+	resp := modifyPlan(r)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	plannedStatus, plannedRole := getPlannedValues(resp)
+	require.True(t, plannedStatus.IsUnknown())
+	require.True(t, plannedRole.IsUnknown())
+	// once the provider is configured, the unknown values are resolved
+	// using the defaults, status is required if there is no default
+	model := userResourceModel{
+		Status: types.Int64Unknown(),
+		Role:   types.StringUnknown(),
+	}
+	diags := r.setUnknownDefaults(&model)
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), "status attribute is required")
+	status := 1
+	diags = (&userResource{defaultUserStatus: &status}).setUnknownDefaults(&model)
+	require.False(t, diags.HasError(), diags)
+	require.Equal(t, int64(1), model.Status.ValueInt64())
+	require.True(t, model.Role.IsNull())
+
+	// the provider is configured, the defaults are applied while planning
+	configured := &userResource{client: &client.Client{}}
+	resp = modifyPlan(configured)
+	require.True(t, resp.Diagnostics.HasError())
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "status attribute is required")
+
+	configured.defaultUserStatus = &status
+	configured.defaultUserRole = "role1"
+	resp = modifyPlan(configured)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	plannedStatus, plannedRole = getPlannedValues(resp)
+	require.Equal(t, int64(1), plannedStatus.ValueInt64())
+	require.Equal(t, "role1", plannedRole.ValueString())
+}
+
+func TestAccUserResourceDeletedOutsideTerraform(t *testing.T) {
+	testAccDeletedOutsideTerraform(t, "sftpgo_user.test", `
+		resource "sftpgo_user" "test" {
+		  username = "test user deleted"
+		  status = 1
+		  home_dir = "/tmp/testuserdeleted"
+		  permissions = {
+			"/" = "*"
+		  }
+		}`, func(c *client.Client) error {
+		return c.DeleteUser(context.Background(), "test user deleted")
+	})
+}
+
+func TestAccUserResourceZeroLimits(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	getConfig := func(dataTransfer string) string {
+		return fmt.Sprintf(`
+		resource "sftpgo_user" "test" {
+		  username = "test user zero limits"
+		  status = 1
+		  home_dir = "/tmp/testuserzerolimits"
+		  permissions = {
+			"/" = "*"
+		  }
+		  quota_size = 0
+		  quota_files = 0
+		  upload_bandwidth = 0
+		  download_bandwidth = 0
+		  %s
+		  filters = {
+			max_upload_file_size = 0
+			bandwidth_limits = [
+			  {
+				sources = ["10.8.0.0/16"]
+				upload_bandwidth = 0
+				download_bandwidth = 100
+			  }
+			]
+		  }
+		}`, dataTransfer)
+	}
+	config := getConfig(`upload_data_transfer = 0
+		  download_data_transfer = 0`)
+	totalConfig := getConfig("total_data_transfer = 0")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "quota_size", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "quota_files", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "upload_bandwidth", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "download_bandwidth", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "upload_data_transfer", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "download_data_transfer", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.max_upload_file_size", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.bandwidth_limits.0.upload_bandwidth", "0"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.bandwidth_limits.0.download_bandwidth", "100"),
+				),
+			},
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: totalConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "total_data_transfer", "0"),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "upload_data_transfer"),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "download_data_transfer"),
+				),
+			},
+			{
+				Config: totalConfig,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccUserResourcePasswordExpiresAt(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	getConfig := func(days int) string {
+		return fmt.Sprintf(`
+		resource "sftpgo_user" "test" {
+		  username = "test user password expiration"
+		  status = 1
+		  password = "Aiv0xa6Ohwah7ucaeh0ohthee"
+		  home_dir = "/tmp/testuserpwdexpiration"
+		  permissions = {
+			"/" = "*"
+		  }
+		  filters = {
+			password_expiration = %d
+		  }
+		}`, days)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(30),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.password_expiration", "30"),
+					resource.TestCheckResourceAttrSet("sftpgo_user.test", "last_password_change"),
+					func(s *terraform.State) error {
+						attrs := s.RootModule().Resources["sftpgo_user.test"].Primary.Attributes
+						lastChange, err := strconv.ParseInt(attrs["last_password_change"], 10, 64)
+						if err != nil {
+							return err
+						}
+						expected := strconv.FormatInt(lastChange+30*24*60*60*1000, 10)
+						if attrs["password_expires_at"] != expected {
+							return fmt.Errorf("unexpected password_expires_at %q, expected %q",
+								attrs["password_expires_at"], expected)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: getConfig(30),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: getConfig(0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filters.password_expiration", "0"),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "password_expires_at"),
+				),
+			},
+			{
+				Config: getConfig(0),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccUserResourceS3RoleARN(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	getConfig := func(staticKeys string) string {
+		return fmt.Sprintf(`
+		resource "sftpgo_user" "test" {
+		  username = "test user s3 role"
+		  status = 1
+		  home_dir = "/tmp/testusers3role"
+		  permissions = {
+			"/" = "*"
+		  }
+		  filesystem = {
+			provider = 1
+			s3config = {
+			  bucket = "bucket"
+			  region = "us-east-1"
+			  role_arn = "arn:aws:iam::123456789012:role/sftpgo"
+			  %s
+			}
+		  }
+		}`, staticKeys)
+	}
+	emptyKeysConfig := getConfig(`access_key = ""
+			  access_secret = ""`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: getConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filesystem.s3config.role_arn",
+						"arn:aws:iam::123456789012:role/sftpgo"),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "filesystem.s3config.access_key"),
+					resource.TestCheckNoResourceAttr("sftpgo_user.test", "filesystem.s3config.access_secret"),
+				),
+			},
+			{
+				Config: getConfig(""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: emptyKeysConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filesystem.s3config.access_key", ""),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filesystem.s3config.access_secret", ""),
+				),
+			},
+			{
+				Config: emptyKeysConfig,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccUserResourceUnknownFilesystem(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// the provider is unknown until terraform_data is created,
+				// it must not be planned or applied as the local filesystem
+				Config: `
+				resource "terraform_data" "fs" {
+				  input = 4
+				}
+
+				resource "sftpgo_user" "test" {
+				  username = "test user"
+				  status      = 1
+				  home_dir    = "/tmp/testuser"
+				  permissions = {
+					"/" = "*"
+				  }
+				  filesystem = {
+					  provider = terraform_data.fs.output
+					  cryptconfig = {
+						passphrase = "test pwd"
+					  }
+				  }
+				}`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue("sftpgo_user.test", tfjsonpath.New("filesystem").AtMapKey("provider")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filesystem.provider", "4"),
+					resource.TestCheckResourceAttr("sftpgo_user.test", "filesystem.cryptconfig.passphrase", "test pwd"),
+				),
+			},
+		},
+	})
+}
+
+func TestUserResourceTimeouts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	apiKey := "key"
+	c, err := client.NewClient(&ts.URL, nil, nil, &apiKey, nil)
+	require.NoError(t, err)
+	r := &userResource{client: c}
+	schemaResp := fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	timeoutsType := objType.AttributeTypes["timeouts"].(tftypes.Object)
+	raw := getTestObject(objType, map[string]tftypes.Value{
+		"username": tftypes.NewValue(tftypes.String, "test user"),
+		"timeouts": getTestObject(timeoutsType, map[string]tftypes.Value{
+			"read": tftypes.NewValue(tftypes.String, "200ms"),
+		}),
+	})
+	req := fwresource.ReadRequest{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: raw},
+	}
+	resp := &fwresource.ReadResponse{State: req.State}
+	start := time.Now()
+	r.Read(ctx, req, resp)
+	require.Less(t, time.Since(start), 4*time.Second)
+	require.True(t, resp.Diagnostics.HasError())
+	require.Contains(t, resp.Diagnostics.Errors()[0].Detail(), context.DeadlineExceeded.Error())
+
+	var state userResourceWithTimeoutsModel
+	require.False(t, req.State.Get(ctx, &state).HasError())
+	timeout, diags := state.Timeouts.Read(ctx, defaultOperationTimeout)
+	require.False(t, diags.HasError())
+	require.Equal(t, 200*time.Millisecond, timeout)
+	timeout, diags = state.Timeouts.Create(ctx, defaultOperationTimeout)
+	require.False(t, diags.HasError())
+	require.Equal(t, defaultOperationTimeout, timeout)
+}
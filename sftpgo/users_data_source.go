@@ -77,6 +77,10 @@ func (d *usersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 							Computed:    true,
 							Description: "Account expiration date as unix timestamp in milliseconds. An expired account cannot login.",
 						},
+						"expiration_date_rfc3339": schema.StringAttribute{
+							Computed:    true,
+							Description: "Account expiration date as RFC 3339 UTC date, derived from expiration_date.",
+						},
 						"password": schema.StringAttribute{
 							Computed:    true,
 							Description: "Password hash saved in the SFTPGo data provider.",
@@ -159,6 +163,10 @@ func (d *usersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 							Computed:    true,
 							Description: "Last login as unix timestamp in milliseconds.",
 						},
+						"last_login_rfc3339": schema.StringAttribute{
+							Computed:    true,
+							Description: "Last login as RFC 3339 UTC date, derived from last_login.",
+						},
 						"created_at": schema.Int64Attribute{
 							Computed:    true,
 							Description: "Creation time as unix timestamp in milliseconds.",
@@ -179,6 +187,10 @@ func (d *usersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 							Computed:    true,
 							Description: "Last password change as unix timestamp in milliseconds.",
 						},
+						"password_expires_at": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Password expiration as unix timestamp in milliseconds, computed from last_password_change and the password_expiration filter. The password_expiration inherited from groups is not taken into account. Not set means no expiration.",
+						},
 						"description": schema.StringAttribute{
 							Computed:    true,
 							Description: "Optional description.",
@@ -193,7 +205,7 @@ func (d *usersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 						},
 						"groups": schema.ListNestedAttribute{
 							Computed:    true,
-							Description: "Groups.",
+							Description: "Groups. The primary group is listed first, then the secondary groups in the order SFTPGo applies them, then the membership only groups.",
 							NestedObject: schema.NestedAttributeObject{
 								Attributes: map[string]schema.Attribute{
 									"name": schema.StringAttribute{
@@ -223,18 +235,18 @@ func (d *usersDataSource) Configure(_ context.Context, req datasource.ConfigureR
 		return
 	}
 
-	d.client = req.ProviderData.(*client.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *usersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state usersDataSourceModel
 
-	users, err := d.client.GetUsers()
+	users, err := d.client.GetUsers(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read SFTPGo Users",
-			err.Error(),
+			parseAPIError(err),
 		)
 		return
 	}
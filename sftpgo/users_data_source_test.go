@@ -15,6 +15,7 @@
 package sftpgo
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -96,23 +97,23 @@ func TestAccUsersDataSource(t *testing.T) {
 		},
 		Password: "Cheiha0ahy7Ieghatiet4phei",
 	}
-	_, err = c.CreateRole(testRole)
+	_, err = c.CreateRole(context.Background(), testRole)
 	require.NoError(t, err)
-	_, err = c.CreateFolder(testFolder)
+	_, err = c.CreateFolder(context.Background(), testFolder)
 	require.NoError(t, err)
-	_, err = c.CreateGroup(testGroup)
+	_, err = c.CreateGroup(context.Background(), testGroup)
 	require.NoError(t, err)
-	_, err = c.CreateUser(user)
+	_, err = c.CreateUser(context.Background(), user)
 	require.NoError(t, err)
 
 	defer func() {
-		err = c.DeleteUser(user.Username)
+		err = c.DeleteUser(context.Background(), user.Username)
 		require.NoError(t, err)
-		err = c.DeleteGroup(testGroup.Name)
+		err = c.DeleteGroup(context.Background(), testGroup.Name)
 		require.NoError(t, err)
-		err = c.DeleteFolder(testFolder.Name)
+		err = c.DeleteFolder(context.Background(), testFolder.Name)
 		require.NoError(t, err)
-		err = c.DeleteRole(testRole.Name)
+		err = c.DeleteRole(context.Background(), testRole.Name)
 		require.NoError(t, err)
 	}()
 
@@ -16,22 +16,48 @@ package sftpgo
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
+	stdpath "path"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/robfig/cron/v3"
 	"github.com/sftpgo/sdk"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
 )
 
+// password hash formats SFTPGo stores as is
+var passwordHashPrefixes = []string{"$2a$", "$argon2id$", "$pbkdf2-sha1$", "$pbkdf2-sha256$", "$pbkdf2-sha512$",
+	"$pbkdf2-b64salt-sha256$", "$1$", "$apr1$", "$5$", "$6$", "$y$", "{MD5}", "{SHA256}", "{SHA512}"}
+
 const (
 	computedSecretDescription = `SFTPGo secret formatted as string: "$<status>$<key>$<additional data length>$<additional data><payload>".`
 	secretDescriptionGeneric  = `If you set a string in SFTPGo secret format, SFTPGo will keep the current secret on updates while the Terraform plan will save your value. Don't do this unless you are sure the values match (e.g because you imported an existing resource).`
+	// number of run times included in the rule next run preview
+	nextRunPreviewSize = 5
+	// timeout for the resource operations not set in the timeouts block
+	defaultOperationTimeout = 20 * time.Minute
 )
 
 func getComputedSchemaForFilesystem() schema.SingleNestedAttribute {
@@ -43,6 +69,10 @@ func getComputedSchemaForFilesystem() schema.SingleNestedAttribute {
 				Computed:    true,
 				Description: "Provider. 0 = local filesystem, 1 = S3 Compatible, 2 = Google Cloud, 3 = Azure Blob, 4 = Local encrypted, 5 = SFTP, 6 = HTTP",
 			},
+			"plain_text_secrets": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Not stored in SFTPGo, always null.",
+			},
 			"osconfig": schema.SingleNestedAttribute{
 				Computed: true,
 				Attributes: map[string]schema.Attribute{
@@ -318,6 +348,10 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 					int64validator.Between(0, 6),
 				},
 			},
+			"plain_text_secrets": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If enabled, the secrets are always sent to SFTPGo as plain text, even if they match the SFTPGo secret format. Use this setting if a plain text secret looks like an SFTPGo secret.",
+			},
 			"osconfig": schema.SingleNestedAttribute{
 				Optional: true,
 				Attributes: map[string]schema.Attribute{
@@ -347,25 +381,35 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 						Optional: true,
 					},
 					"access_key": schema.StringAttribute{
-						Optional: true,
+						Optional:    true,
+						Description: "Static access key, it must be set together with access_secret. Leave both unset to use the credentials from the environment, for example an instance profile.",
 					},
 					"access_secret": schema.StringAttribute{
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text access secret. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"sse_customer_key": schema.StringAttribute{
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text Server-Side encryption key. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"key_prefix": schema.StringAttribute{
 						Optional:    true,
 						Description: `If specified then the SFTPGo user will be restricted to objects starting with the specified prefix. The prefix must not start with "/" and must end with "/"`,
+						Validators: []validator.String{
+							keyPrefixValidator{},
+						},
 					},
 					"role_arn": schema.StringAttribute{
 						Optional:    true,
-						Description: "Optional IAM Role ARN to assume.",
+						Description: "Optional IAM Role ARN to assume. The role is assumed using the static keys, if set, or the credentials from the environment otherwise.",
 					},
 					"session_token": schema.StringAttribute{
 						Optional:    true,
@@ -373,15 +417,23 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 					},
 					"endpoint": schema.StringAttribute{
 						Optional:    true,
-						Description: "The endpoint is generally required for S3 compatible backends. For AWS S3, leave not set to use the default endpoint for the specified region.",
+						Description: "The endpoint is generally required for S3 compatible backends. For AWS S3, leave not set to use the default endpoint for the specified region. S3 compatible backends generally also require force_path_style.",
 					},
 					"storage_class": schema.StringAttribute{
-						Optional:    true,
-						Description: "The storage class to use when storing objects. Leave not set for default.",
+						Optional: true,
+						Description: "The storage class to use when storing objects. Leave not set for default. Valid values: " +
+							strings.Join(client.S3StorageClasses, ", ") + ".",
+						Validators: []validator.String{
+							stringvalidator.OneOf(append([]string{""}, client.S3StorageClasses...)...),
+						},
 					},
 					"acl": schema.StringAttribute{
-						Optional:    true,
-						Description: "The canned ACL to apply to uploaded objects. Not set means the bucket default.",
+						Optional: true,
+						Description: "The canned ACL to apply to uploaded objects. Not set means the bucket default. Valid values: " +
+							strings.Join(client.S3CannedACLs, ", ") + ".",
+						Validators: []validator.String{
+							stringvalidator.OneOf(append([]string{""}, client.S3CannedACLs...)...),
+						},
 					},
 					"upload_part_size": schema.Int64Attribute{
 						Optional:    true,
@@ -427,21 +479,36 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text credentials. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"automatic_credentials": schema.Int64Attribute{
-						Optional: true,
+						Optional:    true,
+						Description: "If set to 1 SFTPGo will use credentials from the environment, for example workload identity. Credentials must not be set in this case.",
 					},
 					"key_prefix": schema.StringAttribute{
 						Optional:    true,
 						Description: `If specified then the SFTPGo user will be restricted to objects starting with the specified prefix. The prefix must not start with "/" and must end with "/"`,
+						Validators: []validator.String{
+							keyPrefixValidator{},
+						},
 					},
 					"storage_class": schema.StringAttribute{
-						Optional:    true,
-						Description: "The storage class to use when storing objects. Leave not set for default.",
+						Optional: true,
+						Description: "The storage class to use when storing objects. Leave not set for default. Valid values: " +
+							strings.Join(client.GCSStorageClasses, ", ") + ".",
+						Validators: []validator.String{
+							stringvalidator.OneOf(append([]string{""}, client.GCSStorageClasses...)...),
+						},
 					},
 					"acl": schema.StringAttribute{
-						Optional:    true,
-						Description: "The ACL to apply to uploaded objects. Not set means the bucket default.",
+						Optional: true,
+						Description: "The ACL to apply to uploaded objects. Not set means the bucket default. Valid values: " +
+							strings.Join(client.GCSPredefinedACLs, ", ") + ".",
+						Validators: []validator.String{
+							stringvalidator.OneOf(append([]string{""}, client.GCSPredefinedACLs...)...),
+						},
 					},
 					"upload_part_size": schema.Int64Attribute{
 						Optional:    true,
@@ -466,11 +533,17 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text account key. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"sas_url": schema.StringAttribute{
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text SAS URL. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"endpoint": schema.StringAttribute{
 						Optional:    true,
@@ -479,6 +552,9 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 					"key_prefix": schema.StringAttribute{
 						Optional:    true,
 						Description: `If specified then the SFTPGo user will be restricted to objects starting with the specified prefix. The prefix must not start with "/" and must end with "/"`,
+						Validators: []validator.String{
+							keyPrefixValidator{},
+						},
 					},
 					"upload_part_size": schema.Int64Attribute{
 						Optional:    true,
@@ -497,11 +573,16 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 						Description: "How many parts are downloaded in parallel. Default: 5.",
 					},
 					"use_emulator": schema.BoolAttribute{
-						Optional: true,
+						Optional:    true,
+						Description: `If enabled, the endpoint must be set and include the protocol, for example "http://127.0.0.1:10000".`,
 					},
 					"access_tier": schema.StringAttribute{
-						Optional:    true,
-						Description: "Blob Access Tier. Not set means the container default.",
+						Optional: true,
+						Description: "Blob Access Tier. Not set means the container default. Valid values: " +
+							strings.Join(client.AzBlobAccessTiers, ", ") + ".",
+						Validators: []validator.String{
+							stringvalidator.OneOf(append([]string{""}, client.AzBlobAccessTiers...)...),
+						},
 					},
 				},
 			},
@@ -512,6 +593,9 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text passphrase. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"read_buffer_size": schema.Int64Attribute{
 						Optional:    true,
@@ -534,7 +618,7 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 				Attributes: map[string]schema.Attribute{
 					"endpoint": schema.StringAttribute{
 						Required:    true,
-						Description: "SFTP endpoint as host:port. Port is always required.",
+						Description: "SFTP endpoint as host:port. If the port is omitted, SFTPGo uses 22.",
 						Validators: []validator.String{
 							sftpEndPointValidator{},
 						},
@@ -546,21 +630,34 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text password. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"private_key": schema.StringAttribute{
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text private key. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"key_passphrase": schema.StringAttribute{
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text passphrase for the private key. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"fingerprints": schema.ListAttribute{
 						ElementType: types.StringType,
 						Optional:    true,
 						Description: "SHA256 fingerprints to validate when connecting to the external SFTP server. If not set any host key will be accepted: this is a security risk.",
+						Validators: []validator.List{
+							emptyFingerprintsValidator{},
+							listvalidator.ValueStringsAre(fingerprintValidator{}),
+						},
 					},
 					"prefix": schema.StringAttribute{
 						Required:    true,
@@ -593,11 +690,17 @@ func getSchemaForFilesystem() schema.SingleNestedAttribute {
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text password. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"api_key": schema.StringAttribute{
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Plain text API key. " + secretDescriptionGeneric,
+						Validators: []validator.String{
+							secretFormatValidator{},
+						},
 					},
 					"skip_tls_verify": schema.BoolAttribute{
 						Optional: true,
@@ -635,11 +738,11 @@ func getComputedSchemaForVirtualFolders() schema.ListNestedAttribute {
 				},
 				"quota_size": schema.Int64Attribute{
 					Computed:    true,
-					Description: "Maximum size allowed as bytes. Not set means unlimited, -1 included in user quota",
+					Description: "Maximum size allowed as bytes. 0 means unlimited, -1 included in user quota.",
 				},
 				"quota_files": schema.Int64Attribute{
 					Computed:    true,
-					Description: "Maximum number of files allowed. Not set means unlimited, -1 included in user quota",
+					Description: "Maximum number of files allowed. 0 means unlimited, -1 included in user quota.",
 				},
 				"used_quota_size": schema.Int64Attribute{
 					Computed:    true,
@@ -661,52 +764,64 @@ func getComputedSchemaForVirtualFolders() schema.ListNestedAttribute {
 
 func getSchemaForVirtualFolders() schema.ListNestedAttribute {
 	return schema.ListNestedAttribute{
-		Optional: true,
-		NestedObject: schema.NestedAttributeObject{
-			Attributes: map[string]schema.Attribute{
-				"name": schema.StringAttribute{
-					Required:    true,
-					Description: "Unique folder name",
-				},
-				"virtual_path": schema.StringAttribute{
-					Required:    true,
-					Description: "The folder will be available on this path.",
-				},
-				"quota_size": schema.Int64Attribute{
-					Required:    true,
-					Description: "Maximum size allowed as bytes. Not set means unlimited, -1 included in user quota",
-				},
-				"quota_files": schema.Int64Attribute{
-					Required:    true,
-					Description: "Maximum number of files allowed. Not set means unlimited, -1 included in user quota",
-				},
-				"mapped_path": schema.StringAttribute{
-					Optional:    true,
-					Computed:    true,
-					Description: "Absolute path to a local directory. This is the folder root path for local storage provider. For non-local filesystems it will store temporary files.",
-				},
-				"description": schema.StringAttribute{
-					Optional:    true,
-					Computed:    true,
-					Description: "Optional description.",
-				},
-				"used_quota_size": schema.Int64Attribute{
-					Computed:    true,
-					Optional:    true,
-					Description: "Used quota as bytes.",
-				},
-				"used_quota_files": schema.Int64Attribute{
-					Computed:    true,
-					Optional:    true,
-					Description: "Used quota as number of files.",
-				},
-				"last_quota_update": schema.Int64Attribute{
-					Computed:    true,
-					Optional:    true,
-					Description: "Last quota update as unix timestamp in milliseconds",
-				},
-				"filesystem": getComputedSchemaForFilesystem(),
+		Optional:     true,
+		NestedObject: getVirtualFolderNestedObject(),
+	}
+}
+
+func getSetSchemaForVirtualFolders() schema.SetNestedAttribute {
+	return schema.SetNestedAttribute{
+		Optional:     true,
+		Description:  "Virtual folders. The order is not relevant.",
+		NestedObject: getVirtualFolderNestedObject(),
+	}
+}
+
+func getVirtualFolderNestedObject() schema.NestedAttributeObject {
+	return schema.NestedAttributeObject{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Unique folder name",
+			},
+			"virtual_path": schema.StringAttribute{
+				Required:    true,
+				Description: "The folder will be available on this path.",
+			},
+			"quota_size": schema.Int64Attribute{
+				Required:    true,
+				Description: "Maximum size allowed as bytes. 0 means unlimited, -1 included in user quota.",
 			},
+			"quota_files": schema.Int64Attribute{
+				Required:    true,
+				Description: "Maximum number of files allowed. 0 means unlimited, -1 included in user quota.",
+			},
+			"mapped_path": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Absolute path to a local directory. This is the folder root path for local storage provider. For non-local filesystems it will store temporary files.",
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Optional description.",
+			},
+			"used_quota_size": schema.Int64Attribute{
+				Computed:    true,
+				Optional:    true,
+				Description: "Used quota as bytes.",
+			},
+			"used_quota_files": schema.Int64Attribute{
+				Computed:    true,
+				Optional:    true,
+				Description: "Used quota as number of files.",
+			},
+			"last_quota_update": schema.Int64Attribute{
+				Computed:    true,
+				Optional:    true,
+				Description: "Last quota update as unix timestamp in milliseconds",
+			},
+			"filesystem": getComputedSchemaForFilesystem(),
 		},
 	}
 }
@@ -900,18 +1015,25 @@ func getComputedSchemaForUserFilters(onlyBase bool) schema.SingleNestedAttribute
 
 func getSchemaForUserFilters(onlyBase bool) schema.SingleNestedAttribute {
 	result := schema.SingleNestedAttribute{
-		Optional: true,
-		Computed: true,
+		Optional:    true,
+		Computed:    true,
+		Description: "Additional restrictions. SFTPGo does not store empty lists: an empty list is equivalent to an unset one and both are kept as configured.",
 		Attributes: map[string]schema.Attribute{
 			"allowed_ip": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
 				Description: `Only connections from these IP/Mask are allowed. IP/Mask must be in CIDR notation as defined in RFC 4632 and RFC 4291 for example "192.0.2.0/24" or "2001:db8::/32"`,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(cidrValidator{}),
+				},
 			},
 			"denied_ip": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
 				Description: "Connections from these IP/Mask are allowed. Denied rules will be evaluated before allowed ones.",
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(cidrValidator{}),
+				},
 			},
 			"denied_login_methods": schema.ListAttribute{
 				ElementType: types.StringType,
@@ -934,7 +1056,8 @@ func getSchemaForUserFilters(onlyBase bool) schema.SingleNestedAttribute {
 				},
 			},
 			"file_patterns": schema.ListNestedAttribute{
-				Optional: true,
+				Optional:    true,
+				Description: "Filters based on shell patterns. The order of the paths and of the patterns is not significant.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"path": schema.StringAttribute{
@@ -960,7 +1083,10 @@ func getSchemaForUserFilters(onlyBase bool) schema.SingleNestedAttribute {
 			},
 			"max_upload_file_size": schema.Int64Attribute{
 				Optional:    true,
-				Description: "Max size allowed for a single upload. Unset means no limit.",
+				Description: `Max size allowed for a single upload as bytes. Unset means no limit. Use the parse_size provider function to set a human readable size, for example provider::sftpgo::parse_size("100MB").`,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
 			},
 			"tls_username": schema.StringAttribute{
 				Optional:    true,
@@ -1012,7 +1138,10 @@ func getSchemaForUserFilters(onlyBase bool) schema.SingleNestedAttribute {
 						"sources": schema.ListAttribute{
 							ElementType: types.StringType,
 							Required:    true,
-							Description: `Source networks in CIDR notation as defined in RFC 4632 and RFC 4291 for example "192.0.2.0/24" or "2001:db8::/32". The limit applies if the defined networks contain the client IP.`,
+							Description: `Source networks in CIDR notation as defined in RFC 4632 and RFC 4291 for example "192.0.2.0/24" or "2001:db8::/32". The limit applies if the defined networks contain the client IP. If the networks of different entries overlap, the first matching entry applies.`,
+							Validators: []validator.List{
+								listvalidator.ValueStringsAre(cidrValidator{}),
+							},
 						},
 						"upload_bandwidth": schema.Int64Attribute{
 							Optional:    true,
@@ -1031,7 +1160,10 @@ func getSchemaForUserFilters(onlyBase bool) schema.SingleNestedAttribute {
 			},
 			"start_directory": schema.StringAttribute{
 				Optional:    true,
-				Description: `Alternate starting directory. If not set, the default is "/". This option is supported for SFTP/SCP, FTP and HTTP (WebClient/REST API) protocols. Relative paths will use this directory as base.`,
+				Description: `Alternate starting directory as absolute virtual path, for example "/data". If not set, the default is "/". This option is supported for SFTP/SCP, FTP and HTTP (WebClient/REST API) protocols. Relative paths will use this directory as base.`,
+				Validators: []validator.String{
+					absoluteVirtualPathValidator{},
+				},
 			},
 			"two_factor_protocols": schema.ListAttribute{
 				ElementType: types.StringType,
@@ -1101,7 +1233,7 @@ func getSchemaForUserFilters(onlyBase bool) schema.SingleNestedAttribute {
 	result.Attributes["tls_certs"] = schema.ListAttribute{
 		ElementType: types.StringType,
 		Optional:    true,
-		Description: "TLS certificates for mutual authentication. If provided will be checked before TLS username.",
+		Description: "TLS certificates for mutual authentication. If provided will be checked before TLS username. Certificates are compared ignoring line endings and surrounding white spaces.",
 		Validators: []validator.List{
 			listvalidator.UniqueValues(),
 		},
@@ -1117,42 +1249,969 @@ func getSchemaForUserFilters(onlyBase bool) schema.SingleNestedAttribute {
 	return result
 }
 
-func preserveFsConfigPlanFields(ctx context.Context, fsPlan, fsState filesystem) (types.Object, diag.Diagnostics) {
+// sizeUnits maps the supported size units to their multiplier.
+var sizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"kb":  1000,
+	"mb":  1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"tb":  1000 * 1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// parseSize converts a human readable size, such as "100MB", to bytes.
+func parseSize(size string) (int64, error) {
+	value := strings.TrimSpace(size)
+	idx := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	unit := ""
+	if idx >= 0 {
+		unit = strings.ToLower(strings.TrimSpace(value[idx:]))
+		value = value[:idx]
+	}
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unsupported unit %q", size, unit)
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", size, err)
+	}
+	result := number * float64(multiplier)
+	if result >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q: value too large", size)
+	}
+	return int64(result), nil
+}
+
+// parseAPIError returns a readable description for errors returned by the
+// SFTPGo client. If SFTPGo returned a JSON error, its error and message are
+// used instead of the raw response body.
+func parseAPIError(err error) string {
+	apiErr := client.GetAPIError(err)
+	if apiErr == nil {
+		return err.Error()
+	}
+	msg := apiErr.Error
+	if apiErr.Message != "" && apiErr.Message != apiErr.Error {
+		if msg != "" {
+			msg += ": "
+		}
+		msg += apiErr.Message
+	}
+	return fmt.Sprintf("%s (status code: %d)", msg, apiErr.StatusCode)
+}
+
+// createOrAdopt creates an object using create. The client does not retry
+// non idempotent requests, so if retries are enabled and create fails with a
+// transport error it is retried here, up to the configured maximum retries.
+// If a retry reports that the object already exists, it was probably created
+// by a previous attempt whose response was lost: the existing object is
+// returned using get and adopted is true. The caller must then check that
+// the object matches the plan using checkAdoptedState.
+// Any other error, including a conflict on the first attempt, is returned
+// as is.
+func createOrAdopt[T any](ctx context.Context, c *client.Client, create, get func() (*T, error),
+) (obj *T, adopted bool, err error) {
+	retried, err := c.RetryOnTransportError(ctx, func() error {
+		var createErr error
+		obj, createErr = create()
+		return createErr
+	})
+	if !retried || !client.IsConflict(err) {
+		return obj, false, err
+	}
+	existing, err := get()
+	if err != nil {
+		return nil, false, fmt.Errorf("the object already exists but it cannot be read: %s", parseAPIError(err))
+	}
+	return existing, true, nil
+}
+
+// checkAdoptedState returns an error if the state built from an adopted
+// object does not match the known values in plan. Secrets not returned by
+// SFTPGo are copied from the plan, so they cannot be checked and a warning
+// listing them is added.
+func checkAdoptedState(ctx context.Context, plan tfsdk.Plan, state any, objectType, name string) diag.Diagnostics {
+	adopted := tfsdk.State{
+		Schema: plan.Schema,
+		Raw:    tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil),
+	}
+	diags := adopted.Set(ctx, state)
+	if diags.HasError() {
+		return diags
+	}
+	diffs, err := plan.Raw.Diff(adopted.Raw)
+	if err != nil {
+		diags.AddError(
+			"Error creating "+objectType,
+			fmt.Sprintf("Could not compare the existing %s %q with the plan: %v", objectType, name, err),
+		)
+		return diags
+	}
+	var mismatches []string
+	for _, d := range diffs {
+		if d.Value1 == nil || !d.Value1.IsFullyKnown() {
+			continue
+		}
+		if p := d.Path.String(); !contains(mismatches, p) {
+			mismatches = append(mismatches, p)
+		}
+	}
+	if len(mismatches) > 0 {
+		diags.AddError(
+			"Error creating "+objectType,
+			fmt.Sprintf("Could not create %s: %q already exists and does not match the configuration, "+
+				"differing attributes: %s. Remove it or import it.", objectType, name, strings.Join(mismatches, ", ")),
+		)
+		return diags
+	}
+	if secrets := getSensitivePlanValues(ctx, plan); len(secrets) > 0 {
+		diags.AddWarning(
+			"Adopted Object Secrets Not Checked",
+			fmt.Sprintf("The existing %s %q was adopted, the following sensitive attributes could not be "+
+				"compared with the configuration: %s. Change them and apply again to be sure they are "+
+				"set as configured.", objectType, name, strings.Join(secrets, ", ")),
+		)
+	}
+	tflog.Info(ctx, "adopted existing "+objectType, map[string]any{"name": name})
+	return diags
+}
+
+// getSensitivePlanValues returns the sorted paths of the sensitive attributes
+// set in plan.
+func getSensitivePlanValues(ctx context.Context, plan tfsdk.Plan) []string {
+	var paths []string
+	_ = tftypes.Walk(plan.Raw, func(p *tftypes.AttributePath, v tftypes.Value) (bool, error) {
+		if v.IsNull() || !v.IsKnown() {
+			return false, nil
+		}
+		attr, err := plan.Schema.AttributeAtTerraformPath(ctx, p)
+		if err == nil && attr.IsSensitive() {
+			paths = append(paths, p.String())
+			return false, nil
+		}
+		return true, nil
+	})
+	slices.Sort(paths)
+	return paths
+}
+
+// normalizeSFTPEndpoint adds the default port 22 to the specified endpoint,
+// if missing, as SFTPGo does.
+func normalizeSFTPEndpoint(endpoint string) string {
+	var addrErr *net.AddrError
+	if _, _, err := net.SplitHostPort(endpoint); errors.As(err, &addrErr) && addrErr.Err == "missing port in address" {
+		return endpoint + ":22"
+	}
+	return endpoint
+}
+
+// preserveFsConfigPlanFields preserves the secrets from fsPlan in fsState,
+// SFTPGo returns them encrypted, see preserveSecret. After an import there
+// are no planned secrets, so the encrypted ones are kept: they are in the
+// SFTPGo secret format and SFTPGo keeps the current secrets if they are sent
+// back as is.
+// If normalizeEndpoint is true, the SFTP endpoint from fsPlan is also kept
+// if it matches the one returned by SFTPGo once the default port is added.
+func preserveFsConfigPlanFields(ctx context.Context, fsPlan, fsState filesystem, normalizeEndpoint bool,
+) (types.Object, diag.Diagnostics) {
+	fsState.PlainTextSecrets = fsPlan.PlainTextSecrets
 	switch sdk.FilesystemProvider(fsState.Provider.ValueInt64()) {
 	case sdk.S3FilesystemProvider:
 		if fsPlan.S3Config != nil {
-			fsState.S3Config.AccessSecret = fsPlan.S3Config.AccessSecret
-			fsState.S3Config.SSECustomerKey = fsPlan.S3Config.SSECustomerKey
+			fsState.S3Config.AccessSecret = preserveSecret(fsPlan.S3Config.AccessSecret, fsState.S3Config.AccessSecret)
+			fsState.S3Config.SSECustomerKey = preserveSecret(fsPlan.S3Config.SSECustomerKey,
+				fsState.S3Config.SSECustomerKey)
+			// empty strings, for example from unset variables, are returned as null
+			fsState.S3Config.AccessKey = preserveEmptyString(fsPlan.S3Config.AccessKey, fsState.S3Config.AccessKey)
+			fsState.S3Config.RoleARN = preserveEmptyString(fsPlan.S3Config.RoleARN, fsState.S3Config.RoleARN)
+			fsState.S3Config.SessionToken = preserveEmptyString(fsPlan.S3Config.SessionToken,
+				fsState.S3Config.SessionToken)
 		}
 	case sdk.GCSFilesystemProvider:
-		if fsPlan.GCSConfig != nil {
-			fsState.GCSConfig.Credentials = fsPlan.GCSConfig.Credentials
+		if fsPlan.GCSConfig != nil && fsPlan.GCSConfig.AutomaticCredentials.ValueInt64() <= 0 {
+			fsState.GCSConfig.Credentials = preserveSecret(fsPlan.GCSConfig.Credentials, fsState.GCSConfig.Credentials)
 		}
 	case sdk.AzureBlobFilesystemProvider:
 		if fsPlan.AzBlobConfig != nil {
-			fsState.AzBlobConfig.AccountKey = fsPlan.AzBlobConfig.AccountKey
-			fsState.AzBlobConfig.SASURL = fsPlan.AzBlobConfig.SASURL
+			fsState.AzBlobConfig.AccountKey = preserveSecret(fsPlan.AzBlobConfig.AccountKey,
+				fsState.AzBlobConfig.AccountKey)
+			fsState.AzBlobConfig.SASURL = preserveSecret(fsPlan.AzBlobConfig.SASURL, fsState.AzBlobConfig.SASURL)
 		}
 	case sdk.CryptedFilesystemProvider:
 		if fsPlan.CryptConfig != nil {
-			fsState.CryptConfig.Passphrase = fsPlan.CryptConfig.Passphrase
+			fsState.CryptConfig.Passphrase = preserveSecret(fsPlan.CryptConfig.Passphrase,
+				fsState.CryptConfig.Passphrase)
 		}
 	case sdk.SFTPFilesystemProvider:
 		if fsPlan.SFTPConfig != nil {
-			fsState.SFTPConfig.Password = fsPlan.SFTPConfig.Password
-			fsState.SFTPConfig.PrivateKey = fsPlan.SFTPConfig.PrivateKey
-			fsState.SFTPConfig.KeyPassphrase = fsPlan.SFTPConfig.KeyPassphrase
+			fsState.SFTPConfig.Password = preserveSecret(fsPlan.SFTPConfig.Password, fsState.SFTPConfig.Password)
+			fsState.SFTPConfig.PrivateKey = preserveSecret(fsPlan.SFTPConfig.PrivateKey, fsState.SFTPConfig.PrivateKey)
+			fsState.SFTPConfig.KeyPassphrase = preserveSecret(fsPlan.SFTPConfig.KeyPassphrase,
+				fsState.SFTPConfig.KeyPassphrase)
+			if normalizeEndpoint && fsState.SFTPConfig != nil &&
+				normalizeSFTPEndpoint(fsPlan.SFTPConfig.Endpoint.ValueString()) == fsState.SFTPConfig.Endpoint.ValueString() {
+				fsState.SFTPConfig.Endpoint = fsPlan.SFTPConfig.Endpoint
+			}
 		}
 	case sdk.HTTPFilesystemProvider:
 		if fsPlan.HTTPConfig != nil {
-			fsState.HTTPConfig.Password = fsPlan.HTTPConfig.Password
-			fsState.HTTPConfig.APIKey = fsPlan.HTTPConfig.APIKey
+			fsState.HTTPConfig.Password = preserveSecret(fsPlan.HTTPConfig.Password, fsState.HTTPConfig.Password)
+			fsState.HTTPConfig.APIKey = preserveSecret(fsPlan.HTTPConfig.APIKey, fsState.HTTPConfig.APIKey)
 		}
 	}
 
 	return types.ObjectValueFrom(ctx, fsState.getTFAttributes(), fsState)
 }
 
+// fsProviderConfigs maps each filesystem provider to its configuration block.
+var fsProviderConfigs = []string{"osconfig", "s3config", "gcsconfig", "azblobconfig", "cryptconfig", "sftpconfig",
+	"httpconfig"}
+
+// validateFilesystemConfig checks that the populated filesystem configuration
+// block matches the provider. SFTPGo ignores the configuration for other
+// providers and returns an error if the required one is missing.
+func validateFilesystemConfig(ctx context.Context, config tfsdk.Config, fsPath path.Path) diag.Diagnostics {
+	var provider types.Int64
+	diags := config.GetAttribute(ctx, fsPath.AtName("provider"), &provider)
+	if diags.HasError() || provider.IsNull() || provider.IsUnknown() {
+		return diags
+	}
+	if provider.ValueInt64() < 0 || provider.ValueInt64() >= int64(len(fsProviderConfigs)) {
+		return diags
+	}
+	expected := fsProviderConfigs[provider.ValueInt64()]
+
+	var unexpected []string
+	// the local filesystem configuration is optional
+	hasExpected := provider.ValueInt64() == int64(sdk.LocalFilesystemProvider)
+	for _, name := range fsProviderConfigs {
+		var block types.Object
+		d := config.GetAttribute(ctx, fsPath.AtName(name), &block)
+		diags.Append(d...)
+		if d.HasError() {
+			return diags
+		}
+		if block.IsNull() {
+			continue
+		}
+		if name == expected {
+			hasExpected = true
+			continue
+		}
+		if !block.IsUnknown() {
+			unexpected = append(unexpected, name)
+		}
+	}
+	if hasExpected && len(unexpected) == 0 {
+		return diags
+	}
+
+	detail := fmt.Sprintf("Filesystem provider %d requires the %q configuration block", provider.ValueInt64(), expected)
+	if provider.ValueInt64() == int64(sdk.LocalFilesystemProvider) {
+		detail = fmt.Sprintf("Filesystem provider %d only supports the %q configuration block", provider.ValueInt64(),
+			expected)
+	}
+	if len(unexpected) > 0 {
+		detail += fmt.Sprintf(", remove the configuration for other providers: %s", strings.Join(unexpected, ", "))
+	}
+	diags.AddAttributeError(
+		fsPath,
+		"Invalid Filesystem Configuration",
+		detail+".",
+	)
+	return diags
+}
+
+// validateGCSCredentials checks that the GCS credentials are not set if
+// automatic credentials are enabled, SFTPGo ignores them in this case.
+func validateGCSCredentials(ctx context.Context, config tfsdk.Config, fsPath path.Path) diag.Diagnostics {
+	var automaticCredentials types.Int64
+	gcsPath := fsPath.AtName("gcsconfig")
+	diags := config.GetAttribute(ctx, gcsPath.AtName("automatic_credentials"), &automaticCredentials)
+	if diags.HasError() || automaticCredentials.IsNull() || automaticCredentials.IsUnknown() ||
+		automaticCredentials.ValueInt64() <= 0 {
+		return diags
+	}
+	var credentials types.String
+	diags.Append(config.GetAttribute(ctx, gcsPath.AtName("credentials"), &credentials)...)
+	if diags.HasError() || credentials.IsNull() || credentials.IsUnknown() || credentials.ValueString() == "" {
+		return diags
+	}
+	diags.AddAttributeError(
+		gcsPath.AtName("credentials"),
+		"Invalid GCS Configuration",
+		"credentials cannot be set together with automatic_credentials, SFTPGo uses the credentials from the "+
+			"environment if automatic credentials are enabled.",
+	)
+	return diags
+}
+
+// validateS3Credentials checks that the S3 static keys are set together.
+// Without static keys SFTPGo uses the credentials from the environment, for
+// example an instance profile, to access the bucket or to assume role_arn.
+// A warning is added if both static keys and role_arn are set, the role is
+// assumed using the static keys, and this is rarely what is intended.
+func validateS3Credentials(ctx context.Context, config tfsdk.Config, fsPath path.Path) diag.Diagnostics {
+	var accessKey, accessSecret, roleARN types.String
+	s3Path := fsPath.AtName("s3config")
+	diags := config.GetAttribute(ctx, s3Path.AtName("access_key"), &accessKey)
+	diags.Append(config.GetAttribute(ctx, s3Path.AtName("access_secret"), &accessSecret)...)
+	diags.Append(config.GetAttribute(ctx, s3Path.AtName("role_arn"), &roleARN)...)
+	if diags.HasError() || accessKey.IsUnknown() || accessSecret.IsUnknown() || roleARN.IsUnknown() {
+		return diags
+	}
+	hasAccessKey := accessKey.ValueString() != ""
+	hasAccessSecret := accessSecret.ValueString() != ""
+	if hasAccessKey != hasAccessSecret {
+		attrPath := s3Path.AtName("access_secret")
+		if !hasAccessKey {
+			attrPath = s3Path.AtName("access_key")
+		}
+		diags.AddAttributeError(
+			attrPath,
+			"Invalid S3 Configuration",
+			"access_key and access_secret must be set together. Leave both unset to use the credentials from "+
+				"the environment, for example an instance profile, optionally together with role_arn.",
+		)
+		return diags
+	}
+	if hasAccessKey && roleARN.ValueString() != "" {
+		diags.AddAttributeWarning(
+			s3Path.AtName("role_arn"),
+			"S3 Static Keys With Role ARN",
+			"Both static keys and role_arn are set, the static keys are used to assume the role. "+
+				"Leave access_key and access_secret unset to assume the role using the credentials from the environment.",
+		)
+	}
+	return diags
+}
+
+// isAWSS3Endpoint returns true if the specified endpoint points to AWS S3.
+func isAWSS3Endpoint(endpoint string) bool {
+	host := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		host = u.Hostname()
+	} else if h, _, err := net.SplitHostPort(endpoint); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	return strings.HasSuffix(host, ".amazonaws.com") || strings.HasSuffix(host, ".amazonaws.com.cn")
+}
+
+// validateS3Endpoint adds a warning if a custom, non AWS, endpoint is set
+// without force_path_style. S3 compatible backends, for example MinIO or
+// Ceph, generally require path-style addressing and fail with confusing
+// errors otherwise. Some gateways support virtual-hosted style, so this is
+// only a warning.
+func validateS3Endpoint(ctx context.Context, config tfsdk.Config, fsPath path.Path) diag.Diagnostics {
+	var endpoint types.String
+	var forcePathStyle types.Bool
+	s3Path := fsPath.AtName("s3config")
+	diags := config.GetAttribute(ctx, s3Path.AtName("endpoint"), &endpoint)
+	diags.Append(config.GetAttribute(ctx, s3Path.AtName("force_path_style"), &forcePathStyle)...)
+	if diags.HasError() || endpoint.IsUnknown() || forcePathStyle.IsUnknown() {
+		return diags
+	}
+	if endpoint.ValueString() == "" || forcePathStyle.ValueBool() || isAWSS3Endpoint(endpoint.ValueString()) {
+		return diags
+	}
+	diags.AddAttributeWarning(
+		s3Path.AtName("endpoint"),
+		"S3 Endpoint Without Path-Style Addressing",
+		fmt.Sprintf("The custom endpoint %q is used without force_path_style. S3 compatible backends, for "+
+			"example MinIO or Ceph, generally require path-style addressing, set force_path_style to true "+
+			"unless your backend supports virtual-hosted style requests.", endpoint.ValueString()),
+	)
+	return diags
+}
+
+// fsSkipTLSVerifyConfigs lists the filesystem configuration blocks with a
+// skip_tls_verify attribute.
+var fsSkipTLSVerifyConfigs = []string{"s3config", "httpconfig"}
+
+// validateSkipTLSVerify adds a warning for each filesystem configuration with
+// TLS certificate verification disabled. It is often enabled for testing and
+// then forgotten.
+func validateSkipTLSVerify(ctx context.Context, config tfsdk.Config, fsPath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, name := range fsSkipTLSVerifyConfigs {
+		var skipTLSVerify types.Bool
+		attrPath := fsPath.AtName(name).AtName("skip_tls_verify")
+		diags.Append(config.GetAttribute(ctx, attrPath, &skipTLSVerify)...)
+		if diags.HasError() {
+			return diags
+		}
+		if skipTLSVerify.ValueBool() {
+			diags.AddAttributeWarning(
+				attrPath,
+				"TLS Certificate Verification Disabled",
+				"The server certificate is not verified, the connection is susceptible to man-in-the-middle "+
+					"attacks. This should be used only for testing, configure a certificate signed by a trusted "+
+					"CA on the server instead.",
+			)
+		}
+	}
+	return diags
+}
+
+// validateAzBlobEmulator checks that an endpoint including the protocol is
+// set if the Azure Blob emulator is used, SFTPGo rejects it otherwise.
+func validateAzBlobEmulator(ctx context.Context, config tfsdk.Config, fsPath path.Path) diag.Diagnostics {
+	var useEmulator types.Bool
+	azPath := fsPath.AtName("azblobconfig")
+	diags := config.GetAttribute(ctx, azPath.AtName("use_emulator"), &useEmulator)
+	if diags.HasError() || !useEmulator.ValueBool() {
+		return diags
+	}
+	var endpoint types.String
+	diags.Append(config.GetAttribute(ctx, azPath.AtName("endpoint"), &endpoint)...)
+	if diags.HasError() || endpoint.IsUnknown() {
+		return diags
+	}
+	value := endpoint.ValueString()
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return diags
+	}
+	diags.AddAttributeError(
+		azPath.AtName("endpoint"),
+		"Invalid Azure Blob Configuration",
+		fmt.Sprintf("If use_emulator is enabled the endpoint must be set and include the protocol, for example "+
+			"\"http://127.0.0.1:10000\", got: %q", value),
+	)
+	return diags
+}
+
+// validateDataTransferConfig checks that the total data transfer is not set
+// together with the upload/download ones, SFTPGo ignores the individual values
+// if a total data transfer is set.
+func validateDataTransferConfig(ctx context.Context, config tfsdk.Config, basePath path.Path) diag.Diagnostics {
+	var total types.Int64
+	diags := config.GetAttribute(ctx, basePath.AtName("total_data_transfer"), &total)
+	if diags.HasError() || total.IsNull() || total.IsUnknown() || total.ValueInt64() <= 0 {
+		return diags
+	}
+	for _, name := range []string{"upload_data_transfer", "download_data_transfer"} {
+		var value types.Int64
+		d := config.GetAttribute(ctx, basePath.AtName(name), &value)
+		diags.Append(d...)
+		if d.HasError() {
+			return diags
+		}
+		if value.IsNull() || value.IsUnknown() || value.ValueInt64() <= 0 {
+			continue
+		}
+		diags.AddAttributeError(
+			basePath.AtName("total_data_transfer"),
+			"Invalid Data Transfer Configuration",
+			fmt.Sprintf("total_data_transfer cannot be set together with %s, set a total data transfer or "+
+				"the individual values for uploads and downloads.", name),
+		)
+		return diags
+	}
+	return diags
+}
+
+// tlsCertificateLoginMethods are the login methods that use the TLS
+// certificate mapped by tls_username.
+var tlsCertificateLoginMethods = []string{"TLSCertificate", "TLSCertificate+password"}
+
+// validateTLSUsername adds a warning if tls_username is set but all the TLS
+// certificate login methods are denied, the certificate to username mapping
+// is never used in this case.
+func validateTLSUsername(ctx context.Context, config tfsdk.Config, filtersPath path.Path) diag.Diagnostics {
+	var tlsUsername types.String
+	diags := config.GetAttribute(ctx, filtersPath.AtName("tls_username"), &tlsUsername)
+	if diags.HasError() || tlsUsername.IsUnknown() || tlsUsername.ValueString() == "" ||
+		tlsUsername.ValueString() == "None" {
+		return diags
+	}
+	var deniedLoginMethods types.List
+	diags.Append(config.GetAttribute(ctx, filtersPath.AtName("denied_login_methods"), &deniedLoginMethods)...)
+	if diags.HasError() || deniedLoginMethods.IsNull() || deniedLoginMethods.IsUnknown() {
+		return diags
+	}
+	var methods []types.String
+	diags.Append(deniedLoginMethods.ElementsAs(ctx, &methods, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	var deniedTLSMethods int
+	for _, method := range methods {
+		if contains(tlsCertificateLoginMethods, method.ValueString()) {
+			deniedTLSMethods++
+		}
+	}
+	if deniedTLSMethods < len(tlsCertificateLoginMethods) {
+		return diags
+	}
+	diags.AddAttributeWarning(
+		filtersPath.AtName("tls_username"),
+		"Unused TLS Username",
+		fmt.Sprintf("tls_username is set but the TLS certificate login methods %v are denied, the certificate "+
+			"attribute is never used as username. Remove tls_username or allow at least one TLS certificate "+
+			"login method.", tlsCertificateLoginMethods),
+	)
+	return diags
+}
+
+// validateBandwidthLimits adds a warning if source networks of different
+// bandwidth_limits entries overlap. SFTPGo applies the first entry with a
+// network containing the client IP, so the later entry is ignored for the
+// overlapping addresses.
+func validateBandwidthLimits(ctx context.Context, config tfsdk.Config, filtersPath path.Path) diag.Diagnostics {
+	limitsPath := filtersPath.AtName("bandwidth_limits")
+	var limits types.List
+	diags := config.GetAttribute(ctx, limitsPath, &limits)
+	if diags.HasError() || limits.IsNull() || limits.IsUnknown() {
+		return diags
+	}
+	type source struct {
+		entry   int
+		value   string
+		network netip.Prefix
+	}
+	var sources []source
+	for idx, elem := range limits.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok || obj.IsNull() || obj.IsUnknown() {
+			continue
+		}
+		var limit bandwidthLimit
+		diags.Append(obj.As(ctx, &limit, basetypes.ObjectAsOptions{
+			UnhandledNullAsEmpty:    true,
+			UnhandledUnknownAsEmpty: true,
+		})...)
+		if diags.HasError() {
+			return diags
+		}
+		if limit.Sources.IsNull() || limit.Sources.IsUnknown() {
+			continue
+		}
+		for _, val := range limit.Sources.Elements() {
+			s, ok := val.(types.String)
+			if !ok || s.IsNull() || s.IsUnknown() {
+				continue
+			}
+			// invalid networks are reported by the attribute validator
+			network, err := netip.ParsePrefix(s.ValueString())
+			if err != nil {
+				continue
+			}
+			network = network.Masked()
+			for _, prev := range sources {
+				if prev.entry == idx || !prev.network.Overlaps(network) {
+					continue
+				}
+				diags.AddAttributeWarning(
+					limitsPath.AtListIndex(idx).AtName("sources"),
+					"Overlapping Bandwidth Limits",
+					fmt.Sprintf("The source network %q overlaps with %q defined in bandwidth_limits[%d]. "+
+						"SFTPGo applies the first entry containing the client IP, so this entry does not apply "+
+						"to the clients in both networks.", s.ValueString(), prev.value, prev.entry),
+				)
+			}
+			sources = append(sources, source{entry: idx, value: s.ValueString(), network: network})
+		}
+	}
+	return diags
+}
+
+// getIPListEntryImportID returns the IP or network to import for the
+// specified IP list. The import identifier is the IP or network, optionally
+// prefixed by the list type, for example "1/192.168.1.0/24". The entry must
+// exist in the specified list.
+func getIPListEntryImportID(ctx context.Context, c *client.Client, id string, listType int, listName string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	ipOrNet := id
+	// an IP or network never starts with an integer followed by "/"
+	if prefix, value, ok := strings.Cut(id, "/"); ok {
+		if idType, err := strconv.Atoi(prefix); err == nil {
+			if idType != listType {
+				diags.AddError(
+					"Invalid Import Identifier",
+					fmt.Sprintf("The import identifier %q refers to the IP list type %d, the %s has type %d. "+
+						"Use \"<ipornet>\" or \"%d/<ipornet>\".", id, idType, listName, listType, listType),
+				)
+				return "", diags
+			}
+			ipOrNet = value
+		}
+	}
+	if ipOrNet == "" {
+		diags.AddError(
+			"Invalid Import Identifier",
+			fmt.Sprintf("The import identifier %q does not include an IP or network.", id),
+		)
+		return "", diags
+	}
+
+	entry, err := c.GetIPListEntry(ctx, listType, ipOrNet)
+	if err != nil {
+		if client.IsNotFound(err) {
+			diags.AddError(
+				"Cannot Import Non-Existent IP List Entry",
+				fmt.Sprintf("The entry %q does not exist in the %s.", ipOrNet, listName),
+			)
+			return "", diags
+		}
+		diags.AddError(
+			"Error Reading SFTPGo "+listName+" entry",
+			fmt.Sprintf("Could not read SFTPGo %s entry %s: %s", listName, ipOrNet, parseAPIError(err)),
+		)
+		return "", diags
+	}
+	if entry.Type != listType {
+		diags.AddError(
+			"Unexpected IP List Entry Type",
+			fmt.Sprintf("The entry %q has type %d, the %s has type %d.", ipOrNet, entry.Type, listName, listType),
+		)
+		return "", diags
+	}
+	return ipOrNet, diags
+}
+
+// getAdminAPIKeyImportID returns the ID of the admin API key to import. The
+// import identifier is the generated key ID or "<admin>:<name>", in the latter
+// case the ID is looked up and the name must identify a single key.
+func getAdminAPIKeyImportID(ctx context.Context, c *client.Client, id string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// generated key IDs never include ":"
+	admin, name, ok := strings.Cut(id, ":")
+	if !ok {
+		return id, diags
+	}
+	if admin == "" || name == "" {
+		diags.AddError(
+			"Invalid Import Identifier",
+			fmt.Sprintf("The import identifier %q must be the API key ID or \"<admin>:<name>\".", id),
+		)
+		return "", diags
+	}
+
+	apiKeys, err := c.GetAPIKeys(ctx)
+	if err != nil {
+		diags.AddError(
+			"Unable to Read SFTPGo API keys",
+			"Could not read SFTPGo API keys: "+parseAPIError(err),
+		)
+		return "", diags
+	}
+	var ids []string
+	for _, apiKey := range apiKeys {
+		if apiKey.Scope == client.APIKeyScopeAdmin && apiKey.Admin == admin && apiKey.Name == name {
+			ids = append(ids, apiKey.ID)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		diags.AddError(
+			"Cannot Import Non-Existent API Key",
+			fmt.Sprintf("The admin %q has no API key named %q.", admin, name),
+		)
+		return "", diags
+	case 1:
+		return ids[0], diags
+	default:
+		diags.AddError(
+			"Ambiguous Import Identifier",
+			fmt.Sprintf("The admin %q has %d API keys named %q, import one of them by ID: %s.", admin, len(ids),
+				name, strings.Join(ids, ", ")),
+		)
+		return "", diags
+	}
+}
+
+// validateTwoFactorProtocols adds a warning for each protocol that requires
+// two factor authentication but is also denied, the requirement has no effect.
+func validateTwoFactorProtocols(ctx context.Context, config tfsdk.Config, filtersPath path.Path) diag.Diagnostics {
+	var twoFactorProtocols, deniedProtocols types.List
+	diags := config.GetAttribute(ctx, filtersPath.AtName("two_factor_protocols"), &twoFactorProtocols)
+	diags.Append(config.GetAttribute(ctx, filtersPath.AtName("denied_protocols"), &deniedProtocols)...)
+	if diags.HasError() || twoFactorProtocols.IsNull() || twoFactorProtocols.IsUnknown() ||
+		deniedProtocols.IsNull() || deniedProtocols.IsUnknown() {
+		return diags
+	}
+	var required, denied []types.String
+	diags.Append(twoFactorProtocols.ElementsAs(ctx, &required, false)...)
+	diags.Append(deniedProtocols.ElementsAs(ctx, &denied, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	for _, protocol := range required {
+		if protocol.IsUnknown() || !contains(denied, protocol) {
+			continue
+		}
+		diags.AddAttributeWarning(
+			filtersPath.AtName("two_factor_protocols"),
+			"Two Factor Authentication Required For A Denied Protocol",
+			fmt.Sprintf("Protocol %q requires two factor authentication but it is also denied in denied_protocols, "+
+				"logins using this protocol are always rejected.", protocol.ValueString()),
+		)
+	}
+	return diags
+}
+
+// checkGroupsExist returns an error listing the specified groups that do not
+// exist in SFTPGo.
+func checkGroupsExist(ctx context.Context, c *client.Client, names []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var missing []string
+	for _, name := range names {
+		_, err := c.GetGroup(ctx, name)
+		if err == nil {
+			continue
+		}
+		if !client.IsNotFound(err) {
+			diags.AddError(
+				"Error Reading SFTPGo Group",
+				"Could not read SFTPGo Group "+name+": "+parseAPIError(err),
+			)
+			return diags
+		}
+		missing = append(missing, name)
+	}
+	if len(missing) > 0 {
+		diags.AddAttributeError(
+			path.Root("groups"),
+			"Missing SFTPGo Groups",
+			fmt.Sprintf("The following groups do not exist: %s.", strings.Join(missing, ", ")),
+		)
+	}
+	return diags
+}
+
+// checkVirtualFoldersPaths returns a warning for each virtual folder mounted
+// on the root directory or overlapping a previously defined virtual folder.
+// SFTPGo rejects these configurations, so we warn as early as possible.
+func checkVirtualFoldersPaths(virtualPaths []types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	cleanedPaths := make([]string, len(virtualPaths))
+	for idx, virtualPath := range virtualPaths {
+		if virtualPath.IsNull() || virtualPath.IsUnknown() {
+			continue
+		}
+		attrPath := path.Root("virtual_folders").AtListIndex(idx).AtName("virtual_path")
+		cleanedPaths[idx] = stdpath.Clean("/" + virtualPath.ValueString())
+		if cleanedPaths[idx] == "/" {
+			diags.AddAttributeWarning(
+				attrPath,
+				"Virtual folder mounted on the root directory",
+				fmt.Sprintf("The virtual path %q matches the root of the user home directory. "+
+					"A virtual folder cannot be mounted on \"/\", use a sub directory or configure the user filesystem instead.",
+					virtualPath.ValueString()),
+			)
+			continue
+		}
+		for i := 0; i < idx; i++ {
+			if cleanedPaths[i] == "" || cleanedPaths[i] == "/" {
+				continue
+			}
+			if isVirtualPathOverlapped(cleanedPaths[i], cleanedPaths[idx]) {
+				diags.AddAttributeWarning(
+					attrPath,
+					"Overlapping virtual folders",
+					fmt.Sprintf("The virtual path %q overlaps with the virtual path %q. "+
+						"Nested or duplicated virtual folders are not allowed.",
+						virtualPath.ValueString(), virtualPaths[i].ValueString()),
+				)
+				break
+			}
+		}
+	}
+
+	return diags
+}
+
+// isVirtualPathOverlapped reports whether two cleaned virtual paths are
+// equal or one is nested inside the other.
+func isVirtualPathOverlapped(path1, path2 string) bool {
+	if path1 == path2 {
+		return true
+	}
+	return strings.HasPrefix(path1, path2+"/") || strings.HasPrefix(path2, path1+"/")
+}
+
+// normalizeSecret removes leading and trailing white spaces from pre-hashed
+// passwords and secrets in SFTPGo format, for example loaded from a file
+// ending with a new line. Plain text secrets are returned as is.
+func normalizeSecret(secret string) string {
+	if trimmed := strings.TrimSpace(secret); isPasswordHash(trimmed) || isSFTPGoSecretFormat(trimmed) {
+		return trimmed
+	}
+	return secret
+}
+
+// isPasswordHash reports whether password is in a hash format supported by SFTPGo.
+func isPasswordHash(password string) bool {
+	for _, prefix := range passwordHashPrefixes {
+		if strings.HasPrefix(password, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getNextScheduledRuns returns up to count run times, in UTC, after from for
+// the specified schedules. SFTPGo runs scheduled rules at minute 0 of the
+// matching hours. Schedules that never match, for example on February 30,
+// have no run times.
+func getNextScheduledRuns(schedules []ruleSchedule, from time.Time, count int) ([]time.Time, error) {
+	parsed := make([]cron.Schedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		spec := fmt.Sprintf("0 %s %s %s %s", schedule.Hours.ValueString(), schedule.DayOfMonth.ValueString(),
+			schedule.Month.ValueString(), schedule.DayOfWeek.ValueString())
+		s, err := cron.ParseStandard(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		parsed = append(parsed, s)
+	}
+
+	next := make([]time.Time, len(parsed))
+	for idx, s := range parsed {
+		next[idx] = s.Next(from.UTC())
+	}
+	var result []time.Time
+	for len(result) < count {
+		// the zero time means that the schedule has no more runs
+		var earliest time.Time
+		for _, t := range next {
+			if !t.IsZero() && (earliest.IsZero() || t.Before(earliest)) {
+				earliest = t
+			}
+		}
+		if earliest.IsZero() {
+			break
+		}
+		result = append(result, earliest)
+		for idx, s := range parsed {
+			if next[idx].Equal(earliest) {
+				next[idx] = s.Next(earliest)
+			}
+		}
+	}
+	return result, nil
+}
+
+// getPublicKeyMaterial returns the key type and the base64 encoded key from
+// a public key in authorized_keys format, comments and white spaces are
+// ignored.
+func getPublicKeyMaterial(key string) string {
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return strings.TrimSpace(key)
+	}
+	return fields[0] + " " + fields[1]
+}
+
+// hasSamePublicKeys reports whether the two lists contain the same public keys,
+// in the same order, compared by key material.
+func hasSamePublicKeys(keys1, keys2 types.List) bool {
+	return hasSameNormalizedStrings(keys1, keys2, getPublicKeyMaterial)
+}
+
+// hasSameTLSCerts reports whether the two lists contain the same PEM
+// encoded certificates, in the same order, compared by normalized content.
+func hasSameTLSCerts(certs1, certs2 types.List) bool {
+	return hasSameNormalizedStrings(certs1, certs2, normalizePEM)
+}
+
+// hasSameNormalizedStrings reports whether the two string lists have the
+// same elements, in the same order, after applying normalize.
+func hasSameNormalizedStrings(list1, list2 types.List, normalize func(string) string) bool {
+	if list1.IsNull() || list1.IsUnknown() || list2.IsNull() || list2.IsUnknown() {
+		return false
+	}
+	elems1 := list1.Elements()
+	elems2 := list2.Elements()
+	if len(elems1) != len(elems2) {
+		return false
+	}
+	for idx := range elems1 {
+		val1, ok1 := elems1[idx].(types.String)
+		val2, ok2 := elems2[idx].(types.String)
+		if !ok1 || !ok2 || val1.IsUnknown() || val2.IsUnknown() {
+			return false
+		}
+		if normalize(val1.ValueString()) != normalize(val2.ValueString()) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizePEM converts CRLF line endings to LF and removes the surrounding
+// white spaces from PEM encoded data.
+func normalizePEM(data string) string {
+	return strings.TrimSpace(strings.ReplaceAll(data, "\r\n", "\n"))
+}
+
+// getPlannedInt64 returns the planned value if known, otherwise the state one.
+// It is used for server maintained values, such as the quota usage, that
+// SFTPGo may update between plan and apply.
+func getPlannedInt64(plan, state types.Int64) types.Int64 {
+	if plan.IsUnknown() {
+		return state
+	}
+	return plan
+}
+
+// getPlannedBool is like getPlannedInt64 for boolean values.
+func getPlannedBool(plan, state types.Bool) types.Bool {
+	if plan.IsUnknown() {
+		return state
+	}
+	return plan
+}
+
+// getFolderQuotaScan returns the scan status and start time for the folder
+// with the specified name. The start time is 0 if no scan is running.
+func getFolderQuotaScan(scans []client.FolderQuotaScan, name string) (types.Bool, types.Int64) {
+	for _, scan := range scans {
+		if scan.Name == name {
+			return types.BoolValue(true), types.Int64Value(scan.StartTime)
+		}
+	}
+	return types.BoolValue(false), types.Int64Value(0)
+}
+
+// isSameSecret reports whether two secrets match. Secrets are compared in
+// constant time.
+func isSameSecret(secret1, secret2 string) bool {
+	return subtle.ConstantTimeCompare([]byte(secret1), []byte(secret2)) == 1
+}
+
+// preserveSecret returns the secret to store in the state given the planned
+// secret and the one returned by SFTPGo. SFTPGo returns secrets encrypted,
+// or omits them, so the planned secret is kept unless both are password
+// hashes: SFTPGo stores them as is and a different hash means the password
+// was changed outside Terraform. Without a planned secret, for example after
+// an import, the returned one is kept.
+func preserveSecret(plan, state types.String) types.String {
+	if plan.IsNull() || plan.IsUnknown() {
+		return state
+	}
+	planSecret := normalizeSecret(plan.ValueString())
+	storedSecret := normalizeSecret(state.ValueString())
+	if isSameSecret(planSecret, storedSecret) {
+		return plan
+	}
+	if isPasswordHash(planSecret) && isPasswordHash(storedSecret) {
+		return types.StringValue(storedSecret)
+	}
+	return plan
+}
+
 // contains reports whether v is present in elems.
 func contains[T comparable](elems []T, v T) bool {
 	for _, s := range elems {
@@ -0,0 +1,680 @@
+// Copyright (C) 2023 Nicola Murino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/sftpgo/sdk"
+	"github.com/stretchr/testify/require"
+
+	"github.com/drakkan/terraform-provider-sftpgo/sftpgo/client"
+)
+
+func TestCheckVirtualFoldersPaths(t *testing.T) {
+	type testCase struct {
+		paths            []types.String
+		expectedWarnings int
+	}
+	tests := map[string]testCase{
+		"empty": {
+			paths:            nil,
+			expectedWarnings: 0,
+		},
+		"valid": {
+			paths:            []types.String{types.StringValue("/vdir1"), types.StringValue("/vdir2")},
+			expectedWarnings: 0,
+		},
+		"similar prefix": {
+			paths:            []types.String{types.StringValue("/vdir"), types.StringValue("/vdir1")},
+			expectedWarnings: 0,
+		},
+		"unknown and null": {
+			paths:            []types.String{types.StringUnknown(), types.StringNull(), types.StringValue("/vdir")},
+			expectedWarnings: 0,
+		},
+		"root": {
+			paths:            []types.String{types.StringValue("/")},
+			expectedWarnings: 1,
+		},
+		"root not cleaned": {
+			paths:            []types.String{types.StringValue("/vdir/..")},
+			expectedWarnings: 1,
+		},
+		"duplicated": {
+			paths:            []types.String{types.StringValue("/vdir"), types.StringValue("/vdir/")},
+			expectedWarnings: 1,
+		},
+		"nested": {
+			paths:            []types.String{types.StringValue("/vdir/sub"), types.StringValue("/vdir")},
+			expectedWarnings: 1,
+		},
+		"root and nested": {
+			paths: []types.String{types.StringValue("/"), types.StringValue("/vdir"),
+				types.StringValue("/vdir/sub")},
+			expectedWarnings: 2,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := checkVirtualFoldersPaths(test.paths)
+			require.False(t, diags.HasError())
+			require.Equal(t, test.expectedWarnings, diags.WarningsCount())
+		})
+	}
+}
+
+func TestIsPasswordHash(t *testing.T) {
+	tests := map[string]bool{
+		"":                    false,
+		"plain text password": false,
+		"$2a$10$tXvOoYqbL7uDyJL5c0yy3u7EvDfbEqrS3sZ4aCb9jW0rkYIqmxNpa": true,
+		"$argon2id$v=19$m=65536,t=1,p=2$c2FsdA$aGFzaA":                 true,
+		"$pbkdf2-sha256$150000$c2FsdA$aGFzaA":                          true,
+		"$6$salt$hash":                                                 true,
+		"{SHA256}aGFzaA==":                                             true,
+		"2a$10$missing-dollar":                                         false,
+	}
+
+	for password, expected := range tests {
+		require.Equal(t, expected, isPasswordHash(password), password)
+	}
+}
+
+func TestIsSameSecret(t *testing.T) {
+	type testCase struct {
+		secret1  string
+		secret2  string
+		expected bool
+	}
+	tests := map[string]testCase{
+		"empty": {
+			expected: true,
+		},
+		"match": {
+			secret1:  "$2a$10$tXvOoYqbL7uDyJL5c0yy3u7EvDfbEqrS3sZ4aCb9jW0rkYIqmxNpa",
+			secret2:  "$2a$10$tXvOoYqbL7uDyJL5c0yy3u7EvDfbEqrS3sZ4aCb9jW0rkYIqmxNpa",
+			expected: true,
+		},
+		"trailing new line": {
+			secret1:  "$2a$10$tXvOoYqbL7uDyJL5c0yy3u7EvDfbEqrS3sZ4aCb9jW0rkYIqmxNpa\n",
+			secret2:  "$2a$10$tXvOoYqbL7uDyJL5c0yy3u7EvDfbEqrS3sZ4aCb9jW0rkYIqmxNpa",
+			expected: false,
+		},
+		"mismatch": {
+			secret1:  "$2a$10$tXvOoYqbL7uDyJL5c0yy3u7EvDfbEqrS3sZ4aCb9jW0rkYIqmxNpa",
+			secret2:  "$2a$10$tXvOoYqbL7uDyJL5c0yy3u7EvDfbEqrS3sZ4aCb9jW0rkYIqmxNpb",
+			expected: false,
+		},
+		"different length": {
+			secret1:  "secret",
+			secret2:  "secret1",
+			expected: false,
+		},
+		"case sensitive": {
+			secret1:  "Secret",
+			secret2:  "secret",
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		require.Equal(t, test.expected, isSameSecret(test.secret1, test.secret2), name)
+	}
+}
+
+func TestNormalizeSecret(t *testing.T) {
+	hash := "$2a$10$tXvOoYqbL7uDyJL5c0yy3u7EvDfbEqrS3sZ4aCb9jW0rkYIqmxNpa"
+	require.Equal(t, hash, normalizeSecret(hash))
+	require.Equal(t, hash, normalizeSecret(hash+"\n"))
+	require.Equal(t, hash, normalizeSecret(" "+hash+"\r\n"))
+	secret := "$AES-256-GCM$key$4$datapayload"
+	require.Equal(t, secret, normalizeSecret(secret+"\n"))
+	// plain text secrets are sent as is
+	require.Equal(t, " password\n", normalizeSecret(" password\n"))
+	require.Equal(t, "", normalizeSecret(""))
+}
+
+func TestPreserveSecret(t *testing.T) {
+	hash1 := "$2a$10$tXvOoYqbL7uDyJL5c0yy3u7EvDfbEqrS3sZ4aCb9jW0rkYIqmxNpa"
+	hash2 := "$2a$10$tXvOoYqbL7uDyJL5c0yy3u7EvDfbEqrS3sZ4aCb9jW0rkYIqmxNpb"
+	encrypted := "$AES-256-GCM$key$4$datapayload"
+	type testCase struct {
+		plan     types.String
+		state    types.String
+		expected types.String
+	}
+	tests := map[string]testCase{
+		"imported": {
+			plan:     types.StringNull(),
+			state:    types.StringValue(encrypted),
+			expected: types.StringValue(encrypted),
+		},
+		"plain text": {
+			plan:     types.StringValue("password"),
+			state:    types.StringValue(encrypted),
+			expected: types.StringValue("password"),
+		},
+		"same encrypted secret": {
+			plan:     types.StringValue(encrypted + "\n"),
+			state:    types.StringValue(encrypted),
+			expected: types.StringValue(encrypted + "\n"),
+		},
+		"same hash": {
+			plan:     types.StringValue(hash1 + "\n"),
+			state:    types.StringValue(hash1),
+			expected: types.StringValue(hash1 + "\n"),
+		},
+		"hash changed outside Terraform": {
+			plan:     types.StringValue(hash1),
+			state:    types.StringValue(hash2),
+			expected: types.StringValue(hash2),
+		},
+		"plain text password and hash": {
+			plan:     types.StringValue("password"),
+			state:    types.StringValue(hash1),
+			expected: types.StringValue("password"),
+		},
+	}
+
+	for name, test := range tests {
+		require.Equal(t, test.expected, preserveSecret(test.plan, test.state), name)
+	}
+}
+
+func TestGetNextScheduledRuns(t *testing.T) {
+	getSchedule := func(hour, dayOfWeek, dayOfMonth, month string) ruleSchedule {
+		return ruleSchedule{
+			Hours:      types.StringValue(hour),
+			DayOfWeek:  types.StringValue(dayOfWeek),
+			DayOfMonth: types.StringValue(dayOfMonth),
+			Month:      types.StringValue(month),
+		}
+	}
+	// Wednesday
+	from := time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC)
+
+	type testCase struct {
+		schedules []ruleSchedule
+		expected  []string
+	}
+	tests := map[string]testCase{
+		"every hour": {
+			schedules: []ruleSchedule{getSchedule("*", "*", "*", "*")},
+			expected: []string{"2024-01-10T11:00:00Z", "2024-01-10T12:00:00Z", "2024-01-10T13:00:00Z",
+				"2024-01-10T14:00:00Z", "2024-01-10T15:00:00Z"},
+		},
+		"daily at midnight": {
+			schedules: []ruleSchedule{getSchedule("0", "*", "*", "*")},
+			expected: []string{"2024-01-11T00:00:00Z", "2024-01-12T00:00:00Z", "2024-01-13T00:00:00Z",
+				"2024-01-14T00:00:00Z", "2024-01-15T00:00:00Z"},
+		},
+		"step and range": {
+			schedules: []ruleSchedule{getSchedule("8-16/4", "mon-fri", "*", "*")},
+			expected: []string{"2024-01-10T12:00:00Z", "2024-01-10T16:00:00Z", "2024-01-11T08:00:00Z",
+				"2024-01-11T12:00:00Z", "2024-01-11T16:00:00Z"},
+		},
+		"day of month or day of week": {
+			schedules: []ruleSchedule{getSchedule("6", "0", "1,15", "*")},
+			expected: []string{"2024-01-14T06:00:00Z", "2024-01-15T06:00:00Z", "2024-01-21T06:00:00Z",
+				"2024-01-28T06:00:00Z", "2024-02-01T06:00:00Z"},
+		},
+		"multiple schedules": {
+			schedules: []ruleSchedule{getSchedule("12", "*", "*", "*"), getSchedule("11", "*", "*", "feb")},
+			expected: []string{"2024-01-10T12:00:00Z", "2024-01-11T12:00:00Z", "2024-01-12T12:00:00Z",
+				"2024-01-13T12:00:00Z", "2024-01-14T12:00:00Z"},
+		},
+		"yearly": {
+			schedules: []ruleSchedule{getSchedule("0", "*", "29", "2")},
+			expected: []string{"2024-02-29T00:00:00Z", "2028-02-29T00:00:00Z", "2032-02-29T00:00:00Z",
+				"2036-02-29T00:00:00Z", "2040-02-29T00:00:00Z"},
+		},
+		"never": {
+			schedules: []ruleSchedule{getSchedule("0", "*", "30", "2")},
+		},
+		"no schedules": {},
+	}
+
+	for name, test := range tests {
+		runs, err := getNextScheduledRuns(test.schedules, from, nextRunPreviewSize)
+		require.NoError(t, err, name)
+		var actual []string
+		for _, run := range runs {
+			actual = append(actual, run.Format(time.RFC3339))
+		}
+		require.Equal(t, test.expected, actual, name)
+	}
+
+	_, err := getNextScheduledRuns([]ruleSchedule{getSchedule("24", "*", "*", "*")}, from, nextRunPreviewSize)
+	require.Error(t, err)
+}
+
+func TestCheckGroupsExist(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/groups/group1":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"name":"group1"}`))
+		case "/api/v2/groups/group3":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error":"Internal Server Error","message":"database unavailable"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	apiKey := "apikey"
+	c, err := client.NewClient(&ts.URL, nil, nil, &apiKey, nil)
+	require.NoError(t, err)
+
+	diags := checkGroupsExist(context.Background(), c, []string{"group1"})
+	require.False(t, diags.HasError(), "unexpected error: %v", diags)
+
+	diags = checkGroupsExist(context.Background(), c, []string{"group1", "group2", "missing"})
+	require.Equal(t, 1, diags.ErrorsCount())
+	require.Contains(t, diags.Errors()[0].Detail(), "group2, missing")
+
+	diags = checkGroupsExist(context.Background(), c, []string{"group3"})
+	require.Equal(t, 1, diags.ErrorsCount())
+	require.Equal(t, "Error Reading SFTPGo Group", diags.Errors()[0].Summary())
+	require.Contains(t, diags.Errors()[0].Detail(), "Internal Server Error: database unavailable (status code: 500)")
+}
+
+func TestGetIPListEntryImportID(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/iplists/1/192.168.1.0/24":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"ipornet":"192.168.1.0/24","type":1}`))
+		case "/api/v2/iplists/2/192.168.1.0/24":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"ipornet":"192.168.1.0/24","type":3}`))
+		case "/api/v2/iplists/1/2001:db8::1":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"ipornet":"2001:db8::1","type":1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	apiKey := "apikey"
+	c, err := client.NewClient(&ts.URL, nil, nil, &apiKey, nil)
+	require.NoError(t, err)
+
+	for _, id := range []string{"192.168.1.0/24", "1/192.168.1.0/24"} {
+		ipOrNet, diags := getIPListEntryImportID(context.Background(), c, id, 1, "allow list")
+		require.False(t, diags.HasError(), "unexpected error: %v", diags)
+		require.Equal(t, "192.168.1.0/24", ipOrNet)
+	}
+	ipOrNet, diags := getIPListEntryImportID(context.Background(), c, "2001:db8::1", 1, "allow list")
+	require.False(t, diags.HasError(), "unexpected error: %v", diags)
+	require.Equal(t, "2001:db8::1", ipOrNet)
+
+	_, diags = getIPListEntryImportID(context.Background(), c, "2/192.168.1.0/24", 1, "allow list")
+	require.Equal(t, 1, diags.ErrorsCount())
+	require.Equal(t, "Invalid Import Identifier", diags.Errors()[0].Summary())
+
+	_, diags = getIPListEntryImportID(context.Background(), c, "1/", 1, "allow list")
+	require.Equal(t, 1, diags.ErrorsCount())
+	require.Equal(t, "Invalid Import Identifier", diags.Errors()[0].Summary())
+
+	_, diags = getIPListEntryImportID(context.Background(), c, "10.0.0.0/8", 1, "allow list")
+	require.Equal(t, 1, diags.ErrorsCount())
+	require.Equal(t, "Cannot Import Non-Existent IP List Entry", diags.Errors()[0].Summary())
+
+	_, diags = getIPListEntryImportID(context.Background(), c, "192.168.1.0/24", 2, "defender list")
+	require.Equal(t, 1, diags.ErrorsCount())
+	require.Equal(t, "Unexpected IP List Entry Type", diags.Errors()[0].Summary())
+}
+
+func TestGetAdminAPIKeyImportID(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/apikeys" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[{"id":"id1","name":"key","scope":1,"admin":"admin"},
+			{"id":"id2","name":"key","scope":2,"user":"admin"},
+			{"id":"id3","name":"dup","scope":1,"admin":"admin"},
+			{"id":"id4","name":"dup","scope":1,"admin":"admin"}]`))
+	}))
+	defer ts.Close()
+
+	apiKey := "apikey"
+	c, err := client.NewClient(&ts.URL, nil, nil, &apiKey, nil)
+	require.NoError(t, err)
+
+	id, diags := getAdminAPIKeyImportID(context.Background(), c, "id1")
+	require.False(t, diags.HasError(), "unexpected error: %v", diags)
+	require.Equal(t, "id1", id)
+	id, diags = getAdminAPIKeyImportID(context.Background(), c, "admin:key")
+	require.False(t, diags.HasError(), "unexpected error: %v", diags)
+	require.Equal(t, "id1", id)
+
+	_, diags = getAdminAPIKeyImportID(context.Background(), c, "admin:")
+	require.Equal(t, 1, diags.ErrorsCount())
+	require.Equal(t, "Invalid Import Identifier", diags.Errors()[0].Summary())
+
+	_, diags = getAdminAPIKeyImportID(context.Background(), c, "other:key")
+	require.Equal(t, 1, diags.ErrorsCount())
+	require.Equal(t, "Cannot Import Non-Existent API Key", diags.Errors()[0].Summary())
+
+	_, diags = getAdminAPIKeyImportID(context.Background(), c, "admin:dup")
+	require.Equal(t, 1, diags.ErrorsCount())
+	require.Equal(t, "Ambiguous Import Identifier", diags.Errors()[0].Summary())
+	require.Contains(t, diags.Errors()[0].Detail(), "id3, id4")
+}
+
+func TestHasSamePublicKeys(t *testing.T) {
+	key := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMLhC8Kr6mD7o8fUdpQuFj0jOSzRy6JwZnlD+UyGUUwm"
+	otherKey := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFuUV9LXy6rDlxPD7Ta3/WEgm+yZuRXfZEY5vVcCxlWy"
+	getList := func(keys ...string) types.List {
+		var elems []attr.Value
+		for _, k := range keys {
+			elems = append(elems, types.StringValue(k))
+		}
+		return types.ListValueMust(types.StringType, elems)
+	}
+
+	require.True(t, hasSamePublicKeys(getList(key), getList(key)))
+	require.True(t, hasSamePublicKeys(getList(key), getList(key+" user@host")))
+	require.True(t, hasSamePublicKeys(getList(key+" comment\n"), getList("  "+key+"   other comment")))
+	require.True(t, hasSamePublicKeys(getList(key, otherKey), getList(key+" c1", otherKey+" c2")))
+	require.False(t, hasSamePublicKeys(getList(key), getList(otherKey)))
+	require.False(t, hasSamePublicKeys(getList(key, otherKey), getList(otherKey, key)))
+	require.False(t, hasSamePublicKeys(getList(key), getList(key, otherKey)))
+	require.False(t, hasSamePublicKeys(types.ListNull(types.StringType), getList(key)))
+	require.False(t, hasSamePublicKeys(types.ListUnknown(types.StringType), getList(key)))
+}
+
+func TestHasSameTLSCerts(t *testing.T) {
+	cert := "-----BEGIN CERTIFICATE-----\nMIICHTCCAaKgAwIBAgIU\n-----END CERTIFICATE-----"
+	otherCert := "-----BEGIN CERTIFICATE-----\nMIIBtjCCAVygAwIBAgIU\n-----END CERTIFICATE-----"
+	getList := func(certs ...string) types.List {
+		var elems []attr.Value
+		for _, c := range certs {
+			elems = append(elems, types.StringValue(c))
+		}
+		return types.ListValueMust(types.StringType, elems)
+	}
+
+	require.Equal(t, cert, normalizePEM(" "+strings.ReplaceAll(cert, "\n", "\r\n")+"\r\n"))
+	require.True(t, hasSameTLSCerts(getList(cert), getList(cert+"\n")))
+	require.True(t, hasSameTLSCerts(getList(strings.ReplaceAll(cert, "\n", "\r\n")), getList(cert)))
+	require.True(t, hasSameTLSCerts(getList(cert, otherCert), getList(cert+"\n", "\n"+otherCert)))
+	require.False(t, hasSameTLSCerts(getList(cert), getList(otherCert)))
+	require.False(t, hasSameTLSCerts(getList(cert, otherCert), getList(otherCert, cert)))
+	require.False(t, hasSameTLSCerts(getList(cert), getList(cert, otherCert)))
+	require.False(t, hasSameTLSCerts(types.ListNull(types.StringType), getList(cert)))
+}
+
+func TestNormalizeSFTPEndpoint(t *testing.T) {
+	require.Equal(t, "127.0.0.1:22", normalizeSFTPEndpoint("127.0.0.1"))
+	require.Equal(t, "127.0.0.1:2022", normalizeSFTPEndpoint("127.0.0.1:2022"))
+	require.Equal(t, "sftp.example.com:22", normalizeSFTPEndpoint("sftp.example.com"))
+	require.Equal(t, "::1", normalizeSFTPEndpoint("::1"))
+	require.Equal(t, "[::1]:22", normalizeSFTPEndpoint("[::1]"))
+	require.Equal(t, "[::1]:2022", normalizeSFTPEndpoint("[::1]:2022"))
+
+	getFs := func(endpoint string) filesystem {
+		return filesystem{
+			Provider: types.Int64Value(int64(sdk.SFTPFilesystemProvider)),
+			SFTPConfig: &sftpFsConfig{
+				Endpoint:     types.StringValue(endpoint),
+				Username:     types.StringValue("user"),
+				Fingerprints: types.ListNull(types.StringType),
+			},
+		}
+	}
+	getEndpoint := func(obj types.Object) string {
+		var fs filesystem
+		diags := obj.As(context.Background(), &fs, basetypes.ObjectAsOptions{})
+		require.False(t, diags.HasError())
+		return fs.SFTPConfig.Endpoint.ValueString()
+	}
+	// SFTPGo adds the default port
+	obj, diags := preserveFsConfigPlanFields(context.Background(), getFs("127.0.0.1"), getFs("127.0.0.1:22"), true)
+	require.False(t, diags.HasError())
+	require.Equal(t, "127.0.0.1", getEndpoint(obj))
+	obj, diags = preserveFsConfigPlanFields(context.Background(), getFs("127.0.0.1"), getFs("127.0.0.1:22"), false)
+	require.False(t, diags.HasError())
+	require.Equal(t, "127.0.0.1:22", getEndpoint(obj))
+	// a different endpoint is never preserved
+	obj, diags = preserveFsConfigPlanFields(context.Background(), getFs("127.0.0.1"), getFs("127.0.0.1:2022"), true)
+	require.False(t, diags.HasError())
+	require.Equal(t, "127.0.0.1:2022", getEndpoint(obj))
+}
+
+func TestParseAPIError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/folders/validation":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"Validation error: mapped_path must be absolute","message":""}`))
+		case "/api/v2/folders/message":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":"Conflict","message":"folder is referenced"}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("Bad Gateway"))
+		}
+	}))
+	defer ts.Close()
+
+	apiKey := "apikey"
+	c, err := client.NewClient(&ts.URL, nil, nil, &apiKey, nil)
+	require.NoError(t, err)
+
+	_, err = c.GetFolder(context.Background(), "validation")
+	require.Equal(t, "Validation error: mapped_path must be absolute (status code: 400)", parseAPIError(err))
+	_, err = c.GetFolder(context.Background(), "message")
+	require.Equal(t, "Conflict: folder is referenced (status code: 409)", parseAPIError(err))
+	_, err = c.GetFolder(context.Background(), "other")
+	require.Equal(t, err.Error(), parseAPIError(err))
+	require.Contains(t, parseAPIError(err), "Bad Gateway")
+}
+
+func TestCreateOrAdopt(t *testing.T) {
+	created := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/roles":
+			if created {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"error":"the role already exists"}`))
+				return
+			}
+			// the role is created but the connection is closed before
+			// sending the response
+			created = true
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/users":
+			w.WriteHeader(http.StatusBadGateway)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/roles/role1" && created:
+			_, _ = w.Write([]byte(`{"name":"role1","description":"desc","created_at":1,"updated_at":2}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	apiKey := "apikey"
+	c, err := client.NewClient(&ts.URL, nil, nil, &apiKey, nil)
+	require.NoError(t, err)
+	create := func() (*client.Role, error) { return c.CreateRole(ctx, client.Role{Name: "role1"}) }
+	get := func() (*client.Role, error) { return c.GetRole(ctx, "role1") }
+
+	// retries are disabled, the transport error is returned as is
+	_, adopted, err := createOrAdopt(ctx, c, create, get)
+	require.True(t, client.IsTransportError(err))
+	require.False(t, adopted)
+	require.True(t, created)
+	// the first attempt fails with a transport error, the retry gets a
+	// conflict and the existing role is adopted
+	created = false
+	c.RetryMax = 1
+	c.RetryWait = 10 * time.Millisecond
+	role, adopted, err := createOrAdopt(ctx, c, create, get)
+	require.NoError(t, err)
+	require.True(t, adopted)
+	require.Equal(t, "desc", role.Description)
+	// a conflict without a previous transport error is returned as is
+	_, adopted, err = createOrAdopt(ctx, c, create, get)
+	require.True(t, client.IsConflict(err))
+	require.False(t, adopted)
+	// unexpected status codes are not retried
+	_, adopted, err = createOrAdopt(ctx, c,
+		func() (*client.User, error) { return c.CreateUser(ctx, client.User{}) },
+		func() (*client.User, error) { return c.GetUser(ctx, "user1") },
+	)
+	require.Error(t, err)
+	require.False(t, client.IsTransportError(err))
+	require.False(t, adopted)
+	// the error is returned if the existing role cannot be read
+	created = false
+	_, adopted, err = createOrAdopt(ctx, c, create, func() (*client.Role, error) { return c.GetRole(ctx, "role2") })
+	require.ErrorContains(t, err, "cannot be read")
+	require.ErrorContains(t, err, "status: 404")
+	require.False(t, adopted)
+
+	var schemaResp resource.SchemaResponse
+	NewRoleResource().Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	var state roleResourceModel
+	diags := state.fromSFTPGo(ctx, role)
+	require.False(t, diags.HasError())
+
+	getPlan := func(description string) tfsdk.Plan {
+		plan := tfsdk.Plan{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		}
+		diags := plan.Set(ctx, roleResourceModel{
+			ID:          types.StringUnknown(),
+			Name:        types.StringValue("role1"),
+			Description: types.StringValue(description),
+			CreatedAt:   types.Int64Unknown(),
+			UpdatedAt:   types.Int64Unknown(),
+			Admins:      types.ListUnknown(types.StringType),
+			Users:       types.ListUnknown(types.StringType),
+		})
+		require.False(t, diags.HasError())
+		return plan
+	}
+	diags = checkAdoptedState(ctx, getPlan("desc"), state, "role", "role1")
+	require.False(t, diags.HasError(), diags)
+	diags = checkAdoptedState(ctx, getPlan("other"), state, "role", "role1")
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), `"description"`)
+}
+
+func TestGetSensitivePlanValues(t *testing.T) {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	NewUserResource().Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	fsType := objType.AttributeTypes["filesystem"].(tftypes.Object)
+	s3Type := fsType.AttributeTypes["s3config"].(tftypes.Object)
+	plan := tfsdk.Plan{
+		Schema: schemaResp.Schema,
+		Raw: getTestObject(objType, map[string]tftypes.Value{
+			"username": tftypes.NewValue(tftypes.String, "user1"),
+			"password": tftypes.NewValue(tftypes.String, "secret"),
+			"filesystem": getTestObject(fsType, map[string]tftypes.Value{
+				"provider": tftypes.NewValue(tftypes.Number, 1),
+				"s3config": getTestObject(s3Type, map[string]tftypes.Value{
+					"bucket":        tftypes.NewValue(tftypes.String, "bucket"),
+					"access_secret": tftypes.NewValue(tftypes.String, "secret"),
+				}),
+			}),
+		}),
+	}
+	require.Equal(t, []string{
+		`AttributeName("filesystem").AttributeName("s3config").AttributeName("access_secret")`,
+		`AttributeName("password")`,
+	}, getSensitivePlanValues(ctx, plan))
+}
+
+func TestGetFolderQuotaScan(t *testing.T) {
+	scans := []client.FolderQuotaScan{
+		{Name: "folder1", StartTime: 1000},
+		{Name: "folder2", StartTime: 2000},
+	}
+	inProgress, startTime := getFolderQuotaScan(scans, "folder2")
+	require.True(t, inProgress.ValueBool())
+	require.Equal(t, int64(2000), startTime.ValueInt64())
+	inProgress, startTime = getFolderQuotaScan(scans, "folder3")
+	require.False(t, inProgress.IsNull())
+	require.False(t, inProgress.ValueBool())
+	require.Equal(t, int64(0), startTime.ValueInt64())
+	inProgress, startTime = getFolderQuotaScan(nil, "folder1")
+	require.False(t, inProgress.ValueBool())
+	require.Equal(t, int64(0), startTime.ValueInt64())
+}
+
+func TestGetUserAgent(t *testing.T) {
+	providerUA := "terraform-provider-sftpgo/" + getVersion()
+	require.Equal(t, "Terraform/1.9.0 (+https://www.terraform.io) "+providerUA, getUserAgent("1.9.0", ""))
+	require.Equal(t, "Terraform/1.9.0 (+https://www.terraform.io) "+providerUA+" team-a", getUserAgent("1.9.0", " team-a "))
+	require.Equal(t, providerUA, getUserAgent("", ""))
+}
+
+func TestParseSize(t *testing.T) {
+	testCases := []struct {
+		size     string
+		expected int64
+	}{
+		{"0", 0},
+		{"1024", 1024},
+		{"512B", 512},
+		{"1KB", 1000},
+		{"1KiB", 1024},
+		{"100MB", 100000000},
+		{"100 mb", 100000000},
+		{"100MiB", 104857600},
+		{"1.5GB", 1500000000},
+		{"2GiB", 2147483648},
+		{"1TB", 1000000000000},
+		{"1TiB", 1099511627776},
+	}
+	for _, tc := range testCases {
+		result, err := parseSize(tc.size)
+		require.NoError(t, err, tc.size)
+		require.Equal(t, tc.expected, result, tc.size)
+	}
+	for _, size := range []string{"", "MB", "-1MB", "10PB", "1.2.3KB", "10000000TiB"} {
+		_, err := parseSize(size)
+		require.Error(t, err, size)
+	}
+}
@@ -16,19 +16,40 @@ package sftpgo
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+var (
+	windowsAbsPathRegex = regexp.MustCompile(`^[a-zA-Z]:[\\/]`)
+	// emailRegex is the email address format defined by the WHATWG HTML standard
+	emailRegex = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?" +
+		"(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+)
+
+// validPermissions defines the permissions supported by SFTPGo
+var validPermissions = []string{"*", "list", "download", "upload", "overwrite", "delete", "delete_files",
+	"delete_dirs", "rename", "rename_files", "rename_dirs", "create_dirs", "create_symlinks", "chmod",
+	"chown", "chtimes", "copy"}
+
 type sftpEndPointValidator struct{}
 
 // Description describes the validation in plain text formatting.
 func (sftpEndPointValidator) Description(_ context.Context) string {
-	return "string must be in the format host:port"
+	return "string must be in the format host:port or host"
 }
 
 // MarkdownDescription describes the validation in Markdown formatting.
@@ -44,7 +65,10 @@ func (v sftpEndPointValidator) ValidateString(ctx context.Context, request valid
 
 	value := request.ConfigValue.ValueString()
 
-	_, _, err := net.SplitHostPort(value)
+	host, _, err := net.SplitHostPort(normalizeSFTPEndpoint(value))
+	if err == nil && host == "" {
+		err = errors.New("missing host")
+	}
 	if err != nil {
 		response.Diagnostics.Append(invalidAttributeSFTPEndPointDiagnostic(
 			request.Path,
@@ -62,3 +86,492 @@ func invalidAttributeSFTPEndPointDiagnostic(path path.Path, description string,
 		fmt.Sprintf("Attribute %s %s, got: %s", path, description, value),
 	)
 }
+
+// localHomeDirValidator requires an absolute home directory for users
+// stored on the local filesystem, encrypted or not.
+type localHomeDirValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (localHomeDirValidator) Description(_ context.Context) string {
+	return "must be an absolute path for local filesystem providers"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v localHomeDirValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v localHomeDirValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var provider types.Int64
+	diags := request.Config.GetAttribute(ctx, path.Root("filesystem").AtName("provider"), &provider)
+	response.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	if provider.IsUnknown() {
+		return
+	}
+	// 0 = local filesystem, 4 = local encrypted
+	if !provider.IsNull() && provider.ValueInt64() != 0 && provider.ValueInt64() != 4 {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+	if !isAbsPath(value) {
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"Invalid Home Directory",
+			fmt.Sprintf("Attribute %s %s, got: %q", request.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+// isAbsPath returns true if the specified path is absolute. Both Unix and
+// Windows paths are accepted, the SFTPGo server OS is not known.
+func isAbsPath(p string) bool {
+	return strings.HasPrefix(p, "/") || strings.HasPrefix(p, `\\`) || windowsAbsPathRegex.MatchString(p)
+}
+
+// permissionsValidator checks that the permissions map keys are absolute
+// virtual paths and the values contain only supported permissions.
+type permissionsValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (permissionsValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("keys must be absolute virtual paths and values comma separated permissions, supported permissions: %s",
+		strings.Join(validPermissions, ", "))
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v permissionsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v permissionsValidator) ValidateMap(ctx context.Context, request validator.MapRequest, response *validator.MapResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var invalidEntries []string
+	for key, val := range request.ConfigValue.Elements() {
+		if !strings.HasPrefix(key, "/") {
+			invalidEntries = append(invalidEntries, fmt.Sprintf("%q: the path must start with \"/\"", key))
+		}
+		perms, ok := val.(types.String)
+		if !ok || perms.IsNull() || perms.IsUnknown() {
+			continue
+		}
+		for _, perm := range strings.Split(perms.ValueString(), ",") {
+			if !slices.Contains(validPermissions, perm) {
+				invalidEntries = append(invalidEntries, fmt.Sprintf("%q: invalid permission %q", key, perm))
+			}
+		}
+	}
+	if len(invalidEntries) == 0 {
+		return
+	}
+	slices.Sort(invalidEntries)
+	response.Diagnostics.AddAttributeError(
+		request.Path,
+		"Invalid Permissions",
+		fmt.Sprintf("Attribute %s %s. Invalid entries:\n%s", request.Path, v.Description(ctx), strings.Join(invalidEntries, "\n")),
+	)
+}
+
+var (
+	monthNames = map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7,
+		"aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}
+	dayOfWeekNames = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+	cronHourField       = cronFieldValidator{min: 0, max: 23}
+	cronDayOfWeekField  = cronFieldValidator{min: 0, max: 6, names: dayOfWeekNames}
+	cronDayOfMonthField = cronFieldValidator{min: 1, max: 31}
+	cronMonthField      = cronFieldValidator{min: 1, max: 12, names: monthNames}
+)
+
+// cronFieldValidator validates a single field of a cron expression. Lists,
+// ranges, steps and wildcards are supported, as for the SFTPGo scheduler.
+type cronFieldValidator struct {
+	min   int
+	max   int
+	names map[string]int
+}
+
+// Description describes the validation in plain text formatting.
+func (v cronFieldValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("must be a valid cron field with values between %d and %d", v.min, v.max)
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v cronFieldValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v cronFieldValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+	if err := v.validate(value); err != nil {
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"Invalid Attribute Value Cron Field",
+			fmt.Sprintf("Attribute %s %s, got: %q: %v", request.Path, v.Description(ctx), value, err),
+		)
+	}
+}
+
+func (v cronFieldValidator) validate(field string) error {
+	if field == "" {
+		return errors.New("empty field")
+	}
+	for _, expr := range strings.Split(field, ",") {
+		rangeAndStep := strings.Split(expr, "/")
+		if len(rangeAndStep) > 2 {
+			return fmt.Errorf("too many slashes in %q", expr)
+		}
+		lowAndHigh := strings.Split(rangeAndStep[0], "-")
+		if len(lowAndHigh) > 2 {
+			return fmt.Errorf("too many hyphens in %q", expr)
+		}
+		if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
+			if len(lowAndHigh) > 1 {
+				return fmt.Errorf("invalid range %q", expr)
+			}
+		} else {
+			low, err := v.parseValue(lowAndHigh[0])
+			if err != nil {
+				return err
+			}
+			if len(lowAndHigh) == 2 {
+				high, err := v.parseValue(lowAndHigh[1])
+				if err != nil {
+					return err
+				}
+				if low > high {
+					return fmt.Errorf("beginning of range %d beyond end of range %d", low, high)
+				}
+			}
+		}
+		if len(rangeAndStep) == 2 {
+			step, err := strconv.Atoi(rangeAndStep[1])
+			if err != nil {
+				return fmt.Errorf("invalid step %q", rangeAndStep[1])
+			}
+			if step <= 0 {
+				return fmt.Errorf("step must be positive, got %d", step)
+			}
+		}
+	}
+	return nil
+}
+
+func (v cronFieldValidator) parseValue(val string) (int, error) {
+	if n, ok := v.names[strings.ToLower(val)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", val)
+	}
+	if n < v.min || n > v.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, v.min, v.max)
+	}
+	return n, nil
+}
+
+// secretFormatValidator warns if a plain text secret matches the SFTPGo
+// secret format and so it will not be sent as plain text.
+type secretFormatValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (secretFormatValidator) Description(_ context.Context) string {
+	return "values in SFTPGo secret format are not sent as plain text unless plain_text_secrets is enabled"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v secretFormatValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v secretFormatValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+	if !isSFTPGoSecretFormat(request.ConfigValue.ValueString()) {
+		return
+	}
+
+	// secrets are defined inside the provider specific configuration
+	plainTextPath := request.Path.ParentPath().ParentPath().AtName("plain_text_secrets")
+	var plainText types.Bool
+	diags := request.Config.GetAttribute(ctx, plainTextPath, &plainText)
+	response.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	if plainText.IsUnknown() || plainText.ValueBool() {
+		return
+	}
+
+	response.Diagnostics.AddAttributeWarning(
+		request.Path,
+		"Value in SFTPGo Secret Format",
+		fmt.Sprintf("Attribute %s matches the SFTPGo secret format, it will not be sent as plain text and "+
+			"SFTPGo will keep the current secret. If this is a plain text secret, set %s to true.",
+			request.Path, plainTextPath),
+	)
+}
+
+// emailValidator checks that the value is a valid email address. Values
+// containing placeholders are not validated, they are replaced at runtime.
+type emailValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (emailValidator) Description(_ context.Context) string {
+	return "must be a valid email address"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v emailValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v emailValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+	if strings.Contains(value, "{{") {
+		return
+	}
+	if !emailRegex.MatchString(value) {
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"Invalid Attribute Value Email",
+			fmt.Sprintf("Attribute %s %s, got: %q", request.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+// cidrValidator checks that the value is an IP/Mask in CIDR notation.
+type cidrValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (cidrValidator) Description(_ context.Context) string {
+	return `must be in CIDR notation, for example "192.0.2.0/24" or "2001:db8::/32"`
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v cidrValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v cidrValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+	if _, _, err := net.ParseCIDR(value); err != nil {
+		detail := fmt.Sprintf("Attribute %s %s, got: %q", request.Path, v.Description(ctx), value)
+		if net.ParseIP(value) != nil {
+			detail += `. Add the network mask to allow a single IP address, for example "/32" for IPv4 or "/128" for IPv6`
+		}
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"Invalid Attribute Value CIDR",
+			detail,
+		)
+	}
+}
+
+type keyPrefixValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (keyPrefixValidator) Description(_ context.Context) string {
+	return `must not start with "/" and must end with "/"`
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v keyPrefixValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v keyPrefixValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+	if value == "" {
+		return
+	}
+	if strings.HasPrefix(value, "/") || !strings.HasSuffix(value, "/") {
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"Invalid Attribute Value Key Prefix",
+			fmt.Sprintf("Attribute %s %s, got: %q", request.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+type absoluteVirtualPathValidator struct {
+	// allowPlaceholders accepts paths starting with a placeholder, for
+	// example "{{VirtualPath}}", placeholders expand to absolute paths
+	allowPlaceholders bool
+}
+
+// Description describes the validation in plain text formatting.
+func (v absoluteVirtualPathValidator) Description(_ context.Context) string {
+	if v.allowPlaceholders {
+		return `must be an absolute virtual path starting with "/" or with a placeholder, for example "{{VirtualPath}}"`
+	}
+	return `must be an absolute virtual path starting with "/"`
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v absoluteVirtualPathValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v absoluteVirtualPathValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+	// empty means the default "/"
+	if value == "" {
+		return
+	}
+	if v.allowPlaceholders && strings.HasPrefix(value, "{{") {
+		return
+	}
+	if !strings.HasPrefix(value, "/") {
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"Invalid Attribute Value Virtual Path",
+			fmt.Sprintf("Attribute %s %s, got: %q", request.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+type fingerprintValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (fingerprintValidator) Description(_ context.Context) string {
+	return `must be a SHA256 fingerprint in the format used by SFTPGo, "SHA256:" followed by the base64 encoded hash`
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v fingerprintValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v fingerprintValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := request.ConfigValue.ValueString()
+	if !isSHA256Fingerprint(value) {
+		response.Diagnostics.AddAttributeError(
+			request.Path,
+			"Invalid Attribute Value Fingerprint",
+			fmt.Sprintf("Attribute %s %s, got: %q", request.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+// isSHA256Fingerprint reports whether value is a SHA256 fingerprint as
+// reported by SFTPGo and OpenSSH, for example "SHA256:" followed by the
+// unpadded base64 encoded hash. SFTPGo compares fingerprints as strings, so
+// other formats never match.
+func isSHA256Fingerprint(value string) bool {
+	encoded, ok := strings.CutPrefix(value, "SHA256:")
+	if !ok {
+		return false
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(encoded)
+	return err == nil && len(hash) == sha256.Size
+}
+
+type emptyFingerprintsValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (emptyFingerprintsValidator) Description(_ context.Context) string {
+	return "should contain at least one fingerprint, if it is not set or empty any host key is accepted"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v emptyFingerprintsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v emptyFingerprintsValidator) ValidateList(ctx context.Context, request validator.ListRequest, response *validator.ListResponse) {
+	if request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	// a null list is handled as an empty one by SFTPGo
+	if len(request.ConfigValue.Elements()) == 0 {
+		response.Diagnostics.AddAttributeWarning(
+			request.Path,
+			"Host Key Verification Disabled",
+			fmt.Sprintf("Attribute %s %s. Any host key presented by the SFTP server will be accepted, "+
+				"this is a security risk.", request.Path, v.Description(ctx)),
+		)
+	}
+}
+
+// minMillisecondsTimestamp is 1973-03-03 as unix timestamp in milliseconds.
+// Lower values are implausible as expiration and are likely seconds, the
+// same value in seconds is year 5138.
+const minMillisecondsTimestamp = 100000000000
+
+type millisecondsTimestampValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (millisecondsTimestampValidator) Description(_ context.Context) string {
+	return "should be a unix timestamp in milliseconds"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v millisecondsTimestampValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v millisecondsTimestampValidator) ValidateInt64(ctx context.Context, request validator.Int64Request, response *validator.Int64Response) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := request.ConfigValue.ValueInt64()
+	if value > 0 && value < minMillisecondsTimestamp {
+		response.Diagnostics.AddAttributeWarning(
+			request.Path,
+			"Timestamp Looks Like Seconds",
+			fmt.Sprintf("Attribute %s %s, %d is %s. If it is a timestamp in seconds multiply it by 1000, "+
+				"for example %d.", request.Path, v.Description(ctx), value,
+				time.UnixMilli(value).UTC().Format(time.RFC3339), value*1000),
+		)
+	}
+}
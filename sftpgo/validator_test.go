@@ -16,11 +16,20 @@ package sftpgo
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSFTPEndPointValidator(t *testing.T) {
@@ -43,6 +52,14 @@ func TestSFTPEndPointValidator(t *testing.T) {
 		},
 		"missing port": {
 			val:         types.StringValue("127.0.0.1"),
+			expectError: false,
+		},
+		"missing host": {
+			val:         types.StringValue(":22"),
+			expectError: true,
+		},
+		"invalid": {
+			val:         types.StringValue("127.0.0.1:22:22"),
 			expectError: true,
 		},
 	}
@@ -69,3 +86,2015 @@ func TestSFTPEndPointValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalHomeDirValidator(t *testing.T) {
+	type testCase struct {
+		val         types.String
+		provider    *int64
+		expectError bool
+	}
+	local := int64(0)
+	cryptFs := int64(4)
+	s3 := int64(1)
+	tests := map[string]testCase{
+		"unknown": {
+			val:         types.StringUnknown(),
+			provider:    &local,
+			expectError: false,
+		},
+		"absolute": {
+			val:         types.StringValue("/srv/sftpgo/user"),
+			provider:    &local,
+			expectError: false,
+		},
+		"windows absolute": {
+			val:         types.StringValue(`C:\sftpgo\user`),
+			provider:    &local,
+			expectError: false,
+		},
+		"relative": {
+			val:         types.StringValue("sftpgo/user"),
+			provider:    &local,
+			expectError: true,
+		},
+		"relative encrypted": {
+			val:         types.StringValue("./user"),
+			provider:    &cryptFs,
+			expectError: true,
+		},
+		"relative provider not set": {
+			val:         types.StringValue("user"),
+			provider:    nil,
+			expectError: true,
+		},
+		"relative S3": {
+			val:         types.StringValue("user"),
+			provider:    &s3,
+			expectError: false,
+		},
+	}
+
+	ctx := context.Background()
+	schemaResp := resource.SchemaResponse{}
+	(&userResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	fsType := objType.AttributeTypes["filesystem"].(tftypes.Object)
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			var provider tftypes.Value
+			if test.provider != nil {
+				provider = tftypes.NewValue(tftypes.Number, *test.provider)
+			} else {
+				provider = tftypes.NewValue(tftypes.Number, nil)
+			}
+			request := validator.StringRequest{
+				Path:           path.Root("home_dir"),
+				PathExpression: path.MatchRoot("home_dir"),
+				ConfigValue:    test.val,
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw: getTestObject(objType, map[string]tftypes.Value{
+						"filesystem": getTestObject(fsType, map[string]tftypes.Value{
+							"provider": provider,
+						}),
+					}),
+				},
+			}
+			response := validator.StringResponse{}
+			v := localHomeDirValidator{}
+			v.ValidateString(ctx, request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestPermissionsValidator(t *testing.T) {
+	type testCase struct {
+		val         map[string]string
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"valid": {
+			val: map[string]string{
+				"/":     "*",
+				"/sub":  "list,download",
+				"/sub2": "list,upload,overwrite,delete_files,rename_dirs,create_dirs,chtimes",
+			},
+			expectError: false,
+		},
+		"invalid key": {
+			val: map[string]string{
+				"/":    "*",
+				"home": "list",
+			},
+			expectError: true,
+		},
+		"invalid permission": {
+			val: map[string]string{
+				"/": "list,downloads",
+			},
+			expectError: true,
+		},
+		"space after comma": {
+			val: map[string]string{
+				"/": "list, download",
+			},
+			expectError: true,
+		},
+		"empty": {
+			val: map[string]string{
+				"/": "",
+			},
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			val, diags := types.MapValueFrom(context.Background(), types.StringType, test.val)
+			require.False(t, diags.HasError())
+			request := validator.MapRequest{
+				Path:           path.Root("permissions"),
+				PathExpression: path.MatchRoot("permissions"),
+				ConfigValue:    val,
+			}
+			response := validator.MapResponse{}
+			v := permissionsValidator{}
+			v.ValidateMap(context.TODO(), request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestPermissionsValidatorAllErrors(t *testing.T) {
+	val, diags := types.MapValueFrom(context.Background(), types.StringType, map[string]string{
+		"/":    "list,invalid1",
+		"home": "download,invalid2",
+		"/sub": "*",
+	})
+	require.False(t, diags.HasError())
+	request := validator.MapRequest{
+		Path:           path.Root("permissions"),
+		PathExpression: path.MatchRoot("permissions"),
+		ConfigValue:    val,
+	}
+	response := validator.MapResponse{}
+	permissionsValidator{}.ValidateMap(context.TODO(), request, &response)
+	require.Equal(t, 1, response.Diagnostics.ErrorsCount())
+	detail := response.Diagnostics.Errors()[0].Detail()
+	require.Contains(t, detail, `"/": invalid permission "invalid1"`)
+	require.Contains(t, detail, `"home": the path must start with "/"`)
+	require.Contains(t, detail, `"home": invalid permission "invalid2"`)
+}
+
+func TestCronFieldValidator(t *testing.T) {
+	type testCase struct {
+		v           cronFieldValidator
+		val         string
+		expectError bool
+	}
+	hour := cronFieldValidator{min: 0, max: 23}
+	dayOfWeek := cronFieldValidator{min: 0, max: 6, names: dayOfWeekNames}
+	dayOfMonth := cronFieldValidator{min: 1, max: 31}
+	month := cronFieldValidator{min: 1, max: 12, names: monthNames}
+	tests := map[string]testCase{
+		"hour wildcard":                 {v: hour, val: "*"},
+		"hour value":                    {v: hour, val: "0"},
+		"hour list":                     {v: hour, val: "1,5,23"},
+		"hour range":                    {v: hour, val: "8-18"},
+		"hour step":                     {v: hour, val: "*/2"},
+		"hour range step":               {v: hour, val: "8-18/3"},
+		"hour out of range":             {v: hour, val: "25", expectError: true},
+		"hour negative":                 {v: hour, val: "-1", expectError: true},
+		"hour inverted range":           {v: hour, val: "18-8", expectError: true},
+		"hour zero step":                {v: hour, val: "*/0", expectError: true},
+		"hour invalid step":             {v: hour, val: "*/x", expectError: true},
+		"hour empty":                    {v: hour, val: "", expectError: true},
+		"hour empty list item":          {v: hour, val: "1,", expectError: true},
+		"day of week names":             {v: dayOfWeek, val: "mon-fri"},
+		"day of week question mark":     {v: dayOfWeek, val: "?"},
+		"day of week out of range":      {v: dayOfWeek, val: "7", expectError: true},
+		"day of week invalid name":      {v: dayOfWeek, val: "monday", expectError: true},
+		"day of month list":             {v: dayOfMonth, val: "1,15,31"},
+		"day of month zero":             {v: dayOfMonth, val: "0", expectError: true},
+		"day of month out of range":     {v: dayOfMonth, val: "1-32", expectError: true},
+		"month names":                   {v: month, val: "JAN,jul"},
+		"month step":                    {v: month, val: "1/3"},
+		"month out of range":            {v: month, val: "13", expectError: true},
+		"month too many slashes":        {v: month, val: "*/2/3", expectError: true},
+		"month wildcard with range":     {v: month, val: "*-3", expectError: true},
+		"month day of week name":        {v: month, val: "sun", expectError: true},
+		"day of month too many hyphens": {v: dayOfMonth, val: "1-2-3", expectError: true},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			request := validator.StringRequest{
+				Path:           path.Root("test"),
+				PathExpression: path.MatchRoot("test"),
+				ConfigValue:    types.StringValue(test.val),
+			}
+			response := validator.StringResponse{}
+			test.v.ValidateString(context.TODO(), request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestSecretFormatValidator(t *testing.T) {
+	type testCase struct {
+		val           types.String
+		plainText     *bool
+		expectWarning bool
+	}
+	enabled := true
+	disabled := false
+	secret := "$AES-256-GCM$key$4$datapayload"
+	tests := map[string]testCase{
+		"unknown": {
+			val:           types.StringUnknown(),
+			expectWarning: false,
+		},
+		"plain text": {
+			val:           types.StringValue("secret"),
+			expectWarning: false,
+		},
+		"secret format": {
+			val:           types.StringValue(secret),
+			expectWarning: true,
+		},
+		"secret format plain text disabled": {
+			val:           types.StringValue(secret),
+			plainText:     &disabled,
+			expectWarning: true,
+		},
+		"secret format plain text enabled": {
+			val:           types.StringValue(secret),
+			plainText:     &enabled,
+			expectWarning: false,
+		},
+	}
+
+	ctx := context.Background()
+	schemaResp := resource.SchemaResponse{}
+	(&userResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	fsType := objType.AttributeTypes["filesystem"].(tftypes.Object)
+	secretPath := path.Root("filesystem").AtName("s3config").AtName("access_secret")
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			fsValues := map[string]tftypes.Value{
+				"provider": tftypes.NewValue(tftypes.Number, 1),
+			}
+			if test.plainText != nil {
+				fsValues["plain_text_secrets"] = tftypes.NewValue(tftypes.Bool, *test.plainText)
+			}
+			request := validator.StringRequest{
+				Path:           secretPath,
+				PathExpression: secretPath.Expression(),
+				ConfigValue:    test.val,
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw: getTestObject(objType, map[string]tftypes.Value{
+						"filesystem": getTestObject(fsType, fsValues),
+					}),
+				},
+			}
+			response := validator.StringResponse{}
+			secretFormatValidator{}.ValidateString(ctx, request, &response)
+
+			require.False(t, response.Diagnostics.HasError(), "unexpected error: %v", response.Diagnostics)
+			if test.expectWarning {
+				require.Equal(t, 1, response.Diagnostics.WarningsCount())
+			} else {
+				require.Equal(t, 0, response.Diagnostics.WarningsCount())
+			}
+		})
+	}
+}
+
+func TestEmailValidator(t *testing.T) {
+	type testCase struct {
+		val         types.String
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"unknown": {
+			val:         types.StringUnknown(),
+			expectError: false,
+		},
+		"null": {
+			val:         types.StringNull(),
+			expectError: false,
+		},
+		"valid": {
+			val:         types.StringValue("user@example.com"),
+			expectError: false,
+		},
+		"plus addressing": {
+			val:         types.StringValue("user+sftpgo@example.com"),
+			expectError: false,
+		},
+		"subdomain": {
+			val:         types.StringValue("first.last@mail.sub.example.co.uk"),
+			expectError: false,
+		},
+		"placeholder": {
+			val:         types.StringValue("{{.Email}}"),
+			expectError: false,
+		},
+		"missing at": {
+			val:         types.StringValue("user.example.com"),
+			expectError: true,
+		},
+		"missing local part": {
+			val:         types.StringValue("@example.com"),
+			expectError: true,
+		},
+		"double at": {
+			val:         types.StringValue("user@@example.com"),
+			expectError: true,
+		},
+		"space": {
+			val:         types.StringValue("user name@example.com"),
+			expectError: true,
+		},
+		"invalid domain": {
+			val:         types.StringValue("user@-example.com"),
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			request := validator.StringRequest{
+				Path:           path.Root("test"),
+				PathExpression: path.MatchRoot("test"),
+				ConfigValue:    test.val,
+			}
+			response := validator.StringResponse{}
+			v := emailValidator{}
+			v.ValidateString(context.TODO(), request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestCIDRValidator(t *testing.T) {
+	type testCase struct {
+		val         []string
+		expectError []int
+	}
+	tests := map[string]testCase{
+		"IPv4 network": {
+			val: []string{"192.168.1.0/24"},
+		},
+		"IPv6 network": {
+			val: []string{"2001:db8:abcd:12::/64"},
+		},
+		"single IP with mask": {
+			val: []string{"192.0.2.1/32", "2001:db8::1/128"},
+		},
+		"bare IPv4": {
+			val:         []string{"192.168.1.0/24", "192.0.2.1"},
+			expectError: []int{1},
+		},
+		"bare IPv6": {
+			val:         []string{"2001:db8::1", "2001:db8::/32"},
+			expectError: []int{0},
+		},
+		"invalid": {
+			val:         []string{"invalid", "10.0.0.0/33"},
+			expectError: []int{0, 1},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			val, diags := types.ListValueFrom(context.Background(), types.StringType, test.val)
+			require.False(t, diags.HasError())
+			request := validator.ListRequest{
+				Path:           path.Root("allowed_ip"),
+				PathExpression: path.MatchRoot("allowed_ip"),
+				ConfigValue:    val,
+			}
+			response := validator.ListResponse{}
+			listvalidator.ValueStringsAre(cidrValidator{}).ValidateList(context.TODO(), request, &response)
+
+			require.Equal(t, len(test.expectError), response.Diagnostics.ErrorsCount(), "unexpected diagnostics: %v",
+				response.Diagnostics)
+			for idx, d := range response.Diagnostics.Errors() {
+				withPath, ok := d.(diag.DiagnosticWithPath)
+				require.True(t, ok)
+				require.True(t, withPath.Path().Equal(path.Root("allowed_ip").AtListIndex(test.expectError[idx])))
+			}
+		})
+	}
+}
+
+// getTestObject returns an object of the specified type, attributes without
+// a value are set to null.
+func getTestObject(objType tftypes.Object, values map[string]tftypes.Value) tftypes.Value {
+	attrs := make(map[string]tftypes.Value)
+	for name, attrType := range objType.AttributeTypes {
+		if val, ok := values[name]; ok {
+			attrs[name] = val
+		} else {
+			attrs[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+	return tftypes.NewValue(objType, attrs)
+}
+
+// validateResourceConfig runs the resource config validation for the
+// specified values and returns the resulting diagnostics.
+func validateResourceConfig(
+	t *testing.T,
+	r resource.ResourceWithValidateConfig,
+	getValues func(objType tftypes.Object) map[string]tftypes.Value,
+) diag.Diagnostics {
+	ctx := context.Background()
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	req := resource.ValidateConfigRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    getTestObject(objType, getValues(objType)),
+		},
+	}
+	resp := resource.ValidateConfigResponse{}
+	r.ValidateConfig(ctx, req, &resp)
+	return resp.Diagnostics
+}
+
+func getFsActionTestConfig(fsType int64, attributes ...string) func(tftypes.Object) map[string]tftypes.Value {
+	return func(objType tftypes.Object) map[string]tftypes.Value {
+		optionsType := objType.AttributeTypes["options"].(tftypes.Object)
+		fsConfigType := optionsType.AttributeTypes["fs_config"].(tftypes.Object)
+		paths := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "/path"),
+		})
+		fsConfig := map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.Number, fsType),
+		}
+		for _, name := range attributes {
+			switch name {
+			case "renames", "copy":
+				listType := fsConfigType.AttributeTypes[name].(tftypes.List)
+				elemType := listType.ElementType.(tftypes.Object)
+				fsConfig[name] = tftypes.NewValue(listType, []tftypes.Value{
+					getTestObject(elemType, map[string]tftypes.Value{
+						"key":   tftypes.NewValue(tftypes.String, "/source"),
+						"value": tftypes.NewValue(tftypes.String, "/target"),
+					}),
+				})
+			case "compress":
+				compressType := fsConfigType.AttributeTypes[name].(tftypes.Object)
+				fsConfig[name] = getTestObject(compressType, map[string]tftypes.Value{
+					"name":  tftypes.NewValue(tftypes.String, "/archive.zip"),
+					"paths": paths,
+				})
+			default:
+				fsConfig[name] = paths
+			}
+		}
+		return map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "action"),
+			"type": tftypes.NewValue(tftypes.Number, 9),
+			"options": getTestObject(optionsType, map[string]tftypes.Value{
+				"fs_config": getTestObject(fsConfigType, fsConfig),
+			}),
+		}
+	}
+}
+
+func getHTTPActionTestConfig(method string, withParts bool) func(tftypes.Object) map[string]tftypes.Value {
+	return func(objType tftypes.Object) map[string]tftypes.Value {
+		optionsType := objType.AttributeTypes["options"].(tftypes.Object)
+		httpConfigType := optionsType.AttributeTypes["http_config"].(tftypes.Object)
+		httpConfig := map[string]tftypes.Value{
+			"endpoint": tftypes.NewValue(tftypes.String, "http://127.0.0.1:8082/notify"),
+			"timeout":  tftypes.NewValue(tftypes.Number, 10),
+			"method":   tftypes.NewValue(tftypes.String, method),
+		}
+		if withParts {
+			partsType := httpConfigType.AttributeTypes["parts"].(tftypes.List)
+			httpConfig["parts"] = tftypes.NewValue(partsType, []tftypes.Value{
+				getTestObject(partsType.ElementType.(tftypes.Object), map[string]tftypes.Value{
+					"name": tftypes.NewValue(tftypes.String, "part1"),
+					"body": tftypes.NewValue(tftypes.String, "body"),
+				}),
+			})
+		}
+		return map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "action"),
+			"type": tftypes.NewValue(tftypes.Number, 1),
+			"options": getTestObject(optionsType, map[string]tftypes.Value{
+				"http_config": getTestObject(httpConfigType, httpConfig),
+			}),
+		}
+	}
+}
+
+func TestActionHTTPPartsValidation(t *testing.T) {
+	type testCase struct {
+		method      string
+		withParts   bool
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"POST with parts": {
+			method:      http.MethodPost,
+			withParts:   true,
+			expectError: false,
+		},
+		"PUT with parts": {
+			method:      http.MethodPut,
+			withParts:   true,
+			expectError: false,
+		},
+		"GET without parts": {
+			method:      http.MethodGet,
+			withParts:   false,
+			expectError: false,
+		},
+		"GET with parts": {
+			method:      http.MethodGet,
+			withParts:   true,
+			expectError: true,
+		},
+		"DELETE with parts": {
+			method:      http.MethodDelete,
+			withParts:   true,
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &actionResource{}, getHTTPActionTestConfig(test.method, test.withParts))
+			if test.expectError {
+				require.Equal(t, 1, diags.ErrorsCount(), "unexpected diagnostics: %v", diags)
+				withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+				require.True(t, ok)
+				require.True(t, withPath.Path().Equal(path.Root("options").AtName("http_config").AtName("parts")))
+			} else {
+				require.False(t, diags.HasError(), "unexpected diagnostics: %v", diags)
+			}
+		})
+	}
+}
+
+func TestActionHTTPPartValidation(t *testing.T) {
+	type testCase struct {
+		part        map[string]tftypes.Value
+		errorPath   path.Path
+		expectError bool
+	}
+	partPath := path.Root("options").AtName("http_config").AtName("parts").AtListIndex(0)
+	tests := map[string]testCase{
+		"file part": {
+			part: map[string]tftypes.Value{
+				"name":     tftypes.NewValue(tftypes.String, "file"),
+				"filepath": tftypes.NewValue(tftypes.String, "{{.VirtualPath}}"),
+			},
+			expectError: false,
+		},
+		"body part": {
+			part: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "data"),
+				"body": tftypes.NewValue(tftypes.String, "{{.Name}}"),
+			},
+			expectError: false,
+		},
+		"both file path and body": {
+			part: map[string]tftypes.Value{
+				"name":     tftypes.NewValue(tftypes.String, "file"),
+				"filepath": tftypes.NewValue(tftypes.String, "{{.VirtualPath}}"),
+				"body":     tftypes.NewValue(tftypes.String, "{{.Name}}"),
+			},
+			errorPath:   partPath,
+			expectError: true,
+		},
+		"neither file path nor body": {
+			part: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "file"),
+			},
+			errorPath:   partPath,
+			expectError: true,
+		},
+		"empty name": {
+			part: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, ""),
+				"body": tftypes.NewValue(tftypes.String, "{{.Name}}"),
+			},
+			errorPath:   partPath.AtName("name"),
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &actionResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				optionsType := objType.AttributeTypes["options"].(tftypes.Object)
+				httpConfigType := optionsType.AttributeTypes["http_config"].(tftypes.Object)
+				partsType := httpConfigType.AttributeTypes["parts"].(tftypes.List)
+				return map[string]tftypes.Value{
+					"name": tftypes.NewValue(tftypes.String, "action"),
+					"type": tftypes.NewValue(tftypes.Number, 1),
+					"options": getTestObject(optionsType, map[string]tftypes.Value{
+						"http_config": getTestObject(httpConfigType, map[string]tftypes.Value{
+							"endpoint": tftypes.NewValue(tftypes.String, "http://127.0.0.1:8082/upload"),
+							"method":   tftypes.NewValue(tftypes.String, http.MethodPost),
+							"parts": tftypes.NewValue(partsType, []tftypes.Value{
+								getTestObject(partsType.ElementType.(tftypes.Object), test.part),
+							}),
+						}),
+					}),
+				}
+			})
+			checkConfigErrorPath(t, diags, test.errorPath, test.expectError)
+		})
+	}
+}
+
+func TestActionFsConfigValidation(t *testing.T) {
+	type testCase struct {
+		fsType      int64
+		attributes  []string
+		expectedErr []path.Path
+	}
+	fsConfigPath := path.Root("options").AtName("fs_config")
+	tests := map[string]testCase{
+		"rename": {
+			fsType:     1,
+			attributes: []string{"renames"},
+		},
+		"delete": {
+			fsType:     2,
+			attributes: []string{"deletes"},
+		},
+		"compress": {
+			fsType:     5,
+			attributes: []string{"compress"},
+		},
+		"copy": {
+			fsType:     6,
+			attributes: []string{"copy"},
+		},
+		"rename with deletes": {
+			fsType:      1,
+			attributes:  []string{"renames", "deletes"},
+			expectedErr: []path.Path{fsConfigPath.AtName("deletes")},
+		},
+		"delete with renames": {
+			fsType:      2,
+			attributes:  []string{"renames"},
+			expectedErr: []path.Path{fsConfigPath.AtName("renames"), fsConfigPath.AtName("deletes")},
+		},
+		"mkdir with exist and copy": {
+			fsType:      3,
+			attributes:  []string{"mkdirs", "exist", "copy"},
+			expectedErr: []path.Path{fsConfigPath.AtName("exist"), fsConfigPath.AtName("copy")},
+		},
+		"exist with mkdirs": {
+			fsType:      4,
+			attributes:  []string{"mkdirs"},
+			expectedErr: []path.Path{fsConfigPath.AtName("mkdirs"), fsConfigPath.AtName("exist")},
+		},
+		"compress with deletes": {
+			fsType:      5,
+			attributes:  []string{"compress", "deletes"},
+			expectedErr: []path.Path{fsConfigPath.AtName("deletes")},
+		},
+		"copy missing": {
+			fsType:      6,
+			expectedErr: []path.Path{fsConfigPath.AtName("copy")},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &actionResource{}, getFsActionTestConfig(test.fsType, test.attributes...))
+			require.Equal(t, len(test.expectedErr), diags.ErrorsCount(), "unexpected diagnostics: %v", diags)
+			for _, p := range test.expectedErr {
+				found := false
+				for _, d := range diags.Errors() {
+					if withPath, ok := d.(diag.DiagnosticWithPath); ok && withPath.Path().Equal(p) {
+						found = true
+					}
+				}
+				require.True(t, found, "missing error for path %s", p)
+			}
+		})
+	}
+}
+
+func getRuleTestConfig(trigger int64, concurrentExecution *bool) func(tftypes.Object) map[string]tftypes.Value {
+	return func(objType tftypes.Object) map[string]tftypes.Value {
+		conditionsType := objType.AttributeTypes["conditions"].(tftypes.Object)
+		optionsType := conditionsType.AttributeTypes["options"].(tftypes.Object)
+		options := map[string]tftypes.Value{}
+		if concurrentExecution != nil {
+			options["concurrent_execution"] = tftypes.NewValue(tftypes.Bool, *concurrentExecution)
+		}
+		return map[string]tftypes.Value{
+			"name":    tftypes.NewValue(tftypes.String, "rule"),
+			"status":  tftypes.NewValue(tftypes.Number, 1),
+			"trigger": tftypes.NewValue(tftypes.Number, trigger),
+			"conditions": getTestObject(conditionsType, map[string]tftypes.Value{
+				"options": getTestObject(optionsType, options),
+			}),
+		}
+	}
+}
+
+func TestRuleConcurrentExecutionValidation(t *testing.T) {
+	enabled := true
+	disabled := false
+	type testCase struct {
+		trigger             int64
+		concurrentExecution *bool
+		expectWarning       bool
+	}
+	tests := map[string]testCase{
+		"schedule": {
+			trigger:             3,
+			concurrentExecution: &enabled,
+			expectWarning:       false,
+		},
+		"fs event": {
+			trigger:             1,
+			concurrentExecution: &enabled,
+			expectWarning:       true,
+		},
+		"fs event disabled": {
+			trigger:             1,
+			concurrentExecution: &disabled,
+			expectWarning:       false,
+		},
+		"provider event not set": {
+			trigger:             2,
+			concurrentExecution: nil,
+			expectWarning:       false,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &ruleResource{}, getRuleTestConfig(test.trigger, test.concurrentExecution))
+			require.False(t, diags.HasError(), "unexpected error: %v", diags)
+			if test.expectWarning {
+				require.Equal(t, 1, diags.WarningsCount())
+			} else {
+				require.Equal(t, 0, diags.WarningsCount())
+			}
+		})
+	}
+}
+
+func TestKeyPrefixValidator(t *testing.T) {
+	type testCase struct {
+		val         types.String
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"unknown": {
+			val:         types.StringUnknown(),
+			expectError: false,
+		},
+		"null": {
+			val:         types.StringNull(),
+			expectError: false,
+		},
+		"empty": {
+			val:         types.StringValue(""),
+			expectError: false,
+		},
+		"valid": {
+			val:         types.StringValue("sub/dir/"),
+			expectError: false,
+		},
+		"leading slash": {
+			val:         types.StringValue("/sub/dir/"),
+			expectError: true,
+		},
+		"missing trailing slash": {
+			val:         types.StringValue("sub/dir"),
+			expectError: true,
+		},
+		"root": {
+			val:         types.StringValue("/"),
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			request := validator.StringRequest{
+				Path:           path.Root("test"),
+				PathExpression: path.MatchRoot("test"),
+				ConfigValue:    test.val,
+			}
+			response := validator.StringResponse{}
+			v := keyPrefixValidator{}
+			v.ValidateString(context.TODO(), request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestFsEnumValidators(t *testing.T) {
+	type testCase struct {
+		block     string
+		attribute string
+		valid     string
+		invalid   string
+	}
+	tests := []testCase{
+		{block: "s3config", attribute: "storage_class", valid: "STANDARD_IA", invalid: "STANDRD"},
+		{block: "s3config", attribute: "acl", valid: "bucket-owner-full-control", invalid: "publicRead"},
+		{block: "gcsconfig", attribute: "storage_class", valid: "NEARLINE", invalid: "GLACIER"},
+		{block: "gcsconfig", attribute: "acl", valid: "publicRead", invalid: "public-read"},
+		{block: "azblobconfig", attribute: "access_tier", valid: "Cool", invalid: "cool"},
+	}
+
+	fs := getSchemaForFilesystem()
+	for _, test := range tests {
+		test := test
+		t.Run(test.block+"."+test.attribute, func(t *testing.T) {
+			block, ok := fs.Attributes[test.block].(dsschema.SingleNestedAttribute)
+			require.True(t, ok)
+			attr, ok := block.Attributes[test.attribute].(dsschema.StringAttribute)
+			require.True(t, ok)
+			require.NotEmpty(t, attr.Validators)
+
+			validate := func(val types.String) diag.Diagnostics {
+				request := validator.StringRequest{
+					Path:           path.Root("test"),
+					PathExpression: path.MatchRoot("test"),
+					ConfigValue:    val,
+				}
+				response := validator.StringResponse{}
+				for _, v := range attr.Validators {
+					v.ValidateString(context.TODO(), request, &response)
+				}
+				return response.Diagnostics
+			}
+			for _, val := range []types.String{types.StringNull(), types.StringUnknown(), types.StringValue(""),
+				types.StringValue(test.valid)} {
+				diags := validate(val)
+				require.False(t, diags.HasError(), "unexpected error for %v: %v", val, diags)
+			}
+			require.True(t, validate(types.StringValue(test.invalid)).HasError())
+		})
+	}
+}
+
+func TestAbsoluteVirtualPathValidator(t *testing.T) {
+	type testCase struct {
+		val               types.String
+		allowPlaceholders bool
+		expectError       bool
+	}
+	tests := map[string]testCase{
+		"unknown": {
+			val:         types.StringUnknown(),
+			expectError: false,
+		},
+		"null": {
+			val:         types.StringNull(),
+			expectError: false,
+		},
+		"empty": {
+			val:         types.StringValue(""),
+			expectError: false,
+		},
+		"absolute": {
+			val:         types.StringValue("/data"),
+			expectError: false,
+		},
+		"root": {
+			val:         types.StringValue("/"),
+			expectError: false,
+		},
+		"relative": {
+			val:         types.StringValue("data"),
+			expectError: true,
+		},
+		"relative with placeholders allowed": {
+			val:               types.StringValue("dir1"),
+			allowPlaceholders: true,
+			expectError:       true,
+		},
+		"absolute with placeholders allowed": {
+			val:               types.StringValue("/dir1/{{ObjectName}}"),
+			allowPlaceholders: true,
+			expectError:       false,
+		},
+		"placeholder": {
+			val:               types.StringValue("{{VirtualPath}}.bak"),
+			allowPlaceholders: true,
+			expectError:       false,
+		},
+		"placeholder not allowed": {
+			val:         types.StringValue("{{VirtualPath}}.bak"),
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			request := validator.StringRequest{
+				Path:           path.Root("test"),
+				PathExpression: path.MatchRoot("test"),
+				ConfigValue:    test.val,
+			}
+			response := validator.StringResponse{}
+			v := absoluteVirtualPathValidator{allowPlaceholders: test.allowPlaceholders}
+			v.ValidateString(context.TODO(), request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestFingerprintsValidator(t *testing.T) {
+	type testCase struct {
+		val           []string
+		null          bool
+		expectError   []int
+		expectWarning bool
+	}
+	tests := map[string]testCase{
+		"base64": {
+			val: []string{"SHA256:vaPTvONEfHrhHRr1hrPdjBqtLlOMOEOD73DX0C8HMXc"},
+		},
+		// SFTPGo compares fingerprints as strings, other formats never match
+		"base64 padded": {
+			val:         []string{"SHA256:vaPTvONEfHrhHRr1hrPdjBqtLlOMOEOD73DX0C8HMXc="},
+			expectError: []int{0},
+		},
+		"hex": {
+			val:         []string{"bda3d3bce3447c7ae11d1af586b3dd8c1aad2e538c384383ef70d7d02f073177"},
+			expectError: []int{0},
+		},
+		"missing prefix": {
+			val:         []string{"SHA256:vaPTvONEfHrhHRr1hrPdjBqtLlOMOEOD73DX0C8HMXc", "vaPTvONEfHrhHRr1hrPdjBqtLlOMOEOD73DX0C8HMXc"},
+			expectError: []int{1},
+		},
+		"invalid": {
+			val: []string{"MD5:16:27:ac:a5:76:28:2d:36:63:1b:56:4d:eb:df:a6:48", "SHA256:invalid",
+				"bda3d3bce3447c7ae11d1af586b3dd8c"},
+			expectError: []int{0, 1, 2},
+		},
+		"empty": {
+			val:           []string{},
+			expectWarning: true,
+		},
+		"null": {
+			null:          true,
+			expectWarning: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			val, diags := types.ListValueFrom(context.Background(), types.StringType, test.val)
+			require.False(t, diags.HasError())
+			if test.null {
+				val = types.ListNull(types.StringType)
+			}
+			request := validator.ListRequest{
+				Path:           path.Root("fingerprints"),
+				PathExpression: path.MatchRoot("fingerprints"),
+				ConfigValue:    val,
+			}
+			response := validator.ListResponse{}
+			emptyFingerprintsValidator{}.ValidateList(context.TODO(), request, &response)
+			listvalidator.ValueStringsAre(fingerprintValidator{}).ValidateList(context.TODO(), request, &response)
+
+			require.Equal(t, len(test.expectError), response.Diagnostics.ErrorsCount(), "unexpected diagnostics: %v",
+				response.Diagnostics)
+			for idx, d := range response.Diagnostics.Errors() {
+				withPath, ok := d.(diag.DiagnosticWithPath)
+				require.True(t, ok)
+				require.True(t, withPath.Path().Equal(path.Root("fingerprints").AtListIndex(test.expectError[idx])))
+			}
+			require.Equal(t, test.expectWarning, response.Diagnostics.WarningsCount() > 0)
+		})
+	}
+}
+
+func TestMillisecondsTimestampValidator(t *testing.T) {
+	type testCase struct {
+		val           types.Int64
+		expectWarning bool
+	}
+	tests := map[string]testCase{
+		"null":         {val: types.Int64Null()},
+		"unknown":      {val: types.Int64Unknown()},
+		"zero":         {val: types.Int64Value(0)},
+		"seconds":      {val: types.Int64Value(1767225600), expectWarning: true},
+		"milliseconds": {val: types.Int64Value(1767225600000)},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			request := validator.Int64Request{
+				Path:           path.Root("expiration_date"),
+				PathExpression: path.MatchRoot("expiration_date"),
+				ConfigValue:    test.val,
+			}
+			response := validator.Int64Response{}
+			millisecondsTimestampValidator{}.ValidateInt64(context.TODO(), request, &response)
+
+			require.False(t, response.Diagnostics.HasError(), "unexpected diagnostics: %v", response.Diagnostics)
+			if !test.expectWarning {
+				require.Equal(t, 0, response.Diagnostics.WarningsCount(), "unexpected diagnostics: %v",
+					response.Diagnostics)
+				return
+			}
+			require.Equal(t, 1, response.Diagnostics.WarningsCount())
+			require.Contains(t, response.Diagnostics.Warnings()[0].Detail(), "1767225600000")
+		})
+	}
+}
+
+type ruleTestConditions struct {
+	fsEvents       []string
+	providerEvents []string
+	schedules      int
+	idpLoginEvent  *int64
+}
+
+func getRuleConditionsTestConfig(trigger int64, conditions ruleTestConditions) func(tftypes.Object) map[string]tftypes.Value {
+	return func(objType tftypes.Object) map[string]tftypes.Value {
+		conditionsType := objType.AttributeTypes["conditions"].(tftypes.Object)
+		values := map[string]tftypes.Value{}
+		getStringList := func(name string, elems []string) {
+			if elems == nil {
+				return
+			}
+			var items []tftypes.Value
+			for _, elem := range elems {
+				items = append(items, tftypes.NewValue(tftypes.String, elem))
+			}
+			values[name] = tftypes.NewValue(conditionsType.AttributeTypes[name], items)
+		}
+		getStringList("fs_events", conditions.fsEvents)
+		getStringList("provider_events", conditions.providerEvents)
+		if conditions.schedules > 0 {
+			schedulesType := conditionsType.AttributeTypes["schedules"].(tftypes.List)
+			scheduleType := schedulesType.ElementType.(tftypes.Object)
+			var schedules []tftypes.Value
+			for i := 0; i < conditions.schedules; i++ {
+				schedules = append(schedules, getTestObject(scheduleType, map[string]tftypes.Value{
+					"hour":         tftypes.NewValue(tftypes.String, "0"),
+					"day_of_week":  tftypes.NewValue(tftypes.String, "*"),
+					"day_of_month": tftypes.NewValue(tftypes.String, "*"),
+					"month":        tftypes.NewValue(tftypes.String, "*"),
+				}))
+			}
+			values["schedules"] = tftypes.NewValue(schedulesType, schedules)
+		}
+		if conditions.idpLoginEvent != nil {
+			values["idp_login_event"] = tftypes.NewValue(tftypes.Number, *conditions.idpLoginEvent)
+		}
+		return map[string]tftypes.Value{
+			"name":       tftypes.NewValue(tftypes.String, "rule"),
+			"status":     tftypes.NewValue(tftypes.Number, 1),
+			"trigger":    tftypes.NewValue(tftypes.Number, trigger),
+			"conditions": getTestObject(conditionsType, values),
+		}
+	}
+}
+
+func TestRuleTriggerConditionsValidation(t *testing.T) {
+	idpAny := int64(0)
+	idpUser := int64(1)
+	type testCase struct {
+		trigger     int64
+		conditions  ruleTestConditions
+		expectError []string
+	}
+	tests := map[string]testCase{
+		"fs events": {
+			trigger:    1,
+			conditions: ruleTestConditions{fsEvents: []string{"upload"}},
+		},
+		"provider events": {
+			trigger:    2,
+			conditions: ruleTestConditions{providerEvents: []string{"add"}},
+		},
+		"schedules": {
+			trigger:    3,
+			conditions: ruleTestConditions{schedules: 1},
+		},
+		"idp login any": {
+			trigger:    7,
+			conditions: ruleTestConditions{idpLoginEvent: &idpAny},
+		},
+		"on demand": {
+			trigger: 6,
+		},
+		"empty list on wrong trigger": {
+			trigger:    3,
+			conditions: ruleTestConditions{fsEvents: []string{}, schedules: 1},
+		},
+		"fs events on provider trigger": {
+			trigger:     2,
+			conditions:  ruleTestConditions{fsEvents: []string{"upload"}, providerEvents: []string{"add"}},
+			expectError: []string{"fs_events"},
+		},
+		"provider events on fs trigger": {
+			trigger:     1,
+			conditions:  ruleTestConditions{fsEvents: []string{"upload"}, providerEvents: []string{"add"}},
+			expectError: []string{"provider_events"},
+		},
+		"schedules on on demand trigger": {
+			trigger:     6,
+			conditions:  ruleTestConditions{schedules: 2},
+			expectError: []string{"schedules"},
+		},
+		"idp login event on schedule trigger": {
+			trigger:     3,
+			conditions:  ruleTestConditions{schedules: 1, idpLoginEvent: &idpUser},
+			expectError: []string{"idp_login_event"},
+		},
+		"missing idp login event": {
+			trigger:     7,
+			expectError: []string{"idp_login_event"},
+		},
+		"multiple mismatches": {
+			trigger:     4,
+			conditions:  ruleTestConditions{fsEvents: []string{"upload"}, schedules: 1, idpLoginEvent: &idpAny},
+			expectError: []string{"fs_events", "schedules", "idp_login_event"},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &ruleResource{}, getRuleConditionsTestConfig(test.trigger, test.conditions))
+			require.Equal(t, len(test.expectError), diags.ErrorsCount(), "unexpected diagnostics: %v", diags)
+			for idx, d := range diags.Errors() {
+				withPath, ok := d.(diag.DiagnosticWithPath)
+				require.True(t, ok)
+				require.True(t, withPath.Path().Equal(path.Root("conditions").AtName(test.expectError[idx])),
+					"unexpected path: %s", withPath.Path())
+			}
+		})
+	}
+}
+
+func getFilesystemTestObject(fsType tftypes.Object, provider int64, blocks ...string) tftypes.Value {
+	values := map[string]tftypes.Value{
+		"provider": tftypes.NewValue(tftypes.Number, provider),
+	}
+	for _, block := range blocks {
+		values[block] = getTestObject(fsType.AttributeTypes[block].(tftypes.Object), nil)
+	}
+	return getTestObject(fsType, values)
+}
+
+func TestFilesystemConfigValidation(t *testing.T) {
+	type testCase struct {
+		provider    int64
+		blocks      []string
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"local": {
+			provider: 0,
+		},
+		"local with osconfig": {
+			provider: 0,
+			blocks:   []string{"osconfig"},
+		},
+		"s3": {
+			provider: 1,
+			blocks:   []string{"s3config"},
+		},
+		"sftp": {
+			provider: 5,
+			blocks:   []string{"sftpconfig"},
+		},
+		"local with sftpconfig": {
+			provider:    0,
+			blocks:      []string{"sftpconfig"},
+			expectError: true,
+		},
+		"s3 with sftpconfig": {
+			provider:    1,
+			blocks:      []string{"sftpconfig"},
+			expectError: true,
+		},
+		"sftp with extra block": {
+			provider:    5,
+			blocks:      []string{"sftpconfig", "httpconfig"},
+			expectError: true,
+		},
+		"missing cryptconfig": {
+			provider:    4,
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				fsType := objType.AttributeTypes["filesystem"].(tftypes.Object)
+				return map[string]tftypes.Value{
+					"username":   tftypes.NewValue(tftypes.String, "user"),
+					"filesystem": getFilesystemTestObject(fsType, test.provider, test.blocks...),
+				}
+			})
+			checkConfigErrorPath(t, diags, path.Root("filesystem"), test.expectError)
+
+			diags = validateResourceConfig(t, &folderResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				fsType := objType.AttributeTypes["filesystem"].(tftypes.Object)
+				return map[string]tftypes.Value{
+					"name":       tftypes.NewValue(tftypes.String, "folder"),
+					"filesystem": getFilesystemTestObject(fsType, test.provider, test.blocks...),
+				}
+			})
+			checkConfigErrorPath(t, diags, path.Root("filesystem"), test.expectError)
+
+			diags = validateResourceConfig(t, &groupResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				settingsType := objType.AttributeTypes["user_settings"].(tftypes.Object)
+				fsType := settingsType.AttributeTypes["filesystem"].(tftypes.Object)
+				return map[string]tftypes.Value{
+					"name": tftypes.NewValue(tftypes.String, "group"),
+					"user_settings": getTestObject(settingsType, map[string]tftypes.Value{
+						"filesystem": getFilesystemTestObject(fsType, test.provider, test.blocks...),
+					}),
+				}
+			})
+			checkConfigErrorPath(t, diags, path.Root("user_settings").AtName("filesystem"), test.expectError)
+		})
+	}
+
+	// the group filesystem is optional
+	diags := validateResourceConfig(t, &groupResource{}, func(_ tftypes.Object) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "group"),
+		}
+	})
+	require.False(t, diags.HasError(), "unexpected error: %v", diags)
+}
+
+func checkConfigErrorPath(t *testing.T, diags diag.Diagnostics, attrPath path.Path, expectError bool) {
+	if !expectError {
+		require.False(t, diags.HasError(), "unexpected error: %v", diags)
+		return
+	}
+	require.Equal(t, 1, diags.ErrorsCount(), "unexpected diagnostics: %v", diags)
+	withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+	require.True(t, ok)
+	require.True(t, withPath.Path().Equal(attrPath))
+}
+
+func TestDataTransferValidation(t *testing.T) {
+	type testCase struct {
+		values      map[string]int64
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"none": {},
+		"total": {
+			values: map[string]int64{"total_data_transfer": 100},
+		},
+		"upload and download": {
+			values: map[string]int64{"upload_data_transfer": 100, "download_data_transfer": 200},
+		},
+		"total with zero values": {
+			values: map[string]int64{"upload_data_transfer": 0, "download_data_transfer": 0, "total_data_transfer": 100},
+		},
+		"total and upload": {
+			values:      map[string]int64{"upload_data_transfer": 100, "total_data_transfer": 100},
+			expectError: true,
+		},
+		"total and download": {
+			values:      map[string]int64{"download_data_transfer": 100, "total_data_transfer": 100},
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		getValues := func() map[string]tftypes.Value {
+			values := make(map[string]tftypes.Value)
+			for k, v := range test.values {
+				values[k] = tftypes.NewValue(tftypes.Number, v)
+			}
+			return values
+		}
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				fsType := objType.AttributeTypes["filesystem"].(tftypes.Object)
+				values := getValues()
+				values["username"] = tftypes.NewValue(tftypes.String, "user")
+				values["filesystem"] = getFilesystemTestObject(fsType, 0)
+				return values
+			})
+			checkConfigErrorPath(t, diags, path.Root("total_data_transfer"), test.expectError)
+
+			diags = validateResourceConfig(t, &groupResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				settingsType := objType.AttributeTypes["user_settings"].(tftypes.Object)
+				return map[string]tftypes.Value{
+					"name":          tftypes.NewValue(tftypes.String, "group"),
+					"user_settings": getTestObject(settingsType, getValues()),
+				}
+			})
+			checkConfigErrorPath(t, diags, path.Root("user_settings").AtName("total_data_transfer"), test.expectError)
+		})
+	}
+}
+
+func TestGCSCredentialsValidation(t *testing.T) {
+	type testCase struct {
+		values      map[string]tftypes.Value
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"credentials": {
+			values: map[string]tftypes.Value{
+				"credentials": tftypes.NewValue(tftypes.String, "{}"),
+			},
+		},
+		"automatic credentials": {
+			values: map[string]tftypes.Value{
+				"automatic_credentials": tftypes.NewValue(tftypes.Number, 1),
+			},
+		},
+		"credentials and disabled automatic credentials": {
+			values: map[string]tftypes.Value{
+				"credentials":           tftypes.NewValue(tftypes.String, "{}"),
+				"automatic_credentials": tftypes.NewValue(tftypes.Number, 0),
+			},
+		},
+		"credentials and automatic credentials": {
+			values: map[string]tftypes.Value{
+				"credentials":           tftypes.NewValue(tftypes.String, "{}"),
+				"automatic_credentials": tftypes.NewValue(tftypes.Number, 1),
+			},
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		getFs := func(fsType tftypes.Object) tftypes.Value {
+			gcsType := fsType.AttributeTypes["gcsconfig"].(tftypes.Object)
+			gcsValues := map[string]tftypes.Value{
+				"bucket": tftypes.NewValue(tftypes.String, "bucket"),
+			}
+			for k, v := range test.values {
+				gcsValues[k] = v
+			}
+			return getTestObject(fsType, map[string]tftypes.Value{
+				"provider":  tftypes.NewValue(tftypes.Number, 2),
+				"gcsconfig": getTestObject(gcsType, gcsValues),
+			})
+		}
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				return map[string]tftypes.Value{
+					"username":   tftypes.NewValue(tftypes.String, "user"),
+					"filesystem": getFs(objType.AttributeTypes["filesystem"].(tftypes.Object)),
+				}
+			})
+			checkConfigErrorPath(t, diags, path.Root("filesystem").AtName("gcsconfig").AtName("credentials"),
+				test.expectError)
+
+			diags = validateResourceConfig(t, &folderResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				return map[string]tftypes.Value{
+					"name":       tftypes.NewValue(tftypes.String, "folder"),
+					"filesystem": getFs(objType.AttributeTypes["filesystem"].(tftypes.Object)),
+				}
+			})
+			checkConfigErrorPath(t, diags, path.Root("filesystem").AtName("gcsconfig").AtName("credentials"),
+				test.expectError)
+		})
+	}
+}
+
+func TestS3CredentialsValidation(t *testing.T) {
+	type testCase struct {
+		values        map[string]string
+		errorPath     string
+		expectWarning bool
+	}
+	tests := map[string]testCase{
+		"environment credentials": {},
+		"role arn": {
+			values: map[string]string{"role_arn": "arn:aws:iam::123456789012:role/sftpgo"},
+		},
+		"empty static keys and role arn": {
+			values: map[string]string{"access_key": "", "access_secret": "",
+				"role_arn": "arn:aws:iam::123456789012:role/sftpgo"},
+		},
+		"static keys": {
+			values: map[string]string{"access_key": "key", "access_secret": "secret"},
+		},
+		"static keys and role arn": {
+			values: map[string]string{"access_key": "key", "access_secret": "secret",
+				"role_arn": "arn:aws:iam::123456789012:role/sftpgo"},
+			expectWarning: true,
+		},
+		"access key only": {
+			values:    map[string]string{"access_key": "key"},
+			errorPath: "access_secret",
+		},
+		"access secret only": {
+			values:    map[string]string{"access_secret": "secret", "role_arn": "arn:aws:iam::123456789012:role/sftpgo"},
+			errorPath: "access_key",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				fsType := objType.AttributeTypes["filesystem"].(tftypes.Object)
+				s3Type := fsType.AttributeTypes["s3config"].(tftypes.Object)
+				s3Values := map[string]tftypes.Value{
+					"bucket": tftypes.NewValue(tftypes.String, "bucket"),
+				}
+				for k, v := range test.values {
+					s3Values[k] = tftypes.NewValue(tftypes.String, v)
+				}
+				return map[string]tftypes.Value{
+					"username": tftypes.NewValue(tftypes.String, "user"),
+					"filesystem": getTestObject(fsType, map[string]tftypes.Value{
+						"provider": tftypes.NewValue(tftypes.Number, 1),
+						"s3config": getTestObject(s3Type, s3Values),
+					}),
+				}
+			})
+			s3Path := path.Root("filesystem").AtName("s3config")
+			checkConfigErrorPath(t, diags, s3Path.AtName(test.errorPath), test.errorPath != "")
+			if test.expectWarning {
+				require.Equal(t, 1, diags.WarningsCount(), "unexpected diagnostics: %v", diags)
+				withPath, ok := diags.Warnings()[0].(diag.DiagnosticWithPath)
+				require.True(t, ok)
+				require.True(t, withPath.Path().Equal(s3Path.AtName("role_arn")))
+			} else {
+				require.Equal(t, 0, diags.WarningsCount(), "unexpected diagnostics: %v", diags)
+			}
+		})
+	}
+}
+
+func TestSkipTLSVerifyValidation(t *testing.T) {
+	type testCase struct {
+		provider      int64
+		block         string
+		skipTLSVerify *bool
+	}
+	enabled := true
+	disabled := false
+	tests := map[string]testCase{
+		"s3 unset":     {provider: 1, block: "s3config"},
+		"s3 disabled":  {provider: 1, block: "s3config", skipTLSVerify: &disabled},
+		"s3 enabled":   {provider: 1, block: "s3config", skipTLSVerify: &enabled},
+		"http enabled": {provider: 6, block: "httpconfig", skipTLSVerify: &enabled},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		getFs := func(fsType tftypes.Object) tftypes.Value {
+			blockType := fsType.AttributeTypes[test.block].(tftypes.Object)
+			values := map[string]tftypes.Value{}
+			if test.block == "s3config" {
+				values["bucket"] = tftypes.NewValue(tftypes.String, "bucket")
+			} else {
+				values["endpoint"] = tftypes.NewValue(tftypes.String, "https://127.0.0.1:9999/api/v1")
+			}
+			if test.skipTLSVerify != nil {
+				values["skip_tls_verify"] = tftypes.NewValue(tftypes.Bool, *test.skipTLSVerify)
+			}
+			return getTestObject(fsType, map[string]tftypes.Value{
+				"provider": tftypes.NewValue(tftypes.Number, test.provider),
+				test.block: getTestObject(blockType, values),
+			})
+		}
+		checkWarning := func(t *testing.T, diags diag.Diagnostics, fsPath path.Path) {
+			require.False(t, diags.HasError(), "unexpected error: %v", diags)
+			if test.skipTLSVerify == nil || !*test.skipTLSVerify {
+				require.Equal(t, 0, diags.WarningsCount(), "unexpected diagnostics: %v", diags)
+				return
+			}
+			require.Equal(t, 1, diags.WarningsCount(), "unexpected diagnostics: %v", diags)
+			withPath, ok := diags.Warnings()[0].(diag.DiagnosticWithPath)
+			require.True(t, ok)
+			require.True(t, withPath.Path().Equal(fsPath.AtName(test.block).AtName("skip_tls_verify")))
+		}
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				return map[string]tftypes.Value{
+					"username":   tftypes.NewValue(tftypes.String, "user"),
+					"filesystem": getFs(objType.AttributeTypes["filesystem"].(tftypes.Object)),
+				}
+			})
+			checkWarning(t, diags, path.Root("filesystem"))
+
+			diags = validateResourceConfig(t, &groupResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				settingsType := objType.AttributeTypes["user_settings"].(tftypes.Object)
+				return map[string]tftypes.Value{
+					"name": tftypes.NewValue(tftypes.String, "group"),
+					"user_settings": getTestObject(settingsType, map[string]tftypes.Value{
+						"filesystem": getFs(settingsType.AttributeTypes["filesystem"].(tftypes.Object)),
+					}),
+				}
+			})
+			checkWarning(t, diags, path.Root("user_settings").AtName("filesystem"))
+		})
+	}
+}
+
+func TestS3EndpointValidation(t *testing.T) {
+	type testCase struct {
+		endpoint       string
+		forcePathStyle *bool
+		expectWarning  bool
+	}
+	enabled := true
+	disabled := false
+	tests := map[string]testCase{
+		"no endpoint":           {},
+		"aws endpoint":          {endpoint: "https://s3.eu-west-1.amazonaws.com"},
+		"aws china endpoint":    {endpoint: "https://s3.cn-north-1.amazonaws.com.cn"},
+		"aws without scheme":    {endpoint: "s3.us-east-1.amazonaws.com:443"},
+		"minio path style":      {endpoint: "http://127.0.0.1:9000", forcePathStyle: &enabled},
+		"minio":                 {endpoint: "http://127.0.0.1:9000", expectWarning: true},
+		"minio path style off":  {endpoint: "https://minio.example.com", forcePathStyle: &disabled, expectWarning: true},
+		"endpoint without host": {endpoint: "ceph.example.com:7480", expectWarning: true},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		getFs := func(fsType tftypes.Object) tftypes.Value {
+			s3Type := fsType.AttributeTypes["s3config"].(tftypes.Object)
+			values := map[string]tftypes.Value{
+				"bucket": tftypes.NewValue(tftypes.String, "bucket"),
+			}
+			if test.endpoint != "" {
+				values["endpoint"] = tftypes.NewValue(tftypes.String, test.endpoint)
+			}
+			if test.forcePathStyle != nil {
+				values["force_path_style"] = tftypes.NewValue(tftypes.Bool, *test.forcePathStyle)
+			}
+			return getTestObject(fsType, map[string]tftypes.Value{
+				"provider": tftypes.NewValue(tftypes.Number, 1),
+				"s3config": getTestObject(s3Type, values),
+			})
+		}
+		checkWarning := func(t *testing.T, diags diag.Diagnostics, fsPath path.Path) {
+			require.False(t, diags.HasError(), "unexpected error: %v", diags)
+			if !test.expectWarning {
+				require.Equal(t, 0, diags.WarningsCount(), "unexpected diagnostics: %v", diags)
+				return
+			}
+			require.Equal(t, 1, diags.WarningsCount(), "unexpected diagnostics: %v", diags)
+			withPath, ok := diags.Warnings()[0].(diag.DiagnosticWithPath)
+			require.True(t, ok)
+			require.True(t, withPath.Path().Equal(fsPath.AtName("s3config").AtName("endpoint")))
+		}
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				return map[string]tftypes.Value{
+					"username":   tftypes.NewValue(tftypes.String, "user"),
+					"filesystem": getFs(objType.AttributeTypes["filesystem"].(tftypes.Object)),
+				}
+			})
+			checkWarning(t, diags, path.Root("filesystem"))
+
+			diags = validateResourceConfig(t, &folderResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				return map[string]tftypes.Value{
+					"name":       tftypes.NewValue(tftypes.String, "folder"),
+					"filesystem": getFs(objType.AttributeTypes["filesystem"].(tftypes.Object)),
+				}
+			})
+			checkWarning(t, diags, path.Root("filesystem"))
+		})
+	}
+}
+
+func TestTLSUsernameValidation(t *testing.T) {
+	type testCase struct {
+		tlsUsername        string
+		deniedLoginMethods []string
+		expectWarning      bool
+	}
+	tests := map[string]testCase{
+		"tls username": {
+			tlsUsername: "CommonName",
+		},
+		"no tls username": {
+			deniedLoginMethods: []string{"TLSCertificate", "TLSCertificate+password"},
+		},
+		"one tls method denied": {
+			tlsUsername:        "CommonName",
+			deniedLoginMethods: []string{"password", "TLSCertificate"},
+		},
+		"all tls methods denied": {
+			tlsUsername:        "CommonName",
+			deniedLoginMethods: []string{"TLSCertificate+password", "TLSCertificate"},
+			expectWarning:      true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		getFilters := func(filtersType tftypes.Object) tftypes.Value {
+			values := map[string]tftypes.Value{}
+			if test.tlsUsername != "" {
+				values["tls_username"] = tftypes.NewValue(tftypes.String, test.tlsUsername)
+			}
+			if len(test.deniedLoginMethods) > 0 {
+				var methods []tftypes.Value
+				for _, m := range test.deniedLoginMethods {
+					methods = append(methods, tftypes.NewValue(tftypes.String, m))
+				}
+				values["denied_login_methods"] = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, methods)
+			}
+			return getTestObject(filtersType, values)
+		}
+		checkWarning := func(t *testing.T, diags diag.Diagnostics, filtersPath path.Path) {
+			require.False(t, diags.HasError(), "unexpected error: %v", diags)
+			if !test.expectWarning {
+				require.Equal(t, 0, diags.WarningsCount(), "unexpected diagnostics: %v", diags)
+				return
+			}
+			require.Equal(t, 1, diags.WarningsCount(), "unexpected diagnostics: %v", diags)
+			withPath, ok := diags.Warnings()[0].(diag.DiagnosticWithPath)
+			require.True(t, ok)
+			require.True(t, withPath.Path().Equal(filtersPath.AtName("tls_username")))
+		}
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				return map[string]tftypes.Value{
+					"username": tftypes.NewValue(tftypes.String, "user"),
+					"filters":  getFilters(objType.AttributeTypes["filters"].(tftypes.Object)),
+				}
+			})
+			checkWarning(t, diags, path.Root("filters"))
+
+			diags = validateResourceConfig(t, &groupResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				settingsType := objType.AttributeTypes["user_settings"].(tftypes.Object)
+				return map[string]tftypes.Value{
+					"name": tftypes.NewValue(tftypes.String, "group"),
+					"user_settings": getTestObject(settingsType, map[string]tftypes.Value{
+						"filters": getFilters(settingsType.AttributeTypes["filters"].(tftypes.Object)),
+					}),
+				}
+			})
+			checkWarning(t, diags, path.Root("user_settings").AtName("filters"))
+		})
+	}
+}
+
+func TestBandwidthLimitsValidation(t *testing.T) {
+	type testCase struct {
+		sources          [][]string
+		expectedWarnings []int
+	}
+	tests := map[string]testCase{
+		"distinct networks": {
+			sources: [][]string{{"192.168.1.0/24", "10.0.0.0/8"}, {"192.168.2.0/24", "2001:db8::/32"}},
+		},
+		"overlap in the same entry": {
+			sources: [][]string{{"192.168.0.0/16", "192.168.1.0/24"}},
+		},
+		"overlapping networks": {
+			sources:          [][]string{{"192.168.0.0/16"}, {"10.0.0.0/8", "192.168.1.0/24"}},
+			expectedWarnings: []int{1},
+		},
+		"same network": {
+			sources:          [][]string{{"2001:db8::/32"}, {"10.0.0.0/8"}, {"2001:db8::/32"}},
+			expectedWarnings: []int{2},
+		},
+		"invalid network": {
+			sources: [][]string{{"192.168.0.0/16"}, {"invalid"}},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				filtersType := objType.AttributeTypes["filters"].(tftypes.Object)
+				limitsType := filtersType.AttributeTypes["bandwidth_limits"].(tftypes.List)
+				var limits []tftypes.Value
+				for _, sources := range test.sources {
+					var networks []tftypes.Value
+					for _, source := range sources {
+						networks = append(networks, tftypes.NewValue(tftypes.String, source))
+					}
+					limits = append(limits, getTestObject(limitsType.ElementType.(tftypes.Object), map[string]tftypes.Value{
+						"sources":          tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, networks),
+						"upload_bandwidth": tftypes.NewValue(tftypes.Number, 100),
+					}))
+				}
+				return map[string]tftypes.Value{
+					"username": tftypes.NewValue(tftypes.String, "user"),
+					"filters": getTestObject(filtersType, map[string]tftypes.Value{
+						"bandwidth_limits": tftypes.NewValue(limitsType, limits),
+					}),
+				}
+			})
+			require.False(t, diags.HasError(), "unexpected error: %v", diags)
+			require.Equal(t, len(test.expectedWarnings), diags.WarningsCount(), "unexpected diagnostics: %v", diags)
+			for idx, entry := range test.expectedWarnings {
+				withPath, ok := diags.Warnings()[idx].(diag.DiagnosticWithPath)
+				require.True(t, ok)
+				require.True(t, withPath.Path().Equal(path.Root("filters").AtName("bandwidth_limits").AtListIndex(entry).AtName("sources")))
+				require.Equal(t, "Overlapping Bandwidth Limits", withPath.Summary())
+			}
+		})
+	}
+}
+
+func TestTwoFactorProtocolsValidation(t *testing.T) {
+	type testCase struct {
+		twoFactorProtocols []string
+		deniedProtocols    []string
+		expectedWarnings   int
+	}
+	tests := map[string]testCase{
+		"two factor only": {
+			twoFactorProtocols: []string{"FTP"},
+		},
+		"denied only": {
+			deniedProtocols: []string{"FTP"},
+		},
+		"different protocols": {
+			twoFactorProtocols: []string{"SSH", "HTTP"},
+			deniedProtocols:    []string{"FTP", "DAV"},
+		},
+		"denied two factor protocol": {
+			twoFactorProtocols: []string{"FTP"},
+			deniedProtocols:    []string{"FTP"},
+			expectedWarnings:   1,
+		},
+		"denied two factor protocols": {
+			twoFactorProtocols: []string{"SSH", "FTP", "HTTP"},
+			deniedProtocols:    []string{"HTTP", "DAV", "FTP"},
+			expectedWarnings:   2,
+		},
+	}
+
+	getList := func(values []string) tftypes.Value {
+		listType := tftypes.List{ElementType: tftypes.String}
+		if len(values) == 0 {
+			return tftypes.NewValue(listType, nil)
+		}
+		var elems []tftypes.Value
+		for _, v := range values {
+			elems = append(elems, tftypes.NewValue(tftypes.String, v))
+		}
+		return tftypes.NewValue(listType, elems)
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		getFilters := func(filtersType tftypes.Object) tftypes.Value {
+			return getTestObject(filtersType, map[string]tftypes.Value{
+				"two_factor_protocols": getList(test.twoFactorProtocols),
+				"denied_protocols":     getList(test.deniedProtocols),
+			})
+		}
+		checkWarnings := func(t *testing.T, diags diag.Diagnostics, filtersPath path.Path) {
+			require.False(t, diags.HasError(), "unexpected error: %v", diags)
+			require.Equal(t, test.expectedWarnings, diags.WarningsCount(), "unexpected diagnostics: %v", diags)
+			for _, warning := range diags.Warnings() {
+				withPath, ok := warning.(diag.DiagnosticWithPath)
+				require.True(t, ok)
+				require.True(t, withPath.Path().Equal(filtersPath.AtName("two_factor_protocols")))
+			}
+		}
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				return map[string]tftypes.Value{
+					"username": tftypes.NewValue(tftypes.String, "user"),
+					"filters":  getFilters(objType.AttributeTypes["filters"].(tftypes.Object)),
+				}
+			})
+			checkWarnings(t, diags, path.Root("filters"))
+
+			diags = validateResourceConfig(t, &groupResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				settingsType := objType.AttributeTypes["user_settings"].(tftypes.Object)
+				return map[string]tftypes.Value{
+					"name": tftypes.NewValue(tftypes.String, "group"),
+					"user_settings": getTestObject(settingsType, map[string]tftypes.Value{
+						"filters": getFilters(settingsType.AttributeTypes["filters"].(tftypes.Object)),
+					}),
+				}
+			})
+			checkWarnings(t, diags, path.Root("user_settings").AtName("filters"))
+		})
+	}
+}
+
+func TestAzBlobEmulatorValidation(t *testing.T) {
+	type testCase struct {
+		values      map[string]tftypes.Value
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"emulator disabled without endpoint": {
+			values: map[string]tftypes.Value{
+				"use_emulator": tftypes.NewValue(tftypes.Bool, false),
+			},
+		},
+		"emulator disabled with endpoint": {
+			values: map[string]tftypes.Value{
+				"use_emulator": tftypes.NewValue(tftypes.Bool, false),
+				"endpoint":     tftypes.NewValue(tftypes.String, "blob.core.windows.net"),
+			},
+		},
+		"emulator with valid endpoint": {
+			values: map[string]tftypes.Value{
+				"use_emulator": tftypes.NewValue(tftypes.Bool, true),
+				"endpoint":     tftypes.NewValue(tftypes.String, "http://127.0.0.1:10000"),
+			},
+		},
+		"emulator without endpoint": {
+			values: map[string]tftypes.Value{
+				"use_emulator": tftypes.NewValue(tftypes.Bool, true),
+			},
+			expectError: true,
+		},
+		"emulator with endpoint without protocol": {
+			values: map[string]tftypes.Value{
+				"use_emulator": tftypes.NewValue(tftypes.Bool, true),
+				"endpoint":     tftypes.NewValue(tftypes.String, "127.0.0.1:10000"),
+			},
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				fsType := objType.AttributeTypes["filesystem"].(tftypes.Object)
+				azValues := map[string]tftypes.Value{
+					"container": tftypes.NewValue(tftypes.String, "container"),
+				}
+				for k, v := range test.values {
+					azValues[k] = v
+				}
+				return map[string]tftypes.Value{
+					"username": tftypes.NewValue(tftypes.String, "user"),
+					"filesystem": getTestObject(fsType, map[string]tftypes.Value{
+						"provider":     tftypes.NewValue(tftypes.Number, 3),
+						"azblobconfig": getTestObject(fsType.AttributeTypes["azblobconfig"].(tftypes.Object), azValues),
+					}),
+				}
+			})
+			checkConfigErrorPath(t, diags, path.Root("filesystem").AtName("azblobconfig").AtName("endpoint"),
+				test.expectError)
+		})
+	}
+}
+
+func TestUserGroupsValidation(t *testing.T) {
+	type group struct {
+		name      string
+		groupType int64
+	}
+	type testCase struct {
+		groups      []group
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"no groups": {},
+		"one primary group": {
+			groups: []group{{"group1", 1}, {"group2", 2}, {"group3", 3}},
+		},
+		"secondary groups": {
+			groups: []group{{"group1", 2}, {"group2", 2}},
+		},
+		"two primary groups": {
+			groups:      []group{{"group1", 1}, {"group2", 2}, {"group3", 1}},
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			diags := validateResourceConfig(t, &userResource{}, func(objType tftypes.Object) map[string]tftypes.Value {
+				fsType := objType.AttributeTypes["filesystem"].(tftypes.Object)
+				groupsType := objType.AttributeTypes["groups"].(tftypes.List)
+				groupType := groupsType.ElementType.(tftypes.Object)
+				values := map[string]tftypes.Value{
+					"username":   tftypes.NewValue(tftypes.String, "user"),
+					"filesystem": getFilesystemTestObject(fsType, 0),
+				}
+				if len(test.groups) > 0 {
+					var groups []tftypes.Value
+					for _, g := range test.groups {
+						groups = append(groups, getTestObject(groupType, map[string]tftypes.Value{
+							"name": tftypes.NewValue(tftypes.String, g.name),
+							"type": tftypes.NewValue(tftypes.Number, g.groupType),
+						}))
+					}
+					values["groups"] = tftypes.NewValue(groupsType, groups)
+				}
+				return values
+			})
+			checkConfigErrorPath(t, diags, path.Root("groups"), test.expectError)
+		})
+	}
+}
+
+func TestUserGroupTypeValidator(t *testing.T) {
+	ctx := context.Background()
+	r := &userResource{}
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	groups := schemaResp.Schema.Attributes["groups"].(schema.ListNestedAttribute)
+	groupType := groups.NestedObject.Attributes["type"].(schema.Int64Attribute)
+	for val, expectError := range map[int64]bool{0: true, 1: false, 2: false, 3: false, 5: true} {
+		request := validator.Int64Request{
+			Path:        path.Root("groups").AtListIndex(0).AtName("type"),
+			ConfigValue: types.Int64Value(val),
+		}
+		var diags diag.Diagnostics
+		for _, v := range groupType.Validators {
+			response := validator.Int64Response{}
+			v.ValidateInt64(ctx, request, &response)
+			diags.Append(response.Diagnostics...)
+		}
+		require.Equal(t, expectError, diags.HasError(), "unexpected result for type %d: %v", val, diags)
+	}
+}